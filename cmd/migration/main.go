@@ -8,6 +8,7 @@ import (
 
 	"github.com/mohammadne/porsesh/internal/config"
 	"github.com/mohammadne/porsesh/pkg/databases/postgres"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
 )
 
 //go:embed schemas/*.sql
@@ -16,14 +17,15 @@ var files embed.FS
 func main() {
 	direction := flag.String("direction", "", "Either 'up' or 'down'")
 	environmentRaw := flag.String("environment", "", "The environment (default: local)")
+	configPath := flag.String("config", "", "The optional YAML or TOML config file, the environment variables override it")
 	flag.Parse() // Parse the command-line flags
 
 	var cfg Config
-	if err := config.Load(&cfg, *environmentRaw); err != nil {
+	if err := config.Load(&cfg, *environmentRaw, *configPath); err != nil {
 		log.Panicf("failed to load config: \n%v", err)
 	}
 
-	db, err := postgres.Open(cfg.Postgres, config.Namespace, config.System)
+	db, err := postgres.Open(cfg.Postgres, metrics.NewRegistry(), config.Namespace, config.System)
 	if err != nil {
 		log.Fatalf("error connecting to postgres database\n%v", err)
 	}
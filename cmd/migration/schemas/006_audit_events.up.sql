@@ -0,0 +1,14 @@
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id BIGINT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id, created_at); -- who did what
+CREATE INDEX idx_audit_events_target ON audit_events (target_type, target_id, created_at); -- what happened to it
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
@@ -0,0 +1,4 @@
+-- 
+DROP TABLE IF EXISTS poll_participants;
+DROP INDEX IF EXISTS idx_polls_visibility_created_at;
+ALTER TABLE polls DROP COLUMN IF EXISTS visibility;
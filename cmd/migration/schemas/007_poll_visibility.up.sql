@@ -0,0 +1,13 @@
+ALTER TABLE polls ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'unlisted', 'private'));
+
+CREATE INDEX idx_polls_visibility_created_at ON polls (visibility, created_at); -- populate public feed
+
+CREATE TABLE poll_participants (
+    poll_id BIGINT REFERENCES polls(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    added_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (poll_id, user_id)
+);
+
+CREATE INDEX idx_poll_participants_user_id ON poll_participants (user_id); -- populate invited polls in feed
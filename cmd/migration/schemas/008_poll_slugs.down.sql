@@ -0,0 +1,3 @@
+-- 
+DROP INDEX IF EXISTS idx_polls_slug;
+ALTER TABLE polls DROP COLUMN IF EXISTS slug;
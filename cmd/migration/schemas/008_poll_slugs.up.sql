@@ -0,0 +1,15 @@
+ALTER TABLE polls ADD COLUMN slug TEXT;
+
+-- backfill existing polls the way pkg/slugs generates them: base62 and never all digits (the first
+-- character is a letter) so resolvePoll doesn't take them as the numeric ids. The sub-select refers
+-- to the row, otherwise it would be evaluated once for all of the polls.
+UPDATE polls SET slug = (
+    SELECT substr('ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz', 1 + floor(random() * 52)::INT, 1) ||
+        string_agg(substr('0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz', 1 + floor(random() * 62)::INT, 1), '')
+    FROM generate_series(2, 10)
+    WHERE polls.id IS NOT NULL
+) WHERE slug IS NULL;
+
+ALTER TABLE polls ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_polls_slug ON polls (slug);
@@ -0,0 +1,4 @@
+ALTER TABLE polls ADD COLUMN results_visibility TEXT NOT NULL DEFAULT 'always'
+    CHECK (results_visibility IN ('always', 'after_vote', 'after_close', 'owner'));
+
+ALTER TABLE polls ADD COLUMN closes_at TIMESTAMP; -- NULL means never closes
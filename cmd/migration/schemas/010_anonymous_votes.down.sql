@@ -0,0 +1,5 @@
+-- 
+DROP TABLE IF EXISTS anonymous_daily_actions;
+DROP TABLE IF EXISTS anonymous_tallies;
+DROP TABLE IF EXISTS anonymous_ballots;
+ALTER TABLE polls DROP COLUMN IF EXISTS anonymous;
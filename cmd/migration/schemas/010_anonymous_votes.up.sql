@@ -0,0 +1,26 @@
+ALTER TABLE polls ADD COLUMN anonymous BOOLEAN NOT NULL DEFAULT false;
+
+-- who has acted on an anonymous poll, identified by sha256(voter_key:poll_id) where the voter key is
+-- the HMAC of the user id by the server-side pepper, so the hashes can't be reversed from the database
+CREATE TABLE anonymous_ballots (
+    poll_id BIGINT REFERENCES polls(id) ON DELETE CASCADE,
+    voter_hash TEXT NOT NULL,
+    PRIMARY KEY (poll_id, voter_hash)
+);
+
+-- what has been chosen on an anonymous poll, without any per-vote row to link back to the voter
+CREATE TABLE anonymous_tallies (
+    option_id BIGINT PRIMARY KEY REFERENCES poll_options(id) ON DELETE CASCADE,
+    poll_id BIGINT REFERENCES polls(id) ON DELETE CASCADE,
+    votes BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_anonymous_tallies_poll_id ON anonymous_tallies (poll_id);
+
+-- anonymous ballots still count towards the daily vote limits of the user
+CREATE TABLE anonymous_daily_actions (
+    user_id BIGINT NOT NULL,
+    acted_on DATE NOT NULL DEFAULT CURRENT_DATE,
+    actions BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, acted_on)
+);
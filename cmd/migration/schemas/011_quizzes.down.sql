@@ -0,0 +1,10 @@
+-- 
+DROP INDEX IF EXISTS idx_votes_poll_id;
+ALTER TABLE votes DROP COLUMN IF EXISTS points;
+ALTER TABLE votes DROP COLUMN IF EXISTS correct;
+ALTER TABLE poll_options DROP COLUMN IF EXISTS correct;
+DROP INDEX IF EXISTS idx_polls_quiz_set;
+ALTER TABLE polls DROP COLUMN IF EXISTS quiz_set;
+ALTER TABLE polls DROP COLUMN IF EXISTS time_limit_seconds;
+ALTER TABLE polls DROP CONSTRAINT IF EXISTS polls_type_check;
+ALTER TABLE polls DROP COLUMN IF EXISTS type;
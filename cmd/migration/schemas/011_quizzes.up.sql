@@ -0,0 +1,13 @@
+ALTER TABLE polls ADD COLUMN type TEXT NOT NULL DEFAULT 'single';
+ALTER TABLE polls ADD CONSTRAINT polls_type_check CHECK (type IN ('single', 'quiz'));
+ALTER TABLE polls ADD COLUMN time_limit_seconds INT NOT NULL DEFAULT 0; -- 0 means no time limit
+ALTER TABLE polls ADD COLUMN quiz_set TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_polls_quiz_set ON polls (quiz_set) WHERE type = 'quiz'; -- quiz set leaderboards
+
+ALTER TABLE poll_options ADD COLUMN correct BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE votes ADD COLUMN correct BOOLEAN; -- NULL for the non-quiz polls
+ALTER TABLE votes ADD COLUMN points INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_votes_poll_id ON votes (poll_id); -- leaderboards and voter lists
@@ -0,0 +1,6 @@
+-- 
+DROP TABLE IF EXISTS survey_answers;
+DROP TABLE IF EXISTS survey_responses;
+DROP TABLE IF EXISTS survey_rules;
+DROP TABLE IF EXISTS survey_questions;
+DROP TABLE IF EXISTS surveys;
@@ -0,0 +1,41 @@
+CREATE TABLE surveys (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    title TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE survey_questions (
+    survey_id BIGINT REFERENCES surveys(id) ON DELETE CASCADE,
+    position INT NOT NULL, -- 1 based order of the question
+    poll_id BIGINT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    PRIMARY KEY (survey_id, position),
+    UNIQUE (survey_id, poll_id)
+);
+
+-- skip logic, choosing the option (its sort) on the question jumps to another one (0 ends the survey)
+CREATE TABLE survey_rules (
+    survey_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    option_sort INT NOT NULL,
+    jump_to INT NOT NULL,
+    PRIMARY KEY (survey_id, position, option_sort),
+    FOREIGN KEY (survey_id, position) REFERENCES survey_questions(survey_id, position) ON DELETE CASCADE
+);
+
+CREATE TABLE survey_responses (
+    survey_id BIGINT REFERENCES surveys(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    submitted_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (survey_id, user_id)
+);
+
+-- the questions on the path of every response, answered is false for the skipped ones
+CREATE TABLE survey_answers (
+    survey_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    answered BOOLEAN NOT NULL,
+    PRIMARY KEY (survey_id, user_id, position),
+    FOREIGN KEY (survey_id, user_id) REFERENCES survey_responses(survey_id, user_id) ON DELETE CASCADE
+);
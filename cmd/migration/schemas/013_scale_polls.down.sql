@@ -0,0 +1,9 @@
+-- 
+-- the polls are kept, the rollback fails while any of them exists (kept on one line for the splitter)
+DO $$ BEGIN IF EXISTS (SELECT 1 FROM polls WHERE type = 'scale') THEN RAISE EXCEPTION 'scale polls exist, remove them before rolling back'; END IF; END $$;
+DROP INDEX IF EXISTS idx_votes_poll_id_value;
+ALTER TABLE votes DROP COLUMN IF EXISTS skipped;
+ALTER TABLE votes DROP COLUMN IF EXISTS value;
+DROP TABLE IF EXISTS poll_scales;
+ALTER TABLE polls DROP CONSTRAINT IF EXISTS polls_type_check;
+ALTER TABLE polls ADD CONSTRAINT polls_type_check CHECK (type IN ('single', 'quiz'));
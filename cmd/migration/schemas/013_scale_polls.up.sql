@@ -0,0 +1,17 @@
+ALTER TABLE polls DROP CONSTRAINT polls_type_check;
+ALTER TABLE polls ADD CONSTRAINT polls_type_check CHECK (type IN ('single', 'quiz', 'scale'));
+
+CREATE TABLE poll_scales (
+    poll_id BIGINT PRIMARY KEY REFERENCES polls(id) ON DELETE CASCADE,
+    min INT NOT NULL,
+    max INT NOT NULL,
+    step INT NOT NULL,
+    labels JSONB NOT NULL DEFAULT '{}', -- value to label
+    CHECK (max > min AND step > 0)
+);
+
+ALTER TABLE votes ADD COLUMN value INT; -- the numeric answer of the scale polls
+ALTER TABLE votes ADD COLUMN skipped BOOLEAN NOT NULL DEFAULT false;
+UPDATE votes SET skipped = true WHERE option_id IS NULL; -- a missing option used to be the only skip
+
+CREATE INDEX idx_votes_poll_id_value ON votes (poll_id, value) WHERE value IS NOT NULL; -- scale distributions
@@ -0,0 +1,6 @@
+-- 
+DROP INDEX IF EXISTS idx_votes_poll_id_other_text;
+DELETE FROM votes WHERE other_text IS NOT NULL;
+ALTER TABLE votes DROP COLUMN IF EXISTS other_hidden;
+ALTER TABLE votes DROP COLUMN IF EXISTS other_text;
+ALTER TABLE polls DROP COLUMN IF EXISTS other_max_length;
@@ -0,0 +1,6 @@
+ALTER TABLE polls ADD COLUMN other_max_length INT NOT NULL DEFAULT 0; -- 0 means no "other" choice
+
+ALTER TABLE votes ADD COLUMN other_text TEXT; -- the free-text answer of the "other" choice
+ALTER TABLE votes ADD COLUMN other_hidden BOOLEAN NOT NULL DEFAULT false; -- hidden by the moderation
+
+CREATE INDEX idx_votes_poll_id_other_text ON votes (poll_id, lower(trim(other_text))) WHERE other_text IS NOT NULL;
@@ -0,0 +1,10 @@
+-- 
+-- the polls are kept, the rollback fails while any of them exists (kept on one line for the splitter)
+DO $$ BEGIN IF EXISTS (SELECT 1 FROM polls WHERE type = 'schedule') THEN RAISE EXCEPTION 'schedule polls exist, remove them before rolling back'; END IF; END $$;
+DROP TABLE IF EXISTS schedule_availabilities;
+ALTER TABLE poll_options DROP CONSTRAINT IF EXISTS poll_options_slot_check;
+ALTER TABLE poll_options DROP COLUMN IF EXISTS time_zone;
+ALTER TABLE poll_options DROP COLUMN IF EXISTS ends_at;
+ALTER TABLE poll_options DROP COLUMN IF EXISTS starts_at;
+ALTER TABLE polls DROP CONSTRAINT IF EXISTS polls_type_check;
+ALTER TABLE polls ADD CONSTRAINT polls_type_check CHECK (type IN ('single', 'quiz', 'scale'));
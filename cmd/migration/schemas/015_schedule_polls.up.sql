@@ -0,0 +1,21 @@
+ALTER TABLE polls DROP CONSTRAINT polls_type_check;
+ALTER TABLE polls ADD CONSTRAINT polls_type_check CHECK (type IN ('single', 'quiz', 'scale', 'schedule'));
+
+-- the time range of the options of the schedule polls
+ALTER TABLE poll_options ADD COLUMN starts_at TIMESTAMPTZ;
+ALTER TABLE poll_options ADD COLUMN ends_at TIMESTAMPTZ;
+ALTER TABLE poll_options ADD COLUMN time_zone TEXT NOT NULL DEFAULT ''; -- IANA name the slot is shown in
+ALTER TABLE poll_options ADD CONSTRAINT poll_options_slot_check CHECK (
+    (starts_at IS NULL AND ends_at IS NULL) OR (starts_at IS NOT NULL AND ends_at > starts_at)
+);
+
+-- the availability of the voters of the schedule polls for every slot, the vote itself stays in votes
+CREATE TABLE schedule_availabilities (
+    poll_id BIGINT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    option_id BIGINT NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    availability TEXT NOT NULL CHECK (availability IN ('yes', 'if_need_be', 'no')),
+    PRIMARY KEY (option_id, user_id)
+);
+
+CREATE INDEX idx_schedule_availabilities_poll_id ON schedule_availabilities (poll_id, user_id);
@@ -0,0 +1,7 @@
+-- 
+-- the polls are kept, the rollback fails while any of them exists (kept on one line for the splitter)
+DO $$ BEGIN IF EXISTS (SELECT 1 FROM polls WHERE published_at IS NULL) THEN RAISE EXCEPTION 'unpublished polls exist, publish or remove them before rolling back'; END IF; END $$;
+DROP INDEX IF EXISTS idx_polls_publish_at;
+DROP INDEX IF EXISTS idx_polls_published_at;
+ALTER TABLE polls DROP COLUMN IF EXISTS published_at;
+ALTER TABLE polls DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,6 @@
+ALTER TABLE polls ADD COLUMN publish_at TIMESTAMPTZ; -- the scheduled publish time of the drafts
+ALTER TABLE polls ADD COLUMN published_at TIMESTAMPTZ; -- NULL while the poll is a draft
+UPDATE polls SET published_at = created_at; -- the polls used to be published on creation
+
+CREATE INDEX idx_polls_published_at ON polls (published_at DESC) WHERE published_at IS NOT NULL; -- feeds
+CREATE INDEX idx_polls_publish_at ON polls (publish_at) WHERE published_at IS NULL AND publish_at IS NOT NULL; -- scheduler
@@ -0,0 +1,4 @@
+-- 
+ALTER TABLE poll_options DROP COLUMN IF EXISTS attachment_id;
+ALTER TABLE polls DROP COLUMN IF EXISTS attachment_id;
+DROP TABLE IF EXISTS attachments;
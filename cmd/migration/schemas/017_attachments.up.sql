@@ -0,0 +1,14 @@
+CREATE TABLE attachments (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL, -- the uploader, only they can attach it
+    key TEXT NOT NULL UNIQUE, -- of the blob store
+    thumbnail_key TEXT, -- images only
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE polls ADD COLUMN attachment_id BIGINT REFERENCES attachments(id);
+ALTER TABLE poll_options ADD COLUMN attachment_id BIGINT REFERENCES attachments(id);
@@ -0,0 +1,6 @@
+-- 
+DROP INDEX IF EXISTS idx_polls_search_vector;
+ALTER TABLE polls DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE polls DROP CONSTRAINT IF EXISTS polls_description_check;
+ALTER TABLE polls DROP COLUMN IF EXISTS description_html;
+ALTER TABLE polls DROP COLUMN IF EXISTS description;
@@ -0,0 +1,8 @@
+ALTER TABLE polls ADD COLUMN description TEXT NOT NULL DEFAULT ''; -- the Markdown source
+ALTER TABLE polls ADD COLUMN description_html TEXT NOT NULL DEFAULT ''; -- rendered and sanitized on write
+ALTER TABLE polls ADD CONSTRAINT polls_description_check CHECK (char_length(description) <= 4000);
+ALTER TABLE polls ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', description), 'B')
+) STORED;
+
+CREATE INDEX idx_polls_search_vector ON polls USING GIN (search_vector);
@@ -0,0 +1,2 @@
+-- 
+ALTER TABLE polls DROP COLUMN IF EXISTS shuffle_options;
@@ -0,0 +1 @@
+ALTER TABLE polls ADD COLUMN shuffle_options BOOLEAN NOT NULL DEFAULT false; -- per-voter order of the options
@@ -0,0 +1,4 @@
+-- 
+DROP TABLE IF EXISTS poll_participant_groups;
+DROP TABLE IF EXISTS user_group_members;
+DROP TABLE IF EXISTS user_groups;
@@ -0,0 +1,26 @@
+-- the named lists of users, the owners invite them to their private polls at once
+CREATE TABLE user_groups (
+    id BIGSERIAL PRIMARY KEY,
+    owner_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (owner_id, name)
+);
+
+CREATE TABLE user_group_members (
+    group_id BIGINT REFERENCES user_groups(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    added_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX idx_user_group_members_user_id ON user_group_members (user_id); -- populate invited polls in feed
+
+CREATE TABLE poll_participant_groups (
+    poll_id BIGINT REFERENCES polls(id) ON DELETE CASCADE,
+    group_id BIGINT REFERENCES user_groups(id) ON DELETE CASCADE,
+    added_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (poll_id, group_id)
+);
+
+CREATE INDEX idx_poll_participant_groups_group_id ON poll_participant_groups (group_id);
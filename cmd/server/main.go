@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -15,22 +18,34 @@ import (
 	"github.com/mohammadne/porsesh/internal/config"
 	"github.com/mohammadne/porsesh/internal/repository/storage"
 	"github.com/mohammadne/porsesh/internal/usecases"
+	"github.com/mohammadne/porsesh/pkg/blobs"
 	"github.com/mohammadne/porsesh/pkg/databases/postgres"
+	"github.com/mohammadne/porsesh/pkg/databases/redis"
+	"github.com/mohammadne/porsesh/pkg/observability/health"
 	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
 )
 
 func main() {
 	monitorPort := flag.Int("monitor-port", 8001, "The server port which handles monitoring endpoints (default: 8001)")
 	requestPort := flag.Int("request-port", 8002, "The server port which handles http requests (default: 8002)")
+	publishInterval := flag.Duration("publish-interval", 30*time.Second, "The interval of publishing the scheduled polls (default: 30s)")
+	healthCacheTTL := flag.Duration("health-cache-ttl", 2*time.Second, "The duration the readiness report is reused for (default: 2s)")
 	environmentRaw := flag.String("environment", "", "The environment (default: local)")
+	configPath := flag.String("config", "", "The optional YAML or TOML config file, the environment variables override it")
 	flag.Parse() // Parse the command-line flags
 
+	if err := validPorts(*monitorPort, *requestPort); err != nil {
+		log.Fatalf("invalid flags: \n%v", err)
+	}
+
 	var cfg Config
-	if err := config.Load(&cfg, *environmentRaw); err != nil {
+	if err := config.Load(&cfg, *environmentRaw, *configPath); err != nil {
 		log.Panicf("failed to load config: \n%v", err)
 	}
 
-	logger, err := logger.New(cfg.Logger)
+	logger, level, err := logger.New(cfg.Logger, config.System, cmd.Version)
 	if err != nil {
 		log.Fatalf("failed to initialize logger: \n%v", err)
 	}
@@ -43,31 +58,115 @@ func main() {
 		}
 	}
 
-	postgres, err := postgres.Open(cfg.Postgres, config.Namespace, config.System)
+	shutdownTracing, err := tracing.New(cfg.Tracing, config.System, cmd.Version)
+	if err != nil {
+		log.Fatalf("failed to initialize tracing: \n%v", err)
+	}
+
+	registry := metrics.NewRegistry() // served by the monitoring server
+
+	postgres, err := postgres.Open(cfg.Postgres, registry, config.Namespace, config.System)
 	if err != nil {
 		log.Fatalf("failed to initialize Postgres: \n%v", err)
 	}
 
+	// nothing is cached in Redis yet, the traced client is opened here so the caches only need to take it once
+	// they are introduced. Until then it's optional, the server starts without it if it's unset or unreachable.
+	var redisClient *redis.Redis
+	if len(cfg.Redis.Address) == 0 {
+		logger.Info("redis is not configured, starting without it")
+	} else if redisClient, err = redis.Open(cfg.Redis); err != nil {
+		logger.Warn("failed to initialize Redis, starting without it", zap.Error(err))
+	}
+
+	healthChecks, err := health.New(registry, config.Namespace, config.System, *healthCacheTTL)
+	if err != nil {
+		log.Fatalf("failed to initialize health: \n%v", err)
+	}
+	healthChecks.Register("postgres", health.Critical, postgresHealthTimeout, postgres.PingContext)
+	if redisClient != nil {
+		healthChecks.Register("redis", health.NonCritical, redisHealthTimeout, redisClient.Check)
+	}
+
+	blobStore, err := blobs.New(cfg.Blobs)
+	if err != nil {
+		log.Fatalf("failed to initialize blob store: \n%v", err)
+	}
+
 	pollsStorage := storage.NewPools(zap.NewNop(), postgres)
 	tagsStorage := storage.NewTags(zap.NewNop(), postgres)
 	votesStorage := storage.NewVotes(zap.NewNop(), postgres)
+	auditEventsStorage := storage.NewAuditEvents(zap.NewNop(), postgres)
+	surveysStorage := storage.NewSurveys(zap.NewNop(), postgres)
+	groupsStorage := storage.NewGroups(zap.NewNop(), postgres)
 
 	// usecases
-	feeds := usecases.NewFeeds(logger)
-	pools := usecases.NewPolls(logger, pollsStorage, tagsStorage, votesStorage)
+	usecasesMetrics, err := usecases.NewMetrics(registry, config.Namespace, config.System)
+	if err != nil {
+		log.Fatalf("failed to initialize usecases metrics: \n%v", err)
+	}
+
+	auditor := usecases.NewAuditor(logger, auditEventsStorage)
+	moderator := usecases.Moderators() // no moderation hooks are configured yet
+	feeds := usecases.NewFeeds(cfg.Polls, logger, usecasesMetrics, pollsStorage, tagsStorage, votesStorage)
+	pools := usecases.NewPolls(cfg.Polls, logger, usecasesMetrics, auditor, moderator, pollsStorage, tagsStorage, votesStorage, groupsStorage)
+	surveys := usecases.NewSurveys(cfg.Polls, logger, usecasesMetrics, auditor, moderator, pollsStorage, tagsStorage, votesStorage, groupsStorage, surveysStorage)
+	attachments := usecases.NewAttachments(logger, auditor, blobStore, pollsStorage)
+	groups := usecases.NewGroups(logger, auditor, groupsStorage)
+	scheduler := usecases.NewScheduler(logger, auditor, pollsStorage)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 	var wg sync.WaitGroup
 
 	wg.Add(1)
-	go http.New(logger, feeds, pools).Serve(ctx, &wg, *monitorPort, *requestPort)
+	go http.New(cfg.HTTP, logger, level, &cfg, registry, healthChecks, auditor, feeds, pools, surveys, attachments, groups).Serve(ctx, &wg, *monitorPort, *requestPort)
+
+	wg.Add(1)
+	go scheduler.Serve(ctx, &wg, *publishInterval)
 
 	<-ctx.Done()
 	wg.Wait()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil { // flushes the buffered spans
+		logger.Error("error shutdown tracing", zap.Error(err))
+	}
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			logger.Error("error closing redis", zap.Error(err))
+		}
+	}
+	_ = logger.Sync() // flushes the buffered sinks, the error of syncing the stdout is meaningless
 }
 
+// validPorts checks the ports of the servers are in range and apart, before any of the dependencies is opened
+func validPorts(monitorPort, requestPort int) error {
+	var errs []error
+	if monitorPort < 1 || monitorPort > 65535 {
+		errs = append(errs, fmt.Errorf("monitor-port %d is out of range, expected 1 to 65535", monitorPort))
+	}
+	if requestPort < 1 || requestPort > 65535 {
+		errs = append(errs, fmt.Errorf("request-port %d is out of range, expected 1 to 65535", requestPort))
+	}
+	if monitorPort == requestPort {
+		errs = append(errs, fmt.Errorf("monitor-port and request-port are both %d", monitorPort))
+	}
+	return errors.Join(errs...)
+}
+
+const (
+	postgresHealthTimeout = time.Second
+	redisHealthTimeout    = time.Second
+)
+
 type Config struct {
 	Logger   *logger.Config   `required:"true"`
 	Postgres *postgres.Config `required:"true"`
+	Redis    *redis.Config    // optional until something is cached in it
+	Blobs    *blobs.Config    `required:"true"`
+	Tracing  *tracing.Config  `required:"true"`
+	HTTP     *http.Config     `required:"true"`
+	Polls    *usecases.Config `required:"true"`
 }
@@ -0,0 +1,20 @@
+package http
+
+import "fmt"
+
+type Config struct {
+	// AdminToken is the shared secret of the admin endpoints on the monitor port, given by the X-Admin-Token
+	// header. If it's empty, the admin endpoints are only served to the loopback addresses.
+	AdminToken string `split_words:"true" secret:"true"`
+
+	// AccessLogSampling is the ratio of the successful requests which are written to the access log,
+	// the failed ones (4xx and 5xx) are always written
+	AccessLogSampling float64 `split_words:"true" default:"1"`
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.AccessLogSampling < 0 || cfg.AccessLogSampling > 1 {
+		return fmt.Errorf("access log sampling %v is out of [0, 1]", cfg.AccessLogSampling)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/mohammadne/porsesh/cmd"
+	"github.com/mohammadne/porsesh/internal/api/http/models"
+	"github.com/mohammadne/porsesh/internal/config"
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/usecases"
+)
+
+// NewAdmin serves the runtime operations, the router has to be guarded by the caller.
+// The settings is the effective config, it's served with its secrets redacted.
+// The changes are recorded by the auditor.
+func NewAdmin(router fiber.Router, logger *zap.Logger, level zap.AtomicLevel, settings any, auditor usecases.Auditor) {
+	handler := &admin{
+		logger:   logger,
+		level:    level,
+		settings: config.Redact(settings),
+		auditor:  auditor,
+	}
+
+	router.Get("/loglevel", handler.getLogLevel)
+	router.Put("/loglevel", handler.setLogLevel)
+	router.Get("/buildinfo", handler.buildInfo)
+	router.Get("/config", handler.config)
+}
+
+type admin struct {
+	logger   *zap.Logger
+	level    zap.AtomicLevel
+	settings any
+	auditor  usecases.Auditor
+}
+
+func (a *admin) getLogLevel(c fiber.Ctx) error {
+	response := &models.Response{Data: models.LogLevelResponse{Level: a.level.String()}}
+	return response.Write(c, fiber.StatusOK)
+}
+
+func (a *admin) setLogLevel(c fiber.Ctx) error {
+	response := &models.Response{}
+
+	request := models.LogLevelRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		a.logger.Error("invalid log level request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	level, err := zapcore.ParseLevel(request.Level)
+	if err != nil {
+		response.Message = fmt.Sprintf("unknown log level %q", request.Level)
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	previous := a.level.Level()
+	err = a.auditor.Record(c.Context(), nil, &entities.AuditEvent{
+		ActorID:    request.UserID,
+		Action:     entities.AuditActionSetLogLevel,
+		TargetType: entities.AuditTargetLogLevel,
+		Metadata:   map[string]any{"from": previous.String(), "to": level.String()},
+	})
+	if err != nil { // the level is kept so every change stays on the record
+		a.logger.Error("error while recording the log level change", zap.Error(err))
+		return response.Write(c, fiber.StatusInternalServerError)
+	}
+
+	a.level.SetLevel(level)
+	a.logger.Warn("log level is changed", zap.Stringer("from", previous), zap.Stringer("to", level))
+
+	response.Data = models.LogLevelResponse{Level: level.String()}
+	return response.Write(c, fiber.StatusOK)
+}
+
+func (a *admin) buildInfo(c fiber.Ctx) error {
+	response := &models.Response{Data: cmd.BuildInfo()}
+	return response.Write(c, fiber.StatusOK)
+}
+
+func (a *admin) config(c fiber.Ctx) error {
+	response := &models.Response{Data: a.settings}
+	return response.Write(c, fiber.StatusOK)
+}
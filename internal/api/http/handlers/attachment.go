@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+
+	"github.com/mohammadne/porsesh/internal/api/http/models"
+	"github.com/mohammadne/porsesh/internal/usecases"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+)
+
+func NewAttachment(r fiber.Router, logger *zap.Logger, attachments usecases.Attachments) {
+	handler := &attachment{
+		logger:      logger,
+		attachments: attachments,
+	}
+
+	r.Post("/attachments", handler.upload)
+	r.Get("/media/*", handler.media) // the attachment urls point here
+}
+
+type attachment struct {
+	logger *zap.Logger
+	// usecases
+	attachments usecases.Attachments
+}
+
+func (s *attachment) upload(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.UploadAttachmentRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if params.UserID <= 0 {
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid upload attachment form", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if header.Size > usecases.MaxAttachmentSize {
+		return response.Write(c, http.StatusRequestEntityTooLarge)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error opening the uploaded file", zap.Error(err))
+		return response.Write(c, http.StatusInternalServerError)
+	}
+	defer file.Close() // ignore error
+
+	content, err := io.ReadAll(io.LimitReader(file, usecases.MaxAttachmentSize+1))
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error reading the uploaded file", zap.Error(err))
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	attachment, err := s.attachments.Upload(c.Context(), params.UserID, header.Header.Get("Content-Type"), content)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while uploading attachment", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidUploadArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrUploadTooLarge) {
+			return response.Write(c, http.StatusRequestEntityTooLarge)
+		}
+		if errors.Is(err, usecases.ErrUploadUnsupportedType) {
+			return response.Write(c, http.StatusUnsupportedMediaType)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	response.Data = models.NewAttachmentResponse(attachment)
+	return response.Write(c, http.StatusCreated)
+}
+
+func (s *attachment) media(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	blob, err := s.attachments.Media(c.Context(), c.Params("*"))
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while retrieving media", zap.Error(err))
+		if errors.Is(err, usecases.ErrMediaNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	c.Set("Content-Type", blob.ContentType)
+	c.Set("Cache-Control", "public, max-age=31536000, immutable") // the keys are never reused
+	c.Set("X-Content-Type-Options", "nosniff")
+	return c.Status(http.StatusOK).SendStream(blob.Body, int(blob.Size)) // the body is closed once sent
+}
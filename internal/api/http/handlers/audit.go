@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+
+	"github.com/mohammadne/porsesh/internal/api/http/models"
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/usecases"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+)
+
+func NewAudit(r fiber.Router, logger *zap.Logger, auditor usecases.Auditor) {
+	handler := &audit{
+		logger:  logger,
+		auditor: auditor,
+	}
+
+	g := r.Group("audit-events")
+	g.Get("/", handler.listEvents)
+}
+
+type audit struct {
+	logger *zap.Logger
+	// usecases
+	auditor usecases.Auditor
+}
+
+const (
+	auditExportFormatJSON  = "json"
+	auditExportFormatCSV   = "csv"
+	auditExportFormatJSONL = "jsonl"
+
+	auditExportPageSize = 1000
+)
+
+func (s *audit) listEvents(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.ListAuditEventsRequestParams{}
+	if err := c.Bind().WithoutAutoHandling().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	filter := entities.AuditFilter{
+		ActorID:    params.ActorID,
+		Action:     entities.AuditAction(params.Action),
+		TargetType: entities.AuditTarget(params.TargetType),
+		TargetID:   params.TargetID,
+		RequestID:  params.RequestID,
+	}
+
+	bounds := []struct {
+		raw   string
+		value *time.Time
+	}{{params.From, &filter.From}, {params.To, &filter.To}}
+	for _, bound := range bounds {
+		if len(bound.raw) == 0 {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, bound.raw)
+		if err != nil {
+			logger.FromContext(c.Context(), s.logger).Error("invalid time range in audit events query", zap.String("value", bound.raw), zap.Error(err))
+			return response.Write(c, fiber.StatusBadRequest)
+		}
+		*bound.value = parsed
+	}
+
+	switch params.Format {
+	case "", auditExportFormatJSON:
+		events, err := s.auditor.Events(c.Context(), &filter, params.Page, params.Limit)
+		if err != nil {
+			logger.FromContext(c.Context(), s.logger).Error("error while listing audit events", zap.Error(err))
+			if errors.Is(err, usecases.ErrInvalidAuditFilterArguments) {
+				return response.Write(c, http.StatusBadRequest)
+			}
+			return response.Write(c, http.StatusInternalServerError)
+		}
+
+		result := make([]models.AuditEventResponse, 0, len(events))
+		for _, event := range events {
+			result = append(result, auditEventResponse(&event))
+		}
+
+		response.Data = result
+		return response.Write(c, http.StatusOK)
+	case auditExportFormatCSV:
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Attachment("audit-events.csv")
+	case auditExportFormatJSONL:
+		c.Set(fiber.HeaderContentType, "application/jsonl")
+		c.Attachment("audit-events.jsonl")
+	default:
+		logger.FromContext(c.Context(), s.logger).Error("invalid audit events export format", zap.String("format", params.Format))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	// the stream writer runs after the handler returns and c is recycled by then, so only the context is kept
+	ctx := c.Context()
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		if err := s.export(ctx, w, &filter, params.Format); err != nil {
+			logger.FromContext(ctx, s.logger).Error("error while exporting audit events", zap.Error(err))
+		}
+	})
+}
+
+func (s *audit) export(ctx context.Context, w *bufio.Writer, filter *entities.AuditFilter, format string) error {
+	csvWriter := csv.NewWriter(w)
+	if format == auditExportFormatCSV {
+		header := []string{"id", "actor_id", "action", "target_type", "target_id", "request_id", "metadata", "created_at"}
+		if err := csvWriter.Write(header); err != nil {
+			return err
+		}
+	}
+
+	// the pages are keyed by the last exported event, the offset would rescan every earlier page
+	keyset := *filter
+	for {
+		events, err := s.auditor.Events(ctx, &keyset, 1, auditExportPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			record := auditEventResponse(&event)
+			if format == auditExportFormatJSONL {
+				line, err := json.Marshal(record)
+				if err != nil {
+					return err
+				}
+				w.Write(append(line, '\n'))
+				continue
+			}
+
+			metadata, _ := json.Marshal(record.Metadata)
+			err = csvWriter.Write([]string{
+				strconv.FormatInt(record.ID, 10),
+				strconv.FormatInt(record.ActorID, 10),
+				record.Action,
+				record.TargetType,
+				strconv.FormatInt(record.TargetID, 10),
+				record.RequestID,
+				string(metadata),
+				record.CreatedAt.Format(time.RFC3339),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if len(events) < auditExportPageSize {
+			return nil
+		}
+		last := events[len(events)-1]
+		keyset.BeforeCreatedAt, keyset.BeforeID = last.CreatedAt, last.ID
+	}
+}
+
+func auditEventResponse(event *entities.AuditEvent) models.AuditEventResponse {
+	return models.AuditEventResponse{
+		ID:         event.ID,
+		ActorID:    int64(event.ActorID),
+		Action:     string(event.Action),
+		TargetType: string(event.TargetType),
+		TargetID:   event.TargetID,
+		RequestID:  event.RequestID,
+		Metadata:   event.Metadata,
+		CreatedAt:  event.CreatedAt,
+	}
+}
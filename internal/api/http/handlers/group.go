@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+
+	"github.com/mohammadne/porsesh/internal/api/http/models"
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/usecases"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+)
+
+func NewGroup(r fiber.Router, logger *zap.Logger, groups usecases.Groups) {
+	handler := &group{
+		logger: logger,
+		groups: groups,
+	}
+
+	g := r.Group("groups")
+	g.Post("/", handler.createGroup)
+	g.Get("/:id/members", handler.listMembers)
+	g.Post("/:id/members", handler.addMembers)
+	g.Delete("/:id/members", handler.removeMembers)
+}
+
+type group struct {
+	logger *zap.Logger
+	// usecases
+	groups usecases.Groups
+}
+
+func (s *group) createGroup(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	request := models.CreateGroupRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid create group request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	params := models.GroupRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if params.UserID <= 0 {
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	group := entities.Group{
+		OwnerID: params.UserID,
+		Name:    request.Name,
+		Members: request.Members,
+	}
+
+	if err := s.groups.CreateGroup(c.Context(), &group); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while creating group", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidCreateGroupArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrCreateGroupAlreadyExists) {
+			return response.Write(c, http.StatusConflict)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	response.Data = models.CreateGroupResponse{ID: int64(group.ID)}
+	return response.Write(c, http.StatusCreated)
+}
+
+func (s *group) listMembers(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("group id is invalid in members")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	params := models.GroupRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if params.UserID <= 0 {
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	members, err := s.groups.Members(c.Context(), entities.GroupID(id), params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while listing group members", zap.Error(err))
+		return s.writeMembersError(c, response, err)
+	}
+
+	response.Data = members
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *group) addMembers(c fiber.Ctx) error {
+	return s.changeMembers(c, s.groups.AddMembers)
+}
+
+func (s *group) removeMembers(c fiber.Ctx) error {
+	return s.changeMembers(c, s.groups.RemoveMembers)
+}
+
+func (s *group) changeMembers(c fiber.Ctx, change func(context.Context, entities.GroupID, entities.UserID, []entities.UserID) error) error {
+	response := &models.Response{}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("group id is invalid in members")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	request := models.GroupMembersRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid group members request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+	scopeLogger(c, request.UserID)
+
+	if err := change(c.Context(), entities.GroupID(id), request.UserID, request.Members); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while changing group members", zap.Error(err))
+		return s.writeMembersError(c, response, err)
+	}
+
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *group) writeMembersError(c fiber.Ctx, response *models.Response, err error) error {
+	if errors.Is(err, usecases.ErrInvalidGroupMembersArguments) {
+		return response.Write(c, http.StatusBadRequest)
+	}
+	if errors.Is(err, usecases.ErrGroupMembersGroupNotExists) {
+		return response.Write(c, http.StatusNotFound)
+	}
+	return response.Write(c, http.StatusInternalServerError)
+}
@@ -5,10 +5,13 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	"go.uber.org/zap"
+
+	"github.com/mohammadne/porsesh/internal/api/http/models"
+	"github.com/mohammadne/porsesh/pkg/observability/health"
 )
 
-func NewHealthz(router fiber.Router, logger *zap.Logger) {
-	handler := &healthz{logger: logger}
+func NewHealthz(router fiber.Router, logger *zap.Logger, health *health.Health) {
+	handler := &healthz{logger: logger, health: health}
 
 	healthz := router.Group("healthz")
 	healthz.Get("/liveness", handler.liveness)
@@ -17,12 +20,28 @@ func NewHealthz(router fiber.Router, logger *zap.Logger) {
 
 type healthz struct {
 	logger *zap.Logger
+	health *health.Health
 }
 
 func (h *healthz) liveness(c fiber.Ctx) error {
 	return c.SendStatus(http.StatusOK)
 }
 
+// readiness fails only by the critical dependencies, the non-critical ones are reported as degraded
 func (h *healthz) readiness(c fiber.Ctx) error {
-	return c.SendStatus(http.StatusOK)
+	report := h.health.Check(c.Context())
+
+	statusCode := http.StatusOK
+	if !report.Ready() {
+		statusCode = http.StatusServiceUnavailable
+		for _, dependency := range report.Dependencies {
+			if dependency.Err != nil {
+				h.logger.Warn("dependency is down", zap.String("dependency", dependency.Name),
+					zap.String("class", string(dependency.Class)), zap.Error(dependency.Err))
+			}
+		}
+	}
+
+	c.Set("Cache-Control", "no-store")
+	return c.Status(statusCode).JSON(models.NewHealthResponse(report))
 }
@@ -1,17 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
-	"github.com/mitchellh/mapstructure"
 	"go.uber.org/zap"
 
+	"github.com/mohammadne/porsesh/internal/api/http/middlewares"
 	"github.com/mohammadne/porsesh/internal/api/http/models"
 	"github.com/mohammadne/porsesh/internal/entities"
 	"github.com/mohammadne/porsesh/internal/usecases"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
 )
 
 func NewPoll(r fiber.Router, logger *zap.Logger, feeds usecases.Feeds, pools usecases.Polls) {
@@ -21,12 +25,26 @@ func NewPoll(r fiber.Router, logger *zap.Logger, feeds usecases.Feeds, pools use
 		pools:  pools,
 	}
 
+	r.Get("/p/:slug", handler.retrievePoll) // short link of the poll
+	r.Get("/quiz/leaderboard", handler.leaderboard)
+
 	g := r.Group("poll")
 	g.Post("/", handler.createPoll)
 	g.Get("/", handler.retrieveFeed)
+	g.Get("/:id", handler.retrievePoll)
+	g.Post("/:id/publish", handler.publishPoll)
 	g.Post("/:id/vote", handler.vote)
 	g.Post("/:id/skip", handler.skip)
 	g.Get("/:id/stats", handler.statistics)
+	g.Get("/:id/calendar", handler.calendar)
+	g.Get("/:id/voters", handler.listVoters)
+	g.Get("/:id/other-answers", handler.listOtherAnswers)
+	g.Get("/:id/other-answers/frequent", handler.frequentOtherAnswers)
+	g.Put("/:id/other-answers/:voter/hidden", handler.hideOtherAnswer)
+	g.Post("/:id/other-answers/promote", handler.promoteOtherAnswer)
+	g.Get("/:id/participants", handler.listParticipants)
+	g.Post("/:id/participants", handler.addParticipants)
+	g.Delete("/:id/participants", handler.removeParticipants)
 }
 
 type poll struct {
@@ -37,62 +55,189 @@ type poll struct {
 }
 
 func (s *poll) createPoll(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
 	response := &models.Response{}
 
 	request := models.CreatePollRequest{}
 	if err := c.Bind().Body(&request); err != nil {
-		s.logger.Error("invalid create poll request body", zap.Error(err))
+		logger.FromContext(c.Context(), s.logger).Error("invalid create poll request body", zap.Error(err))
 		return response.Write(c, fiber.StatusBadRequest)
 	}
 
 	params := models.CreatePollRequestParams{}
-	if err := mapstructure.Decode(c.Queries(), &params); err != nil {
-		s.logger.Error("invalid query parameters", zap.Error(err))
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
 		return response.Write(c, fiber.StatusBadRequest)
 	} else if params.UserID <= 0 {
-		s.logger.Error("user-id in parameters should be given", zap.Error(err))
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given", zap.Error(err))
 		return response.Write(c, fiber.StatusBadRequest)
 	}
 
 	poll := entities.Poll{
-		Title:   request.Title,
-		UserID:  params.UserID,
-		Options: make([]entities.PollOption, 0, len(request.Options)),
-		Tags:    make([]entities.PollTag, 0, len(request.Tags)),
+		Title:             request.Title,
+		Description:       request.Description,
+		UserID:            params.UserID,
+		Visibility:        entities.PollVisibility(request.Visibility),
+		ResultsVisibility: entities.ResultsVisibility(request.ResultsVisibility),
+		Options:           make([]entities.PollOption, 0, len(request.Options)),
+		Tags:              make([]entities.PollTag, 0, len(request.Tags)),
+		Participants:      entities.Participants{Users: request.Participants, Groups: request.ParticipantGroups},
+		Anonymous:         request.Anonymous,
+		Type:              entities.PollType(request.Type),
+		Status:            entities.PollStatus(request.Status),
+		TimeLimit:         time.Duration(request.TimeLimit) * time.Second,
+		QuizSet:           request.QuizSet,
+		OtherMaxLength:    request.OtherMaxLength,
+		ShuffleOptions:    request.ShuffleOptions,
+	}
+	if request.ClosesAt != nil {
+		poll.ClosesAt = *request.ClosesAt
+	}
+	if request.PublishAt != nil {
+		poll.PublishAt = *request.PublishAt
+	}
+	if request.Scale != nil {
+		poll.Scale = &entities.PollScale{
+			Min: request.Scale.Min, Max: request.Scale.Max, Step: request.Scale.Step, Labels: request.Scale.Labels,
+		}
 	}
 	for index, option := range request.Options {
 		poll.Options = append(poll.Options, entities.PollOption{Content: option, Sort: index + 1})
 	}
+	for _, slot := range request.Slots {
+		poll.Options = append(poll.Options, entities.PollOption{
+			Content: slot.Label,
+			Sort:    len(poll.Options) + 1,
+			Slot:    &entities.PollSlot{StartsAt: slot.StartsAt, EndsAt: slot.EndsAt, TimeZone: slot.TimeZone},
+		})
+	}
+	if request.Attachment != 0 {
+		poll.Attachment = &entities.Attachment{ID: entities.AttachmentID(request.Attachment)}
+	}
+	for index, id := range request.OptionAttachments {
+		if index >= len(poll.Options) {
+			logger.FromContext(c.Context(), s.logger).Error("invalid option attachments", zap.Int("index", index))
+			return response.Write(c, fiber.StatusBadRequest)
+		} else if id != 0 {
+			poll.Options[index].Attachment = &entities.Attachment{ID: entities.AttachmentID(id)}
+		}
+	}
+	for _, index := range request.CorrectOptions {
+		if index < 0 || index >= len(poll.Options) {
+			logger.FromContext(c.Context(), s.logger).Error("invalid correct option index", zap.Int("index", index))
+			return response.Write(c, fiber.StatusBadRequest)
+		}
+		poll.Options[index].Correct = true
+	}
 	for _, tag := range request.Tags {
 		poll.Tags = append(poll.Tags, entities.PollTag{Name: tag})
 	}
 
 	if err := s.pools.CreatePoll(c.Context(), &poll); err != nil {
-		s.logger.Error("error while creating pool", zap.Error(err))
+		logger.FromContext(c.Context(), s.logger).Error("error while creating pool", zap.Error(err))
 		if errors.Is(err, usecases.ErrInvalidCreatePollArguments) {
 			return response.Write(c, http.StatusBadRequest)
 		}
 		return response.Write(c, http.StatusInternalServerError)
 	}
 
+	response.Data = models.CreatePollResponse{ID: int64(poll.ID), Slug: poll.Slug}
 	return response.Write(c, http.StatusCreated)
 }
 
+func (s *poll) retrievePoll(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.RetrievePollRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	ref := c.Params("id", c.Params("slug"))
+	poll, err := s.pools.GetPoll(c.Context(), ref, params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while retrieving poll", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidGetPollArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrGetPollPollNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	response.Data = models.NewPollResponse(poll)
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *poll) publishPoll(c fiber.Ctx) error {
+	response := &models.Response{}
+
+	request := models.PublishPollRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid publish poll request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+	scopeLogger(c, request.UserID)
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), request.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for publish", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	var publishAt time.Time
+	if request.PublishAt != nil {
+		publishAt = *request.PublishAt
+	}
+
+	if err := s.pools.PublishPoll(c.Context(), id, request.UserID, publishAt); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while publishing poll", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidPublishPollArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrPublishPollPollNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		if errors.Is(err, usecases.ErrPublishPollAlreadyPublished) {
+			return response.Write(c, http.StatusConflict)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	return response.Write(c, http.StatusOK)
+}
+
 func (s *poll) retrieveFeed(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
 	response := &models.Response{}
 
 	params := models.RetrieveFeedRequestParams{}
-	if err := mapstructure.Decode(c.Queries(), &params); err != nil {
-		s.logger.Error("invalid query parameters", zap.Error(err))
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
 		return response.Write(c, fiber.StatusBadRequest)
 	} else if params.UserID <= 0 {
-		s.logger.Error("user-id in parameters should be given", zap.Error(err))
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given", zap.Error(err))
 		return response.Write(c, fiber.StatusBadRequest)
 	}
 
-	feed, err := s.feeds.GetUserFeed(c.Context(), params.UserID, params.Tag, params.Page, params.Limit)
+	feed, err := s.feeds.GetUserFeed(c.Context(), params.UserID, params.Tag, params.Search, params.Page, params.Limit)
 	if err != nil {
-		s.logger.Error("error while retrieving user feed", zap.Error(err))
+		logger.FromContext(c.Context(), s.logger).Error("error while retrieving user feed", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidFeedArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
 		if errors.Is(err, usecases.ErrInvalidSkipPollArguments) {
 			return response.Write(c, http.StatusBadRequest)
 		}
@@ -102,32 +247,41 @@ func (s *poll) retrieveFeed(c fiber.Ctx) error {
 		return response.Write(c, http.StatusInternalServerError)
 	}
 
-	response.Data = feed
+	polls := make([]models.PollResponse, 0, len(feed))
+	for _, poll := range feed {
+		polls = append(polls, models.NewPollResponse(&poll))
+	}
+
+	response.Data = polls
 	return response.Write(c, http.StatusOK)
 }
 
 func (s *poll) vote(c fiber.Ctx) error {
 	response := &models.Response{}
 
-	idRaw := c.Params("id")
-	if len(idRaw) == 0 {
-		s.logger.Error("poll id not given for vote")
+	request := models.VoteRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid vote request body", zap.Error(err))
 		return response.Write(c, fiber.StatusBadRequest)
 	}
-	id, err := strconv.ParseUint(idRaw, 10, 64)
+	scopeLogger(c, request.UserID)
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), request.UserID)
 	if err != nil {
-		s.logger.Error("poll id is invalid in vote")
-		return response.Write(c, fiber.StatusBadRequest)
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for vote", zap.Error(err))
+		return s.writeResolveError(c, response, err)
 	}
 
-	request := models.VoteRequest{}
-	if err := c.Bind().Body(&request); err != nil {
-		s.logger.Error("invalid vote request body", zap.Error(err))
-		return response.Write(c, fiber.StatusBadRequest)
+	ballot := entities.Ballot{
+		OptionID: request.OptionID, Value: request.Value, Other: request.Other, Availability: request.Availability,
 	}
-
-	if err := s.pools.VotePoll(c.Context(), entities.PollID(id), request.UserID, request.OptionIndex); err != nil {
-		s.logger.Error("error while vote a pool", zap.Error(err))
+	if request.OptionID == 0 && request.OptionIndex != nil {
+		ballot.Option = request.OptionIndex
+		flagDeprecated(c, response, "optionIndex", "optionId")
+	}
+	result, err := s.pools.VotePoll(c.Context(), id, request.UserID, ballot)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while vote a pool", zap.Error(err))
 		if errors.Is(err, usecases.ErrInvalidVotePollArguments) {
 			return response.Write(c, http.StatusBadRequest)
 		}
@@ -137,40 +291,57 @@ func (s *poll) vote(c fiber.Ctx) error {
 		if errors.Is(err, usecases.ErrVotePollPollNotExists) {
 			return response.Write(c, http.StatusNotFound)
 		}
+		if errors.Is(err, usecases.ErrVotePollPollClosed) || errors.Is(err, usecases.ErrVotePollPollNotPublished) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		if errors.Is(err, usecases.ErrVotePollAlreadyActed) {
+			return response.Write(c, http.StatusConflict)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
 		return response.Write(c, http.StatusInternalServerError)
 	}
 
+	if result.Quiz {
+		response.Data = models.VoteResponse{Correct: result.Correct, Points: result.Points}
+	}
 	return response.Write(c, http.StatusOK)
 }
 
 func (s *poll) skip(c fiber.Ctx) error {
 	response := &models.Response{}
 
-	idRaw := c.Params("id")
-	if len(idRaw) == 0 {
-		s.logger.Error("poll id not given for vote")
-		return response.Write(c, fiber.StatusBadRequest)
-	}
-	id, err := strconv.ParseUint(idRaw, 10, 64)
-	if err != nil {
-		s.logger.Error("poll id is invalid in vote")
-		return response.Write(c, fiber.StatusBadRequest)
-	}
-
 	request := models.SkipRequest{}
 	if err := c.Bind().Body(&request); err != nil {
-		s.logger.Error("invalid skip request body", zap.Error(err))
+		logger.FromContext(c.Context(), s.logger).Error("invalid skip request body", zap.Error(err))
 		return response.Write(c, fiber.StatusBadRequest)
 	}
+	scopeLogger(c, request.UserID)
 
-	if err := s.pools.SkipPoll(c.Context(), entities.PollID(id), request.UserID); err != nil {
-		s.logger.Error("error while skip a pool", zap.Error(err))
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), request.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for skip", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	if err := s.pools.SkipPoll(c.Context(), id, request.UserID); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while skip a pool", zap.Error(err))
 		if errors.Is(err, usecases.ErrInvalidSkipPollArguments) {
 			return response.Write(c, http.StatusBadRequest)
 		}
 		if errors.Is(err, usecases.ErrSkipPollPollNotExists) {
 			return response.Write(c, http.StatusNotFound)
 		}
+		if errors.Is(err, usecases.ErrSkipPollPollClosed) || errors.Is(err, usecases.ErrSkipPollPollNotPublished) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		if errors.Is(err, usecases.ErrSkipPollAlreadyActed) {
+			return response.Write(c, http.StatusConflict)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
 		return response.Write(c, http.StatusInternalServerError)
 	}
 
@@ -178,31 +349,419 @@ func (s *poll) skip(c fiber.Ctx) error {
 }
 
 func (s *poll) statistics(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
 	response := &models.Response{}
 
-	idRaw := c.Params("id")
-	if len(idRaw) == 0 {
-		s.logger.Error("poll id not given for vote")
+	params := models.StatisticsRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
 		return response.Write(c, fiber.StatusBadRequest)
 	}
-	id, err := strconv.ParseUint(idRaw, 10, 64)
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), params.UserID)
 	if err != nil {
-		s.logger.Error("poll id is invalid in vote")
-		return response.Write(c, fiber.StatusBadRequest)
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for statistics", zap.Error(err))
+		return s.writeResolveError(c, response, err)
 	}
 
-	statistics, err := s.pools.Statistics(c.Context(), entities.PollID(id))
+	statistics, err := s.pools.Statistics(c.Context(), id, params.UserID)
 	if err != nil {
-		s.logger.Error("error while skip a pool", zap.Error(err))
-		if errors.Is(err, usecases.ErrInvalidSkipPollArguments) {
+		logger.FromContext(c.Context(), s.logger).Error("error while retrieving pool statistics", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidStatisticsArguments) {
 			return response.Write(c, http.StatusBadRequest)
 		}
-		if errors.Is(err, usecases.ErrSkipPollPollNotExists) {
+		if errors.Is(err, usecases.ErrStatisticsPollNotExists) {
 			return response.Write(c, http.StatusNotFound)
 		}
+		if errors.Is(err, usecases.ErrStatisticsResultsHidden) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
 		return response.Write(c, http.StatusInternalServerError)
 	}
 
 	response.Data = statistics
 	return response.Write(c, http.StatusOK)
 }
+
+func (s *poll) calendar(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.CalendarRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for calendar", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	calendar, err := s.pools.Calendar(c.Context(), id, params.UserID, params.OptionID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while exporting poll calendar", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidCalendarArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrCalendarPollNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		if errors.Is(err, usecases.ErrCalendarResultsHidden) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		if errors.Is(err, usecases.ErrCalendarNoBestSlot) {
+			return response.Write(c, http.StatusConflict)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	c.Set("Content-Type", "text/calendar; charset=utf-8")
+	c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="poll-%d.ics"`, id))
+	return c.Status(http.StatusOK).Send(calendar)
+}
+
+func (s *poll) listVoters(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.VotersRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if params.UserID <= 0 {
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for voters", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	if params.OptionID == 0 && params.Option != nil {
+		flagDeprecated(c, response, "option", "optionId")
+	}
+	voters, err := s.pools.Voters(c.Context(), id, params.UserID, params.OptionID, params.Option, params.Page, params.Limit)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while listing pool voters", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidVotersArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrVotersPollNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		if errors.Is(err, usecases.ErrVotersPollAnonymous) || errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	result := make([]models.VoterResponse, 0, len(voters))
+	for _, voter := range voters {
+		result = append(result, models.VoterResponse{
+			UserID:   int64(voter.UserID),
+			OptionID: int64(voter.OptionID),
+			Option:   voter.Option,
+			Other:    voter.Other,
+			ActedAt:  voter.ActedAt,
+		})
+	}
+
+	response.Data = result
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *poll) listOtherAnswers(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.OtherAnswersRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if params.UserID <= 0 {
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for other answers", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	answers, err := s.pools.OtherAnswers(c.Context(), id, params.UserID, params.Search, params.Page, params.Limit)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while listing other answers", zap.Error(err))
+		return s.writeOtherAnswersError(c, response, err)
+	}
+
+	result := make([]models.OtherAnswerResponse, 0, len(answers))
+	for _, answer := range answers {
+		result = append(result, models.OtherAnswerResponse{
+			UserID:  int64(answer.UserID),
+			Text:    answer.Text,
+			Hidden:  answer.Hidden,
+			ActedAt: answer.ActedAt,
+		})
+	}
+
+	response.Data = result
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *poll) frequentOtherAnswers(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.OtherAnswersRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if params.UserID <= 0 {
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for frequent other answers", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	frequencies, err := s.pools.FrequentOtherAnswers(c.Context(), id, params.UserID, params.Limit)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while retrieving frequent other answers", zap.Error(err))
+		return s.writeOtherAnswersError(c, response, err)
+	}
+
+	result := make([]models.OtherAnswerFrequencyResponse, 0, len(frequencies))
+	for _, frequency := range frequencies {
+		result = append(result, models.OtherAnswerFrequencyResponse{Text: frequency.Text, Count: frequency.Count})
+	}
+
+	response.Data = result
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *poll) hideOtherAnswer(c fiber.Ctx) error {
+	response := &models.Response{}
+
+	request := models.HideOtherAnswerRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid hide other answer request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+	scopeLogger(c, request.UserID)
+
+	voter, err := strconv.ParseInt(c.Params("voter"), 10, 64)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid voter of the other answer", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), request.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for hiding other answer", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	err = s.pools.HideOtherAnswer(c.Context(), id, request.UserID, entities.UserID(voter), request.Hidden)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while hiding other answer", zap.Error(err))
+		return s.writeOtherAnswersError(c, response, err)
+	}
+
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *poll) promoteOtherAnswer(c fiber.Ctx) error {
+	response := &models.Response{}
+
+	request := models.PromoteOtherAnswerRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid promote other answer request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+	scopeLogger(c, request.UserID)
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), request.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for promoting other answer", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	moved, err := s.pools.PromoteOtherAnswer(c.Context(), id, request.UserID, request.Text)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while promoting other answer", zap.Error(err))
+		return s.writeOtherAnswersError(c, response, err)
+	}
+
+	response.Data = models.PromoteOtherAnswerResponse{Votes: moved}
+	return response.Write(c, http.StatusCreated)
+}
+
+func (s *poll) writeOtherAnswersError(c fiber.Ctx, response *models.Response, err error) error {
+	if errors.Is(err, usecases.ErrInvalidOtherAnswersArguments) {
+		return response.Write(c, http.StatusBadRequest)
+	}
+	if errors.Is(err, usecases.ErrOtherAnswersPollNotExists) || errors.Is(err, usecases.ErrOtherAnswerNotExists) {
+		return response.Write(c, http.StatusNotFound)
+	}
+	if errors.Is(err, usecases.ErrPollAccessDenied) {
+		return response.Write(c, http.StatusForbidden)
+	}
+	return response.Write(c, http.StatusInternalServerError)
+}
+
+func (s *poll) leaderboard(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.LeaderboardRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	entries, err := s.pools.Leaderboard(c.Context(), params.Tag, params.QuizSet, params.Page, params.Limit)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while retrieving quiz leaderboard", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidLeaderboardArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	result := make([]models.LeaderboardEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, models.LeaderboardEntryResponse{
+			UserID:   int64(entry.UserID),
+			Points:   entry.Points,
+			Correct:  entry.Correct,
+			Answered: entry.Answered,
+		})
+	}
+
+	response.Data = result
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *poll) listParticipants(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.ParticipantsRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if params.UserID <= 0 {
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for participants", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	participants, err := s.pools.Participants(c.Context(), id, params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while listing pool participants", zap.Error(err))
+		return s.writeParticipantsError(c, response, err)
+	}
+
+	response.Data = models.NewParticipantsResponse(participants)
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *poll) addParticipants(c fiber.Ctx) error {
+	return s.changeParticipants(c, s.pools.AddParticipants)
+}
+
+func (s *poll) removeParticipants(c fiber.Ctx) error {
+	return s.changeParticipants(c, s.pools.RemoveParticipants)
+}
+
+func (s *poll) changeParticipants(c fiber.Ctx, change func(context.Context, entities.PollID, entities.UserID, entities.Participants) error) error {
+	response := &models.Response{}
+
+	request := models.ParticipantsRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid participants request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+	scopeLogger(c, request.UserID)
+
+	id, err := s.pools.ResolvePoll(c.Context(), c.Params("id"), request.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while resolving poll for participants", zap.Error(err))
+		return s.writeResolveError(c, response, err)
+	}
+
+	participants := entities.Participants{Users: request.Participants, Groups: request.Groups}
+	if err := change(c.Context(), id, request.UserID, participants); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while changing pool participants", zap.Error(err))
+		return s.writeParticipantsError(c, response, err)
+	}
+
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *poll) writeParticipantsError(c fiber.Ctx, response *models.Response, err error) error {
+	if errors.Is(err, usecases.ErrInvalidPollParticipantsArguments) {
+		return response.Write(c, http.StatusBadRequest)
+	}
+	if errors.Is(err, usecases.ErrPollParticipantsPollNotExists) {
+		return response.Write(c, http.StatusNotFound)
+	}
+	if errors.Is(err, usecases.ErrPollAccessDenied) {
+		return response.Write(c, http.StatusForbidden)
+	}
+	return response.Write(c, http.StatusInternalServerError)
+}
+
+func (s *poll) writeResolveError(c fiber.Ctx, response *models.Response, err error) error {
+	if errors.Is(err, usecases.ErrInvalidResolvePollArguments) {
+		return response.Write(c, http.StatusBadRequest)
+	}
+	if errors.Is(err, usecases.ErrResolvePollPollNotExists) {
+		return response.Write(c, http.StatusNotFound)
+	}
+	return response.Write(c, http.StatusInternalServerError)
+}
+
+// flagDeprecated tells the client the request has used a deprecated field, the request is still served
+func flagDeprecated(c fiber.Ctx, response *models.Response, field, replacement string) {
+	message := fmt.Sprintf("%s is deprecated, use %s instead", field, replacement)
+	c.Set("Deprecation", "true")
+	c.Set("Warning", fmt.Sprintf(`299 - "%s"`, message))
+	response.Message = message
+}
+
+// scopeLogger annotates the request-scoped logger with the matched route, and with the user once the body
+// carrying it is bound as the access log only knows the one of the query. A zero user is left out.
+func scopeLogger(c fiber.Ctx, userID entities.UserID) {
+	if userID <= 0 {
+		middlewares.ScopeLogger(c)
+		return
+	}
+	middlewares.ScopeLogger(c, zap.String("user_id", strconv.FormatInt(int64(userID), 10)))
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+
+	"github.com/mohammadne/porsesh/internal/api/http/models"
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/usecases"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+)
+
+func NewSurvey(r fiber.Router, logger *zap.Logger, surveys usecases.Surveys) {
+	handler := &survey{
+		logger:  logger,
+		surveys: surveys,
+	}
+
+	g := r.Group("surveys")
+	g.Post("/", handler.createSurvey)
+	g.Get("/:id", handler.retrieveSurvey)
+	g.Post("/:id/responses", handler.submitResponse)
+	g.Get("/:id/stats", handler.statistics)
+}
+
+type survey struct {
+	logger *zap.Logger
+	// usecases
+	surveys usecases.Surveys
+}
+
+func (s *survey) createSurvey(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	request := models.CreateSurveyRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid create survey request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	params := models.SurveyRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	} else if params.UserID <= 0 {
+		logger.FromContext(c.Context(), s.logger).Error("user-id in parameters should be given")
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	survey := entities.Survey{
+		UserID:    params.UserID,
+		Title:     request.Title,
+		Questions: make([]entities.SurveyQuestion, 0, len(request.Questions)),
+	}
+	for _, question := range request.Questions {
+		surveyQuestion := entities.SurveyQuestion{PollID: question.PollID}
+		for _, rule := range question.Rules {
+			surveyRule := entities.SurveyRule{JumpTo: rule.JumpTo}
+			if rule.OptionID != nil && *rule.OptionID > 0 {
+				surveyRule.OptionID = entities.PollOptionID(*rule.OptionID)
+			} else if rule.Option != nil {
+				surveyRule.Option = *rule.Option
+				flagDeprecated(c, response, "option", "optionId")
+			} else {
+				logger.FromContext(c.Context(), s.logger).Error("option of the survey rule should be given")
+				return response.Write(c, fiber.StatusBadRequest)
+			}
+			surveyQuestion.Rules = append(surveyQuestion.Rules, surveyRule)
+		}
+		survey.Questions = append(survey.Questions, surveyQuestion)
+	}
+
+	if err := s.surveys.CreateSurvey(c.Context(), &survey); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while creating survey", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidCreateSurveyArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrCreateSurveyPollNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	response.Data = models.CreateSurveyResponse{ID: int64(survey.ID)}
+	return response.Write(c, http.StatusCreated)
+}
+
+func (s *survey) retrieveSurvey(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.SurveyRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid survey id", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	survey, err := s.surveys.GetSurvey(c.Context(), entities.SurveyID(id), params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while retrieving survey", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidGetSurveyArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrGetSurveySurveyNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	response.Data = models.NewSurveyResponse(survey)
+	return response.Write(c, http.StatusOK)
+}
+
+func (s *survey) submitResponse(c fiber.Ctx) error {
+	response := &models.Response{}
+
+	request := models.SubmitSurveyRequest{}
+	if err := c.Bind().Body(&request); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid submit survey request body", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+	scopeLogger(c, request.UserID)
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid survey id", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	answers := make([]entities.SurveyAnswer, 0, len(request.Answers))
+	for _, answer := range request.Answers {
+		surveyAnswer := entities.SurveyAnswer{Position: answer.Question}
+		if answer.OptionID != nil {
+			surveyAnswer.Ballot = &entities.Ballot{OptionID: entities.PollOptionID(*answer.OptionID)}
+		} else if answer.Option != nil {
+			surveyAnswer.Ballot = &entities.Ballot{Option: answer.Option}
+			flagDeprecated(c, response, "option", "optionId")
+		} else if answer.Value != nil {
+			surveyAnswer.Ballot = &entities.Ballot{Value: answer.Value}
+		} else if answer.Other != nil {
+			surveyAnswer.Ballot = &entities.Ballot{Other: *answer.Other}
+		} else if len(answer.Availability) != 0 {
+			surveyAnswer.Ballot = &entities.Ballot{Availability: answer.Availability}
+		}
+		answers = append(answers, surveyAnswer)
+	}
+
+	if err := s.surveys.SubmitResponse(c.Context(), entities.SurveyID(id), request.UserID, answers); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while submitting survey response", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidSubmitSurveyArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrSubmitSurveySurveyNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		if errors.Is(err, usecases.ErrSubmitSurveyPollClosed) || errors.Is(err, usecases.ErrDailyUserVotesLimit) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		if errors.Is(err, usecases.ErrSubmitSurveyAlreadyResponded) {
+			return response.Write(c, http.StatusConflict)
+		}
+		if errors.Is(err, usecases.ErrPollAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	return response.Write(c, http.StatusCreated)
+}
+
+func (s *survey) statistics(c fiber.Ctx) error {
+	scopeLogger(c, 0)
+
+	response := &models.Response{}
+
+	params := models.SurveyRequestParams{}
+	if err := c.Bind().Query(&params); err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid query parameters", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("invalid survey id", zap.Error(err))
+		return response.Write(c, fiber.StatusBadRequest)
+	}
+
+	statistics, err := s.surveys.Statistics(c.Context(), entities.SurveyID(id), params.UserID)
+	if err != nil {
+		logger.FromContext(c.Context(), s.logger).Error("error while retrieving survey statistics", zap.Error(err))
+		if errors.Is(err, usecases.ErrInvalidSurveyStatisticsArguments) {
+			return response.Write(c, http.StatusBadRequest)
+		}
+		if errors.Is(err, usecases.ErrSurveyStatisticsSurveyNotExists) {
+			return response.Write(c, http.StatusNotFound)
+		}
+		if errors.Is(err, usecases.ErrSurveyAccessDenied) {
+			return response.Write(c, http.StatusForbidden)
+		}
+		return response.Write(c, http.StatusInternalServerError)
+	}
+
+	result := models.SurveyStatisticsResponse{
+		SurveyID:    int64(statistics.SurveyID),
+		Respondents: statistics.Respondents,
+		Questions:   make([]models.SurveyQuestionStatisticsResponse, 0, len(statistics.Questions)),
+	}
+	for _, question := range statistics.Questions {
+		result.Questions = append(result.Questions, models.SurveyQuestionStatisticsResponse{
+			Position:       question.Position,
+			PollID:         int64(question.PollID),
+			Reached:        question.Reached,
+			Answered:       question.Answered,
+			CompletionRate: question.CompletionRate,
+		})
+	}
+
+	response.Data = result
+	return response.Write(c, http.StatusOK)
+}
@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/requestid"
+)
+
+// routeScopedKey marks the requests whose scoped logger is already annotated with the matched route
+type routeScopedKey struct{}
+
+// AccessLog stores a request-scoped logger in the request context, annotated with the request id, the user id
+// of the query and the path, so the handlers and the usecases attach them to their records. It also writes one
+// access line per request, the successful ones are sampled by the given ratio while the failures are always
+// written. It has to come after the RequestID and the Tracing middlewares.
+func AccessLog(lg *zap.Logger, successSampling float64) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+
+		fields := []zap.Field{
+			zap.String("request_id", requestid.FromContext(c.Context())),
+			zap.String("method", c.Method()),
+			zap.String("path", strings.Clone(c.Path())), // fiber reuses the buffers of the request
+		}
+		if userID := c.Query("userId"); len(userID) > 0 {
+			fields = append(fields, zap.String("user_id", strings.Clone(userID)))
+		}
+		c.SetContext(logger.NewContext(c.Context(), lg.With(fields...)))
+
+		own := c.Route()
+		err := c.Next()
+
+		status := responseStatus(c, err)
+		if status < fiber.StatusBadRequest && rand.Float64() >= successSampling {
+			return err
+		}
+
+		accessFields := []zap.Field{
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes_out", len(c.Response().Body())),
+			zap.String("ip", c.IP()),
+			zap.String("user_agent", c.Get(fiber.HeaderUserAgent)),
+		}
+		if c.Locals(routeScopedKey{}) == nil {
+			accessFields = append(accessFields, zap.String("route", matchedRoute(c, own)))
+		}
+		if err != nil {
+			accessFields = append(accessFields, zap.Error(err))
+		}
+
+		accessLogger := logger.FromContext(c.Context(), lg)
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			accessLogger.Error("access", accessFields...)
+		case status >= fiber.StatusBadRequest:
+			accessLogger.Warn("access", accessFields...)
+		default:
+			accessLogger.Info("access", accessFields...)
+		}
+		return err
+	}
+}
+
+// ScopeLogger annotates the request-scoped logger with the matched route and the given fields, the handlers
+// call it as the route is only matched once they are reached and the user of a body only known once it's bound
+func ScopeLogger(c fiber.Ctx, fields ...zap.Field) {
+	if c.Locals(routeScopedKey{}) == nil {
+		c.Locals(routeScopedKey{}, true)
+		fields = append(fields, zap.String("route", c.Route().Path))
+	}
+	c.SetContext(logger.With(c.Context(), fields...))
+}
@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const AdminTokenHeader = "X-Admin-Token"
+
+// Admin guards the operational endpoints, the requests have to carry the shared token if it's
+// configured, otherwise they have to come from the loopback addresses
+func Admin(token string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if len(token) == 0 {
+			if ip := net.ParseIP(c.IP()); ip == nil || !ip.IsLoopback() {
+				return c.SendStatus(fiber.StatusForbidden)
+			}
+			return c.Next()
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.Get(AdminTokenHeader)), []byte(token)) != 1 {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return c.Next()
+	}
+}
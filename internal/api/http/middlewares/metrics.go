@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+)
+
+// Metrics records the rate, the errors and the duration (RED) of the requests by their route templates,
+// methods and status classes (e.g. 2xx), along with the number of the requests in flight
+func Metrics(registry *metrics.Registry, namespace, subsystem string) fiber.Handler {
+	counter := registry.MustRegisterCounter("http_counter", namespace, subsystem, []string{"route", "method", "status"})
+	histogram := registry.MustRegisterHistogram("http_histogram", namespace, subsystem, []string{"route", "method", "status"})
+	inFlight := registry.MustRegisterGauge("http_in_flight", namespace, subsystem, []string{})
+
+	return func(c fiber.Ctx) error {
+		start, own := time.Now(), c.Route()
+		inFlight.IncrementVector()
+		defer inFlight.DecrementVector()
+
+		err := c.Next()
+
+		route, method := matchedRoute(c, own), c.Method()
+		status := strconv.Itoa(responseStatus(c, err)/100) + "xx"
+		counter.IncrementVectorContext(c.Context(), route, method, status)
+		histogram.ObserveResponseTimeContext(c.Context(), start, route, method, status)
+
+		return err
+	}
+}
@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/mohammadne/porsesh/pkg/requestid"
+)
+
+// RequestID propagates the incoming X-Request-ID header (or generates a new one)
+// into the request context so the lower layers can attach it to their records
+func RequestID() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if len(id) == 0 || len(id) > 128 {
+			id = requestid.Generate()
+		}
+
+		c.Set(requestid.Header, id)
+		c.SetContext(requestid.NewContext(c.Context(), id))
+		return c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// unmatchedRoute labels the requests no route has matched, their raw paths are never used as labels
+const unmatchedRoute = "unmatched"
+
+// matchedRoute is the template of the route which has served the request (e.g. /api/v1/poll/:id/vote),
+// own is the route of the calling middleware which stays the current one if nothing else has matched
+func matchedRoute(c fiber.Ctx, own *fiber.Route) string {
+	if route := c.Route(); route != nil && route != own {
+		return route.Path
+	}
+	return unmatchedRoute
+}
+
+// responseStatus is the status code the request is answered with, the returned error is written
+// by the error handler only after the middlewares
+func responseStatus(c fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return fe.Code
+	}
+	return fiber.StatusInternalServerError
+}
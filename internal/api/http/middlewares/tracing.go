@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+// Tracing starts a server span for every request, continuing the trace of the incoming W3C traceparent
+// header if there is one. The span is named after the route template, not the raw path.
+func Tracing() fiber.Handler {
+	tracer := tracing.Tracer("github.com/mohammadne/porsesh/internal/api/http")
+
+	return func(c fiber.Ctx) error {
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(c.Context(), headerCarrier{c})
+
+		ctx, span := tracer.Start(ctx, c.Method(), trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(c.Method()),
+			semconv.URLPath(c.Path()),
+			semconv.URLScheme(c.Scheme()),
+			semconv.ClientAddress(c.IP()),
+			semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+		))
+		defer span.End()
+
+		own := c.Route()
+		c.SetContext(ctx)
+		err := c.Next()
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		status, route := responseStatus(c, err), matchedRoute(c, own)
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		return err
+	}
+}
+
+// headerCarrier exposes the request headers to the propagators
+type headerCarrier struct {
+	c fiber.Ctx
+}
+
+func (carrier headerCarrier) Get(key string) string {
+	return carrier.c.Get(key)
+}
+
+func (carrier headerCarrier) Set(key, value string) {
+	carrier.c.Request().Header.Set(key, value)
+}
+
+func (carrier headerCarrier) Keys() []string {
+	headers := carrier.c.GetReqHeaders()
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	return keys
+}
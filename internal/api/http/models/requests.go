@@ -1,33 +1,106 @@
 package models
 
-import "github.com/mohammadne/porsesh/internal/entities"
+import (
+	"time"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+)
 
 // CreatePoll
 
 type CreatePollRequestParams struct {
-	UserID entities.UserID `json:"userId"`
+	UserID entities.UserID `query:"userId"`
 }
 
 type CreatePollRequest struct {
-	Title   string   `json:"title"`
-	Options []string `json:"options"`
-	Tags    []string `json:"tags"`
+	Title             string             `json:"title"`
+	Description       string             `json:"description"` // Markdown, up to 4000 characters
+	Options           []string           `json:"options"`
+	Tags              []string           `json:"tags"`
+	Visibility        string             `json:"visibility"`        // public (default), unlisted or private
+	Participants      []entities.UserID  `json:"participants"`      // only for private polls
+	ParticipantGroups []entities.GroupID `json:"participantGroups"` // only for private polls, the own groups
+	// ResultsVisibility is one of always (default), after_vote, after_close or owner
+	ResultsVisibility string     `json:"resultsVisibility"`
+	ClosesAt          *time.Time `json:"closesAt"`
+	// Status is one of published (default), draft or scheduled, only the scheduled polls take PublishAt
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publishAt"`
+	Anonymous bool       `json:"anonymous"` // can't be changed afterwards
+	Type      string     `json:"type"`      // single (default), quiz, scale or schedule
+	// the quiz-only settings, CorrectOptions are the indexes of the correct options
+	CorrectOptions []int  `json:"correctOptions"`
+	TimeLimit      int    `json:"timeLimit"` // seconds, counted from the creation
+	QuizSet        string `json:"quizSet"`
+	// the scale-only settings, the scale polls have no options
+	Scale *PollScaleRequest `json:"scale"`
+	// OtherMaxLength allows the "other" free-text answers up to the length, zero disables them
+	OtherMaxLength int `json:"otherMaxLength"`
+	// ShuffleOptions shows the options of single and quiz polls in a stable per-voter order,
+	// the option indexes of the votes are in that order
+	ShuffleOptions bool `json:"shuffleOptions"`
+	// the schedule-only time slots, they take the place of the options
+	Slots []PollSlotRequest `json:"slots"`
+	// the uploaded attachments of the poll and its options, OptionAttachments follows the
+	// order of the options and zero means no attachment
+	Attachment        int64   `json:"attachment"`
+	OptionAttachments []int64 `json:"optionAttachments"`
+}
+
+type PollSlotRequest struct {
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	TimeZone string    `json:"timeZone"` // IANA name, UTC by default
+	Label    string    `json:"label"`    // optional, the formatted time range by default
+}
+
+type PollScaleRequest struct {
+	Min    int            `json:"min"`
+	Max    int            `json:"max"`
+	Step   int            `json:"step"`
+	Labels map[int]string `json:"labels"` // optional label of the values
+}
+
+// UploadAttachment
+
+type UploadAttachmentRequestParams struct {
+	UserID entities.UserID `query:"userId"`
+}
+
+// PublishPoll
+
+type PublishPollRequest struct {
+	UserID    entities.UserID `json:"userId"`
+	PublishAt *time.Time      `json:"publishAt"` // schedules the draft, it's published right away if not given
+}
+
+// RetrievePoll
+
+type RetrievePollRequestParams struct {
+	UserID entities.UserID `query:"userId"`
 }
 
 // RetrieveFeed
 
 type RetrieveFeedRequestParams struct {
-	Tag    string          `json:"tag"`
-	Page   int             `json:"page"`
-	Limit  int             `json:"limit"`
-	UserID entities.UserID `json:"userId"`
+	Tag    string          `query:"tag"`
+	Search string          `query:"q"` // full-text search over the titles and the descriptions
+	Page   int             `query:"page"`
+	Limit  int             `query:"limit"`
+	UserID entities.UserID `query:"userId"`
 }
 
 // Vote
 
 type VoteRequest struct {
-	UserID      entities.UserID `json:"userId"`
-	OptionIndex int             `json:"optionIndex"`
+	UserID   entities.UserID       `json:"userId"`
+	OptionID entities.PollOptionID `json:"optionId"` // taken over the index when given
+	// OptionIndex is in the order the voter is shown the options, it's deprecated in favor of OptionID
+	OptionIndex *int   `json:"optionIndex"`
+	Value       *int   `json:"value"` // scale polls only, required by them
+	Other       string `json:"other"` // the free-text answer, taken over the option
+	// schedule polls only, yes, if_need_be or no for every slot in order
+	Availability []entities.Availability `json:"availability"`
 }
 
 // Skip
@@ -35,3 +108,140 @@ type VoteRequest struct {
 type SkipRequest struct {
 	UserID entities.UserID `json:"userId"`
 }
+
+// Statistics
+
+type StatisticsRequestParams struct {
+	UserID entities.UserID `query:"userId"`
+}
+
+// Calendar
+
+type CalendarRequestParams struct {
+	UserID   entities.UserID       `query:"userId"`
+	OptionID entities.PollOptionID `query:"optionId"` // the chosen slot, the best one if not given
+}
+
+// Voters
+
+type VotersRequestParams struct {
+	UserID   entities.UserID       `query:"userId"`
+	OptionID entities.PollOptionID `query:"optionId"` // taken over the index, all of the options if none is given
+	Option   *int                  `query:"option"`   // index of the option, deprecated in favor of OptionID
+	Page     int                   `query:"page"`
+	Limit    int                   `query:"limit"`
+}
+
+// Leaderboard
+
+type LeaderboardRequestParams struct {
+	Tag     string `query:"tag"`
+	QuizSet string `query:"quizSet"`
+	Page    int    `query:"page"`
+	Limit   int    `query:"limit"`
+}
+
+// OtherAnswers
+
+type OtherAnswersRequestParams struct {
+	UserID entities.UserID `query:"userId"`
+	Search string          `query:"search"`
+	Page   int             `query:"page"`
+	Limit  int             `query:"limit"`
+}
+
+type HideOtherAnswerRequest struct {
+	UserID entities.UserID `json:"userId"`
+	Hidden bool            `json:"hidden"`
+}
+
+type PromoteOtherAnswerRequest struct {
+	UserID entities.UserID `json:"userId"`
+	Text   string          `json:"text"`
+}
+
+// Participants
+
+type ParticipantsRequestParams struct {
+	UserID entities.UserID `query:"userId"`
+}
+
+type ParticipantsRequest struct {
+	UserID       entities.UserID    `json:"userId"`
+	Participants []entities.UserID  `json:"participants"`
+	Groups       []entities.GroupID `json:"groups"` // the own groups, all of their members are invited
+}
+
+// Groups
+
+type GroupRequestParams struct {
+	UserID entities.UserID `query:"userId"`
+}
+
+type CreateGroupRequest struct {
+	Name    string            `json:"name"` // unique among the groups of the owner
+	Members []entities.UserID `json:"members"`
+}
+
+type GroupMembersRequest struct {
+	UserID  entities.UserID   `json:"userId"`
+	Members []entities.UserID `json:"members"`
+}
+
+// AuditEvents
+
+type ListAuditEventsRequestParams struct {
+	ActorID    entities.UserID `query:"actorId"`
+	Action     string          `query:"action"`
+	TargetType string          `query:"targetType"`
+	TargetID   int64           `query:"targetId"`
+	RequestID  string          `query:"requestId"`
+	From       string          `query:"from"` // RFC3339
+	To         string          `query:"to"`   // RFC3339
+	Page       int             `query:"page"`
+	Limit      int             `query:"limit"`
+	Format     string          `query:"format"` // json, csv or jsonl
+}
+
+// Surveys
+
+type SurveyRequestParams struct {
+	UserID entities.UserID `query:"userId"`
+}
+
+type CreateSurveyRequest struct {
+	Title     string                  `json:"title"`
+	Questions []SurveyQuestionRequest `json:"questions"` // in order
+}
+
+type SurveyQuestionRequest struct {
+	PollID entities.PollID     `json:"pollId"`
+	Rules  []SurveyRuleRequest `json:"rules"`
+}
+
+type SurveyRuleRequest struct {
+	OptionID *int64 `json:"optionId"` // taken over the index when given
+	// Option is the index in the original order of the options, it's deprecated in favor of OptionID
+	Option *int `json:"option"`
+	JumpTo int  `json:"jumpTo"` // position of the question (starting from 1), 0 ends the survey
+}
+
+type SubmitSurveyRequest struct {
+	UserID  entities.UserID       `json:"userId"`
+	Answers []SurveyAnswerRequest `json:"answers"`
+}
+
+type SurveyAnswerRequest struct {
+	Question int     `json:"question"` // position of the question, starting from 1
+	OptionID *int64  `json:"optionId"` // taken over the index when given
+	Option   *int    `json:"option"`   // index of the option, deprecated in favor of OptionID
+	Value    *int    `json:"value"`    // scale polls only
+	Other    *string `json:"other"`    // the free-text answer
+	// schedule polls only, skipped if none of the above is given
+	Availability []entities.Availability `json:"availability"`
+}
+
+type LogLevelRequest struct {
+	UserID entities.UserID `json:"userId"` // the operator, recorded as the actor of the change
+	Level  string          `json:"level"`  // debug, info, warn, error, dpanic, panic or fatal
+}
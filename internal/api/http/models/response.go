@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/pkg/observability/health"
 )
 
 type Response struct {
@@ -16,15 +19,319 @@ func (response *Response) Write(ctx fiber.Ctx, statusCode int) error {
 	return ctx.Status(statusCode).JSON(&response)
 }
 
-type FeedResponse struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Options   []string  `json:"options"`
-	Tags      []string  `json:"tags"`
-	CreatedAt time.Time `json:"createdAt"`
+type CreatePollResponse struct {
+	ID   int64  `json:"id"`
+	Slug string `json:"slug"`
+}
+
+type PollResponse struct {
+	ID                int64               `json:"id"`
+	Slug              string              `json:"slug"`
+	Type              string              `json:"type"`
+	Status            string              `json:"status"`
+	UserID            int64               `json:"userId"`
+	Title             string              `json:"title"`
+	Description       string              `json:"description,omitempty"`     // the Markdown source
+	DescriptionHTML   string              `json:"descriptionHtml,omitempty"` // sanitized, safe to embed
+	Visibility        string              `json:"visibility"`
+	ResultsVisibility string              `json:"resultsVisibility"`
+	Anonymous         bool                `json:"anonymous"`
+	Attachment        *AttachmentResponse `json:"attachment,omitempty"`
+	Options           []string            `json:"options"`
+	OptionIDs         []int64             `json:"optionIds"` // in the order of the options, the votes should refer to them
+	// OptionAttachments follows the order of the options, it's left out if none has an attachment
+	OptionAttachments []*AttachmentResponse `json:"optionAttachments,omitempty"`
+	Scale             *PollScaleResponse    `json:"scale,omitempty"`
+	Slots             []PollSlotResponse    `json:"slots,omitempty"` // schedule polls only, in the order of the options
+	Tags              []string              `json:"tags"`
+	CorrectOptions    []int                 `json:"correctOptions,omitempty"` // closed quizzes only
+	TimeLimit         int                   `json:"timeLimit,omitempty"`      // seconds
+	QuizSet           string                `json:"quizSet,omitempty"`
+	OtherMaxLength    int                   `json:"otherMaxLength,omitempty"`
+	ShuffleOptions    bool                  `json:"shuffleOptions,omitempty"` // the options are in the order of the viewer
+	ClosesAt          *time.Time            `json:"closesAt,omitempty"`
+	PublishAt         *time.Time            `json:"publishAt,omitempty"`
+	PublishedAt       *time.Time            `json:"publishedAt,omitempty"`
+	CreatedAt         time.Time             `json:"createdAt"`
+}
+
+func NewPollResponse(poll *entities.Poll) PollResponse {
+	response := PollResponse{
+		ID:                int64(poll.ID),
+		Slug:              poll.Slug,
+		Type:              string(poll.Type),
+		Status:            string(poll.Status),
+		UserID:            int64(poll.UserID),
+		Title:             poll.Title,
+		Description:       poll.Description,
+		DescriptionHTML:   poll.DescriptionHTML,
+		Visibility:        string(poll.Visibility),
+		ResultsVisibility: string(poll.ResultsVisibility),
+		Anonymous:         poll.Anonymous,
+		Options:           make([]string, 0, len(poll.Options)),
+		OptionIDs:         make([]int64, 0, len(poll.Options)),
+		Tags:              make([]string, 0, len(poll.Tags)),
+		TimeLimit:         int(poll.TimeLimit / time.Second),
+		QuizSet:           poll.QuizSet,
+		OtherMaxLength:    poll.OtherMaxLength,
+		ShuffleOptions:    poll.ShuffleOptions,
+		CreatedAt:         poll.CreatedAt,
+	}
+	if !poll.ClosesAt.IsZero() {
+		response.ClosesAt = &poll.ClosesAt
+	}
+	if !poll.PublishAt.IsZero() {
+		response.PublishAt = &poll.PublishAt
+	}
+	if !poll.PublishedAt.IsZero() {
+		response.PublishedAt = &poll.PublishedAt
+	}
+	if poll.Scale != nil {
+		response.Scale = &PollScaleResponse{
+			Min: poll.Scale.Min, Max: poll.Scale.Max, Step: poll.Scale.Step, Labels: poll.Scale.Labels,
+		}
+	}
+	response.Attachment = NewAttachmentResponse(poll.Attachment)
+	for index, option := range poll.Options {
+		response.Options = append(response.Options, option.Content)
+		response.OptionIDs = append(response.OptionIDs, int64(option.ID))
+		if option.Attachment != nil {
+			if response.OptionAttachments == nil {
+				response.OptionAttachments = make([]*AttachmentResponse, len(poll.Options))
+			}
+			response.OptionAttachments[index] = NewAttachmentResponse(option.Attachment)
+		}
+		if option.Slot != nil {
+			response.Slots = append(response.Slots, PollSlotResponse{
+				StartsAt: option.Slot.StartsAt, EndsAt: option.Slot.EndsAt, TimeZone: option.Slot.TimeZone,
+			})
+		}
+		if option.Correct {
+			response.CorrectOptions = append(response.CorrectOptions, index)
+		}
+	}
+	for _, tag := range poll.Tags {
+		response.Tags = append(response.Tags, tag.Name)
+	}
+	return response
+}
+
+type PollScaleResponse struct {
+	Min    int            `json:"min"`
+	Max    int            `json:"max"`
+	Step   int            `json:"step"`
+	Labels map[int]string `json:"labels,omitempty"`
+}
+
+// MediaPath serves the blobs of the attachments by their keys
+const MediaPath = "/api/v1/media/"
+
+type AttachmentResponse struct {
+	ID           int64  `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+	ContentType  string `json:"contentType"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+}
+
+func NewAttachmentResponse(attachment *entities.Attachment) *AttachmentResponse {
+	if attachment == nil {
+		return nil
+	}
+
+	response := &AttachmentResponse{
+		ID:          int64(attachment.ID),
+		URL:         MediaPath + attachment.Key,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Width:       attachment.Width,
+		Height:      attachment.Height,
+	}
+	if len(attachment.ThumbnailKey) != 0 {
+		response.ThumbnailURL = MediaPath + attachment.ThumbnailKey
+	}
+	return response
+}
+
+type PollSlotResponse struct {
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	TimeZone string    `json:"timeZone"`
 }
 
 type StatisticsResponse struct {
 	PollID int      `json:"pollId"`
 	Votes  []string `json:"votes"`
 }
+
+type AuditEventResponse struct {
+	ID         int64          `json:"id"`
+	ActorID    int64          `json:"actorId"`
+	Action     string         `json:"action"`
+	TargetType string         `json:"targetType"`
+	TargetID   int64          `json:"targetId"`
+	RequestID  string         `json:"requestId"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+type VoteResponse struct {
+	Correct bool `json:"correct"`
+	Points  int  `json:"points"`
+}
+
+type LeaderboardEntryResponse struct {
+	UserID   int64 `json:"userId"`
+	Points   int64 `json:"points"`
+	Correct  int64 `json:"correct"`
+	Answered int64 `json:"answered"`
+}
+
+type VoterResponse struct {
+	UserID   int64     `json:"userId"`
+	OptionID int64     `json:"optionId,omitempty"`
+	Option   string    `json:"option"`
+	Other    string    `json:"other,omitempty"`
+	ActedAt  time.Time `json:"actedAt"`
+}
+
+type OtherAnswerResponse struct {
+	UserID  int64     `json:"userId"`
+	Text    string    `json:"text"`
+	Hidden  bool      `json:"hidden"`
+	ActedAt time.Time `json:"actedAt"`
+}
+
+type OtherAnswerFrequencyResponse struct {
+	Text  string `json:"text"`
+	Count uint64 `json:"count"`
+}
+
+type PromoteOtherAnswerResponse struct {
+	Votes int64 `json:"votes"` // moved into the new option
+}
+
+type ParticipantsResponse struct {
+	Users  []int64 `json:"users"`
+	Groups []int64 `json:"groups"`
+}
+
+func NewParticipantsResponse(participants *entities.Participants) ParticipantsResponse {
+	response := ParticipantsResponse{
+		Users:  make([]int64, 0, len(participants.Users)),
+		Groups: make([]int64, 0, len(participants.Groups)),
+	}
+	for _, user := range participants.Users {
+		response.Users = append(response.Users, int64(user))
+	}
+	for _, group := range participants.Groups {
+		response.Groups = append(response.Groups, int64(group))
+	}
+	return response
+}
+
+type CreateGroupResponse struct {
+	ID int64 `json:"id"`
+}
+
+type CreateSurveyResponse struct {
+	ID int64 `json:"id"`
+}
+
+type SurveyResponse struct {
+	ID        int64                    `json:"id"`
+	UserID    int64                    `json:"userId"`
+	Title     string                   `json:"title"`
+	Questions []SurveyQuestionResponse `json:"questions"`
+	CreatedAt time.Time                `json:"createdAt"`
+}
+
+type SurveyQuestionResponse struct {
+	Position int                  `json:"position"`
+	Poll     PollResponse         `json:"poll"`
+	Rules    []SurveyRuleResponse `json:"rules,omitempty"`
+}
+
+type SurveyRuleResponse struct {
+	OptionID int64 `json:"optionId"`
+	Option   int   `json:"option"` // index in the order of the options of the poll above
+	JumpTo   int   `json:"jumpTo"`
+}
+
+func NewSurveyResponse(survey *entities.Survey) SurveyResponse {
+	response := SurveyResponse{
+		ID:        int64(survey.ID),
+		UserID:    int64(survey.UserID),
+		Title:     survey.Title,
+		Questions: make([]SurveyQuestionResponse, 0, len(survey.Questions)),
+		CreatedAt: survey.CreatedAt,
+	}
+	for _, question := range survey.Questions {
+		questionResponse := SurveyQuestionResponse{Position: question.Position}
+		if question.Poll != nil {
+			questionResponse.Poll = NewPollResponse(question.Poll)
+		}
+		for _, rule := range question.Rules {
+			questionResponse.Rules = append(questionResponse.Rules, SurveyRuleResponse{
+				OptionID: int64(rule.OptionID), Option: rule.Option, JumpTo: rule.JumpTo,
+			})
+		}
+		response.Questions = append(response.Questions, questionResponse)
+	}
+	return response
+}
+
+type SurveyStatisticsResponse struct {
+	SurveyID    int64                              `json:"surveyId"`
+	Respondents uint64                             `json:"respondents"`
+	Questions   []SurveyQuestionStatisticsResponse `json:"questions"`
+}
+
+type SurveyQuestionStatisticsResponse struct {
+	Position       int     `json:"position"`
+	PollID         int64   `json:"pollId"`
+	Reached        uint64  `json:"reached"`
+	Answered       uint64  `json:"answered"`
+	CompletionRate float64 `json:"completionRate"`
+}
+
+type HealthResponse struct {
+	Status       string                     `json:"status"`
+	CheckedAt    time.Time                  `json:"checkedAt"`
+	Dependencies []DependencyHealthResponse `json:"dependencies"`
+}
+
+type DependencyHealthResponse struct {
+	Name     string `json:"name"`
+	Class    string `json:"class"`
+	Status   string `json:"status"`
+	Duration int64  `json:"durationMs"`
+	Error    string `json:"error,omitempty"`
+}
+
+func NewHealthResponse(report *health.Report) *HealthResponse {
+	response := &HealthResponse{
+		Status:       string(report.Status),
+		CheckedAt:    report.CheckedAt,
+		Dependencies: make([]DependencyHealthResponse, 0, len(report.Dependencies)),
+	}
+	for _, dependency := range report.Dependencies {
+		dependencyResponse := DependencyHealthResponse{
+			Name:     dependency.Name,
+			Class:    string(dependency.Class),
+			Status:   string(dependency.Status),
+			Duration: dependency.Duration.Milliseconds(),
+		}
+		if dependency.Err != nil {
+			dependencyResponse.Error = dependency.Err.Error()
+		}
+		response.Dependencies = append(response.Dependencies, dependencyResponse)
+	}
+	return response
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
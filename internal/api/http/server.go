@@ -8,11 +8,15 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/adaptor"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/gofiber/fiber/v3/middleware/pprof"
 	"go.uber.org/zap"
 
 	"github.com/mohammadne/porsesh/internal/api/http/handlers"
+	"github.com/mohammadne/porsesh/internal/api/http/middlewares"
+	"github.com/mohammadne/porsesh/internal/config"
 	"github.com/mohammadne/porsesh/internal/usecases"
+	"github.com/mohammadne/porsesh/pkg/observability/health"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
 )
 
 type Server struct {
@@ -22,21 +26,38 @@ type Server struct {
 	requestApp *fiber.App
 }
 
-func New(log *zap.Logger, feeds usecases.Feeds, pools usecases.Polls) *Server {
+func New(cfg *Config, log *zap.Logger, level zap.AtomicLevel, settings any, registry *metrics.Registry, health *health.Health, auditor usecases.Auditor, feeds usecases.Feeds, pools usecases.Polls, surveys usecases.Surveys,
+	attachments usecases.Attachments, groups usecases.Groups) *Server {
 	server := &Server{logger: log}
 
 	{ // monitoring handlers
 		server.monitorApp = fiber.New(fiber.Config{})
 
-		server.monitorApp.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
-		handlers.NewHealthz(server.monitorApp, log)
+		server.monitorApp.Get("/metrics", adaptor.HTTPHandler(registry.Handler()))
+		handlers.NewHealthz(server.monitorApp, log, health)
+
+		// the operational endpoints are guarded by the shared token, or by the loopback addresses
+		guard := middlewares.Admin(cfg.AdminToken)
+		server.monitorApp.Use("/debug/pprof", guard, pprof.New())
+
+		adminGroup := server.monitorApp.Group("admin", guard)
+		handlers.NewAdmin(adminGroup, log, level, settings, auditor)
+		handlers.NewAudit(adminGroup, log, auditor)
 	}
 
 	{ // requests handlers
-		server.requestApp = fiber.New(fiber.Config{})
+		// the body limit leaves room for the multipart overhead of the attachments
+		server.requestApp = fiber.New(fiber.Config{BodyLimit: usecases.MaxAttachmentSize + 1<<20})
+		server.requestApp.Use(middlewares.RequestID())
+		server.requestApp.Use(middlewares.Tracing())
+		server.requestApp.Use(middlewares.AccessLog(log, cfg.AccessLogSampling))
+		server.requestApp.Use(middlewares.Metrics(registry, config.Namespace, config.System))
 
 		apiGroup := server.requestApp.Group("api/v1")
 		handlers.NewPoll(apiGroup, log, feeds, pools)
+		handlers.NewSurvey(apiGroup, log, surveys)
+		handlers.NewAttachment(apiGroup, log, attachments)
+		handlers.NewGroup(apiGroup, log, groups)
 	}
 
 	return server
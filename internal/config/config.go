@@ -5,34 +5,64 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
-
-	"github.com/kelseyhightower/envconfig"
 )
 
-func Load(config any, environmentRaw string) (err error) {
+// Load fills the config by the layers below, each one overrides the previous ones:
+//   - the default tags of the fields, along with the defaults.env in the local environment
+//   - the YAML or TOML file of the path, if it's given
+//   - the environment variables
+//   - the *_FILE environment variables which point to the files of the secrets
+//
+// At last the config is validated, the failed variables and validations are all reported together.
+func Load(config any, environmentRaw, path string) (err error) {
 	loadEnvironment(environmentRaw)
 
+	prefix := strings.ToUpper(strings.ReplaceAll(System, "-", "_"))
+	variables := gatherVariables(prefix, reflect.TypeOf(config))
+
+	layers := map[string]string{}
+
 	switch EnvironmentValue {
 	case EnvironmentLocal:
-		if err := setDefaults(); err != nil {
-			return fmt.Errorf("error set default config: %v", err)
+		for key, value := range parseDefaults(defaults) {
+			layers[key] = value
 		}
 
 		defer func() {
 			fmt.Println("================ Loaded Configuration ================")
-			object, _ := json.MarshalIndent(config, "", "  ")
+			object, _ := json.MarshalIndent(Redact(config), "", "  ")
 			fmt.Println(string(object))
 			fmt.Println("======================================================")
 		}()
 	}
 
-	prefix := strings.ToUpper(strings.ReplaceAll(System, "-", "_"))
-	if err = envconfig.Process(prefix, config); err != nil {
-		return fmt.Errorf("error processing config via envconfig: %v", err)
+	if len(path) > 0 {
+		values, err := readFile(path, prefix, variables)
+		if err != nil {
+			return fmt.Errorf("error loading config file %s: %w", path, err)
+		}
+		for key, value := range values {
+			layers[key] = value
+		}
 	}
 
-	return nil
+	for key, value := range layers {
+		if _, ok := os.LookupEnv(key); ok {
+			continue // the environment overrides the defaults and the file
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("error set environment %s: %v", key, err)
+		}
+	}
+
+	if err = resolveSecretFiles(variables); err != nil {
+		return fmt.Errorf("error resolving secret files: %w", err)
+	}
+
+	errs := process(prefix, reflect.ValueOf(config))
+	return invalid(append(errs, validate(prefix, reflect.ValueOf(config))...))
 }
 
 const seperator = "_"
@@ -40,20 +70,24 @@ const seperator = "_"
 //go:embed defaults.env
 var defaults string
 
-func setDefaults() error {
-	lines := strings.Split(defaults, "\n")
-	for _, line := range lines {
-		splits := strings.Split(line, "=")
-		if len(splits) < 2 {
+// parseDefaults reads the lines of KEY=VALUE, the value is the rest of the line after the first '='
+// so it may contain '=' itself. The blank lines and the comments starting by '#' are skipped.
+func parseDefaults(content string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
 			continue
 		}
 
-		key := strings.ReplaceAll(splits[0], seperator+seperator, seperator)
-		err := os.Setenv(key, splits[1])
-		if err != nil {
-			return fmt.Errorf("error set environment %s: %v", key, err)
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
 		}
+
+		key = strings.ReplaceAll(strings.TrimSpace(key), seperator+seperator, seperator)
+		values[key] = value
 	}
 
-	return nil
+	return values
 }
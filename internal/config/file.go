@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile reads the YAML or TOML config file into the values of the variables, the nesting of the
+// file follows the nesting of the config. The unknown keys are reported as errors.
+func readFile(path, prefix string, variables []variable) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the config file: %v", err)
+	}
+
+	document := map[string]any{}
+	switch extension := strings.ToLower(filepath.Ext(path)); extension {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &document)
+	case ".toml":
+		err = toml.Unmarshal(content, &document)
+	default:
+		return nil, fmt.Errorf("unknown config file extension %q, expected .yaml, .yml or .toml", extension)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the config file: %v", err)
+	}
+
+	byName := make(map[string]variable, len(variables))
+	for _, variable := range variables {
+		byName[normalize(variable.Key)] = variable
+	}
+
+	values := map[string]string{}
+	var errs []error
+	flattenFile(document, prefix, "", byName, values, &errs)
+	return values, errors.Join(errs...)
+}
+
+func flattenFile(document map[string]any, prefix, path string, variables map[string]variable, values map[string]string, errs *[]error) {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, fieldPath := document[key], strings.TrimPrefix(path+"."+key, ".")
+		variable, found := variables[normalize(prefix+"."+fieldPath)]
+
+		if nested, ok := value.(map[string]any); ok && (!found || variable.Kind != reflect.Map) {
+			flattenFile(nested, prefix, fieldPath, variables, values, errs)
+			continue
+		}
+		if !found {
+			*errs = append(*errs, fmt.Errorf("unknown key %q in the config file", fieldPath))
+			continue
+		}
+
+		values[variable.Key] = fileValue(value)
+	}
+}
+
+// fileValue formats the value the same as it's given by the environment variables,
+// the lists are separated by commas and the maps are as key:value,key:value
+func fileValue(value any) string {
+	switch typed := value.(type) {
+	case []any:
+		items := make([]string, 0, len(typed))
+		for _, item := range typed {
+			items = append(items, fileValue(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]any:
+		entries := make([]string, 0, len(typed))
+		for key, item := range typed {
+			entries = append(entries, key+":"+fileValue(item))
+		}
+		sort.Strings(entries)
+		return strings.Join(entries, ",")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// secretFileSuffix marks the variables which point to the files of the secrets, e.g. PORSESH_POSTGRES_PASSWORD_FILE
+const secretFileSuffix = "_FILE"
+
+// resolveSecretFiles sets the variables by the contents of the files they point to, it overrides the variables
+// themselves. The trailing newlines of the files are trimmed.
+func resolveSecretFiles(variables []variable) error {
+	var errs []error
+	for _, variable := range variables {
+		path, ok := os.LookupEnv(variable.Key + secretFileSuffix)
+		if !ok || len(path) == 0 {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error reading %s: %v", variable.Key+secretFileSuffix, err))
+			continue
+		}
+
+		if err := os.Setenv(variable.Key, strings.TrimRight(string(content), "\r\n")); err != nil {
+			errs = append(errs, fmt.Errorf("error set environment %s: %v", variable.Key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testDatabase struct {
+	Host     string `required:"true"`
+	Port     int    `default:"5432"`
+	Password string `secret:"true"`
+}
+
+func (cfg *testDatabase) Validate() error {
+	var errs []error
+	if cfg.Port < 1 || cfg.Port > 65535 {
+		errs = append(errs, errors.New("port is out of range"))
+	}
+	if len(cfg.Password) == 0 {
+		errs = append(errs, errors.New("password is empty"))
+	}
+	return errors.Join(errs...)
+}
+
+type testConfig struct {
+	Database *testDatabase
+	Loggers  []string
+	Tags     map[string]string
+	MaxAge   time.Duration `split_words:"true" default:"1h"`
+}
+
+// cleanEnvironment unsets the variables the loads set, as they're set on the process
+func cleanEnvironment(t *testing.T) {
+	t.Cleanup(func() {
+		for _, variable := range os.Environ() {
+			if key, _, _ := strings.Cut(variable, "="); strings.HasPrefix(key, "PORSESH_") {
+				os.Unsetenv(key)
+			}
+		}
+	})
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write file has error %s", err.Error())
+	}
+	return path
+}
+
+func TestParseDefaults(t *testing.T) {
+	values := parseDefaults("# comment\nPORSESH__POSTGRES__PASSWORD=a=b==\n\nPORSESH__LOGGER__SENTRY_TAGS=\nBROKEN\n")
+
+	expected := map[string]string{"PORSESH_POSTGRES_PASSWORD": "a=b==", "PORSESH_LOGGER_SENTRY_TAGS": ""}
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("defaults are %v, expected %v", values, expected)
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	files := map[string]string{
+		"porsesh.yaml": "database:\n  host: file-host\n  port: 6432\nloggers: [std, file]\ntags:\n  team: polls\nmax_age: 2h\n",
+		"porsesh.toml": "loggers = [\"std\", \"file\"]\nmaxAge = \"2h\"\n[database]\nhost = \"file-host\"\nport = 6432\n[tags]\nteam = \"polls\"\n",
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			cleanEnvironment(t)
+			t.Setenv("PORSESH_DATABASE_PORT", "7432") // the environment overrides the file
+			t.Setenv("PORSESH_DATABASE_PASSWORD_FILE", writeFile(t, "password", "s3cr=t\n"))
+
+			var cfg testConfig
+			if err := Load(&cfg, string(EnvironmentProd), writeFile(t, name, content)); err != nil {
+				t.Fatalf("load has error %s", err.Error())
+			}
+
+			expected := testConfig{
+				Database: &testDatabase{Host: "file-host", Port: 7432, Password: "s3cr=t"},
+				Loggers:  []string{"std", "file"},
+				Tags:     map[string]string{"team": "polls"},
+				MaxAge:   2 * time.Hour,
+			}
+			if !reflect.DeepEqual(cfg, expected) {
+				t.Fatalf("config is %+v %+v, expected %+v %+v", cfg, cfg.Database, expected, expected.Database)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Run("unknown_key", func(t *testing.T) {
+		cleanEnvironment(t)
+		var cfg testConfig
+		err := Load(&cfg, string(EnvironmentProd), writeFile(t, "porsesh.yaml", "database:\n  hots: localhost\n"))
+		if err == nil || !strings.Contains(err.Error(), `"database.hots"`) {
+			t.Fatalf("error is %v, expected the unknown key", err)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		cleanEnvironment(t)
+		t.Setenv("PORSESH_DATABASE_HOST", "localhost")
+		t.Setenv("PORSESH_DATABASE_PORT", "70000")
+
+		var cfg testConfig
+		err := Load(&cfg, string(EnvironmentProd), "")
+		if err == nil {
+			t.Fatal("invalid config should be rejected")
+		}
+		for _, expected := range []string{"PORSESH_DATABASE: port is out of range", "PORSESH_DATABASE: password is empty"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Fatalf("error is %q, expected all of the failures to be reported", err.Error())
+			}
+		}
+	})
+	t.Run("variables_and_validation", func(t *testing.T) {
+		cleanEnvironment(t)
+		t.Setenv("PORSESH_DATABASE_PORT", "70000")
+		t.Setenv("PORSESH_MAX_AGE", "an hour")
+
+		var cfg testConfig
+		err := Load(&cfg, string(EnvironmentProd), "")
+		if err == nil {
+			t.Fatal("invalid config should be rejected")
+		}
+		for _, expected := range []string{
+			"required key PORSESH_DATABASE_HOST missing value",
+			"assigning PORSESH_MAX_AGE to MaxAge",
+			"PORSESH_DATABASE: port is out of range",
+		} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Fatalf("error is %q, expected %q among the failures", err.Error(), expected)
+			}
+		}
+	})
+}
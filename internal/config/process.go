@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// process fills the config by envconfig variable by variable, as envconfig stops at the first failed
+// variable while all of them are reported together. The nested structs are walked the same as envconfig
+// does, their nil pointers are allocated and only their leaves are processed.
+func process(prefix string, value reflect.Value) []error {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+
+	var errs []error
+	for index := range value.NumField() {
+		field := value.Type().Field(index)
+		if !field.IsExported() || field.Tag.Get("ignored") == "true" {
+			continue
+		}
+
+		key := prefix + "_" + variableName(field)
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && !decodable(fieldType) {
+			errs = append(errs, process(key, value.Field(index))...)
+			continue
+		}
+
+		if err := processVariable(key, field, value.Field(index)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// processVariable runs envconfig over a struct of the single field, named by the whole key of its variable
+func processVariable(key string, field reflect.StructField, value reflect.Value) error {
+	tag := fmt.Sprintf(`envconfig:%q default:%q required:%q`, key, field.Tag.Get("default"), field.Tag.Get("required"))
+	single := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: field.Name,
+		Type: field.Type,
+		Tag:  reflect.StructTag(tag),
+	}}))
+
+	if err := envconfig.Process("", single.Interface()); err != nil {
+		return err
+	}
+	value.Set(single.Elem().Field(0))
+	return nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+)
+
+var (
+	durationType  = reflect.TypeOf(time.Duration(0))
+	redactionType = reflect.TypeOf(&logger.RedactionConfig{})
+)
+
+// Redact copies the loaded config into plain maps and slices, ready to be marshaled. The values of the fields
+// tagged by `secret:"true"` or named as secrets by the redaction policy of the logger are replaced unless
+// they're empty, and the sensitive parts of the other strings are replaced by the policy. The policy is the
+// one the logger is built by, it's extended by the redaction config of the logger found in the config.
+func Redact(config any) any {
+	value := reflect.ValueOf(config)
+	return redact(value, redactionPolicy(value))
+}
+
+// redactionPolicy builds the policy of the first redaction config in the value, the default policy is used
+// if there is none. The invalid patterns are rejected by the validation, the default policy is used for them.
+func redactionPolicy(value reflect.Value) *logger.Policy {
+	if redaction := findRedaction(value); redaction != nil {
+		if policy, err := logger.NewPolicy(redaction.Fields, redaction.Values); err == nil {
+			return policy
+		}
+	}
+	return logger.DefaultPolicy()
+}
+
+func findRedaction(value reflect.Value) *logger.RedactionConfig {
+	if !value.IsValid() {
+		return nil
+	} else if value.Type() == redactionType {
+		return value.Interface().(*logger.RedactionConfig)
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			return findRedaction(value.Elem())
+		}
+	case reflect.Struct:
+		for index := range value.NumField() {
+			if !value.Type().Field(index).IsExported() {
+				continue
+			}
+			if redaction := findRedaction(value.Field(index)); redaction != nil {
+				return redaction
+			}
+		}
+	}
+	return nil
+}
+
+func redact(value reflect.Value, policy *logger.Policy) any {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return redact(value.Elem(), policy)
+	case reflect.Struct:
+		fields := make(map[string]any, value.NumField())
+		for index := range value.NumField() {
+			field := value.Type().Field(index)
+			if !field.IsExported() {
+				continue
+			}
+
+			secret := field.Tag.Get("secret") == "true" || policy.Field(field.Name)
+			if secret && !value.Field(index).IsZero() {
+				fields[field.Name] = logger.Redacted
+				continue
+			}
+			fields[field.Name] = redact(value.Field(index), policy)
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		items := make([]any, 0, value.Len())
+		for index := range value.Len() {
+			items = append(items, redact(value.Index(index), policy))
+		}
+		return items
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		entries := make(map[string]any, value.Len())
+		for iterator := value.MapRange(); iterator.Next(); {
+			entries[fmt.Sprint(iterator.Key().Interface())] = redact(iterator.Value(), policy)
+		}
+		return entries
+	}
+
+	if value.Type() == durationType {
+		return value.Interface().(time.Duration).String()
+	}
+	if value.Kind() == reflect.String {
+		return policy.Value(value.String())
+	}
+	return value.Interface()
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Validator is implemented by the configs which check their values semantically, beyond their types,
+// e.g. the ports are in range or the sub-configs of the enabled features are given
+type Validator interface {
+	Validate() error
+}
+
+// validate runs the validators of the config and of all of its sub-configs, the errors are
+// reported together along with the variable prefixes of their configs
+func validate(prefix string, value reflect.Value) []error {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []error
+	if value.CanAddr() {
+		if validator, ok := value.Addr().Interface().(Validator); ok {
+			errs = append(errs, prefixed(prefix, validator.Validate())...)
+		}
+	}
+
+	for index := range value.NumField() {
+		field := value.Type().Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		errs = append(errs, validate(prefix+"_"+variableName(field), value.Field(index))...)
+	}
+	return errs
+}
+
+func prefixed(prefix string, err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, prefixed(prefix, err)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("%s: %w", prefix, err)}
+}
+
+// invalid is the error of the failed validations, it's joined by errors.Join
+func invalid(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// variable is an environment variable of a field of the config, the same as envconfig names it
+type variable struct {
+	Key  string
+	Kind reflect.Kind
+}
+
+// the same expressions as envconfig splits the words of the field names by
+var (
+	gatherRegexp  = regexp.MustCompile("([^A-Z]+|[A-Z]+[^A-Z]+|[A-Z]+)")
+	acronymRegexp = regexp.MustCompile("([A-Z]+)([A-Z][^A-Z]+)")
+)
+
+// gatherVariables lists the variables of the leaves of the config, the nested structs only prefix their fields
+func gatherVariables(prefix string, configType reflect.Type) []variable {
+	for configType.Kind() == reflect.Pointer {
+		configType = configType.Elem()
+	}
+
+	var variables []variable
+	for index := range configType.NumField() {
+		field := configType.Field(index)
+		if !field.IsExported() || field.Tag.Get("ignored") == "true" {
+			continue
+		}
+
+		key := prefix + "_" + variableName(field)
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && !decodable(fieldType) {
+			variables = append(variables, gatherVariables(key, fieldType)...)
+			continue
+		}
+
+		variables = append(variables, variable{Key: key, Kind: fieldType.Kind()})
+	}
+	return variables
+}
+
+func variableName(field reflect.StructField) string {
+	if alternative := field.Tag.Get("envconfig"); len(alternative) > 0 {
+		return strings.ToUpper(alternative)
+	}
+	if field.Tag.Get("split_words") != "true" {
+		return strings.ToUpper(field.Name)
+	}
+
+	var words []string
+	for _, match := range gatherRegexp.FindAllStringSubmatch(field.Name, -1) {
+		if acronym := acronymRegexp.FindStringSubmatch(match[0]); len(acronym) == 3 {
+			words = append(words, acronym[1], acronym[2])
+		} else {
+			words = append(words, match[0])
+		}
+	}
+	return strings.ToUpper(strings.Join(words, "_"))
+}
+
+var textUnmarshalerType = reflect.TypeOf((*interface{ UnmarshalText([]byte) error })(nil)).Elem()
+
+// decodable structs are decoded from a single variable, e.g. time.Time
+func decodable(structType reflect.Type) bool {
+	return reflect.PointerTo(structType).Implements(textUnmarshalerType)
+}
+
+// normalize lets the keys of the config files be written in any case and with or without underscores,
+// e.g. max_size, maxSize and MAXSIZE are all of the variable MAX_SIZE
+func normalize(key string) string {
+	return strings.ToUpper(strings.NewReplacer("_", "", "-", "", ".", "").Replace(key))
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+type AttachmentID int64
+
+// Attachment is the uploaded media of a poll or an option, the blobs are referenced by their keys
+type Attachment struct {
+	ID           AttachmentID
+	UserID       UserID
+	Key          string
+	ThumbnailKey string // images only
+	ContentType  string
+	Size         int64
+	Width        int // images only
+	Height       int // images only
+	CreatedAt    time.Time
+}
@@ -0,0 +1,67 @@
+package entities
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditActionCreatePoll AuditAction = "poll.create"
+	AuditActionVotePoll   AuditAction = "poll.vote"
+	AuditActionSkipPoll   AuditAction = "poll.skip"
+	// AuditActionPublishPoll is either the publishing or the scheduling of a draft
+	AuditActionPublishPoll AuditAction = "poll.publish"
+
+	AuditActionAddPollParticipants    AuditAction = "poll.participants.add"
+	AuditActionRemovePollParticipants AuditAction = "poll.participants.remove"
+
+	AuditActionHideOtherAnswer    AuditAction = "poll.other.hide"
+	AuditActionUnhideOtherAnswer  AuditAction = "poll.other.unhide"
+	AuditActionPromoteOtherAnswer AuditAction = "poll.other.promote"
+
+	AuditActionUploadAttachment AuditAction = "attachment.upload"
+
+	AuditActionCreateSurvey  AuditAction = "survey.create"
+	AuditActionRespondSurvey AuditAction = "survey.respond"
+
+	AuditActionCreateGroup        AuditAction = "group.create"
+	AuditActionAddGroupMembers    AuditAction = "group.members.add"
+	AuditActionRemoveGroupMembers AuditAction = "group.members.remove"
+
+	AuditActionSetLogLevel AuditAction = "loglevel.set"
+)
+
+type AuditTarget string
+
+const (
+	AuditTargetPoll   AuditTarget = "poll"
+	AuditTargetSurvey AuditTarget = "survey"
+	AuditTargetGroup  AuditTarget = "group"
+
+	AuditTargetAttachment AuditTarget = "attachment"
+	AuditTargetLogLevel   AuditTarget = "loglevel"
+)
+
+type AuditEvent struct {
+	ID         int64
+	ActorID    UserID
+	Action     AuditAction
+	TargetType AuditTarget
+	TargetID   int64
+	RequestID  string
+	Metadata   map[string]any
+	CreatedAt  time.Time
+}
+
+// AuditFilter narrows down the audit events, zero values are ignored
+type AuditFilter struct {
+	ActorID    UserID
+	Action     AuditAction
+	TargetType AuditTarget
+	TargetID   int64
+	RequestID  string
+	From       time.Time
+	To         time.Time
+	// BeforeCreatedAt and BeforeID are the key of the last listed event, the listing goes on with the older ones
+	BeforeCreatedAt time.Time
+	BeforeID        int64
+}
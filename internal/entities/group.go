@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+type GroupID int64
+
+// Group is a named list of users, its owner invites them to the private polls at once
+type Group struct {
+	ID        GroupID
+	OwnerID   UserID
+	Name      string
+	Members   []UserID
+	CreatedAt time.Time
+}
+
+// Participants are the allow-list of a private poll, the members of the groups are included
+type Participants struct {
+	Users  []UserID
+	Groups []GroupID
+}
+
+func (p *Participants) IsEmpty() bool {
+	return len(p.Users) == 0 && len(p.Groups) == 0
+}
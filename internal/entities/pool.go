@@ -4,18 +4,159 @@ import "time"
 
 type PollID int64
 
+type PollOptionID int64
+
+type PollVisibility string
+
+const (
+	// PollVisibilityPublic polls are listed in every user's feed
+	PollVisibilityPublic PollVisibility = "public"
+	// PollVisibilityUnlisted polls are only reachable by their share link
+	PollVisibilityUnlisted PollVisibility = "unlisted"
+	// PollVisibilityPrivate polls are only reachable by the owner and the invited participants
+	PollVisibilityPrivate PollVisibility = "private"
+)
+
+func (v PollVisibility) IsValid() bool {
+	switch v {
+	case PollVisibilityPublic, PollVisibilityUnlisted, PollVisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+type PollType string
+
+const (
+	// PollTypeSingle polls take a single option from every voter
+	PollTypeSingle PollType = "single"
+	// PollTypeQuiz polls have correct options and score the voters on them
+	PollTypeQuiz PollType = "quiz"
+	// PollTypeScale polls take a numeric value on a scale rather than an option
+	PollTypeScale PollType = "scale"
+	// PollTypeSchedule polls have time slots as options and take the availability for every one of them
+	PollTypeSchedule PollType = "schedule"
+)
+
+func (t PollType) IsValid() bool {
+	switch t {
+	case PollTypeSingle, PollTypeQuiz, PollTypeScale, PollTypeSchedule:
+		return true
+	}
+	return false
+}
+
+type PollStatus string
+
+const (
+	// PollStatusDraft polls are only reachable by the owner until they are published
+	PollStatusDraft PollStatus = "draft"
+	// PollStatusScheduled polls are drafts which get published at their PublishAt
+	PollStatusScheduled PollStatus = "scheduled"
+	// PollStatusPublished polls are open to the voters and listed in the feeds
+	PollStatusPublished PollStatus = "published"
+)
+
+func (s PollStatus) IsValid() bool {
+	switch s {
+	case PollStatusDraft, PollStatusScheduled, PollStatusPublished:
+		return true
+	}
+	return false
+}
+
+type ResultsVisibility string
+
+const (
+	// ResultsVisibilityAlways shows the results to anyone who can access the poll
+	ResultsVisibilityAlways ResultsVisibility = "always"
+	// ResultsVisibilityAfterVote shows the results once the user has voted or skipped
+	ResultsVisibilityAfterVote ResultsVisibility = "after_vote"
+	// ResultsVisibilityAfterClose shows the results once the poll is closed
+	ResultsVisibilityAfterClose ResultsVisibility = "after_close"
+	// ResultsVisibilityOwner shows the results only to the owner
+	ResultsVisibilityOwner ResultsVisibility = "owner"
+)
+
+func (v ResultsVisibility) IsValid() bool {
+	switch v {
+	case ResultsVisibilityAlways, ResultsVisibilityAfterVote, ResultsVisibilityAfterClose, ResultsVisibilityOwner:
+		return true
+	}
+	return false
+}
+
 type Poll struct {
-	ID        PollID
-	Title     string
-	UserID    UserID
-	CreatedAt time.Time
-	Options   []PollOption
-	Tags      []PollTag
+	ID                PollID
+	Slug              string
+	Type              PollType
+	Status            PollStatus
+	Title             string
+	Description       string // Markdown, optional
+	DescriptionHTML   string // the description rendered to sanitized HTML
+	UserID            UserID
+	Visibility        PollVisibility
+	ResultsVisibility ResultsVisibility
+	Anonymous         bool          // fixed at creation, the voters can't be linked to their choices
+	TimeLimit         time.Duration // quizzes only, counted from the publishing and zero means no limit
+	QuizSet           string        // quizzes only, the named set of the quiz leaderboards
+	OtherMaxLength    int           // max length of the "other" free-text answers, zero disables them
+	ShuffleOptions    bool          // single and quiz polls only, every voter sees the options in an own order
+	ClosesAt          time.Time     // zero means the poll never closes
+	PublishAt         time.Time     // scheduled polls only
+	PublishedAt       time.Time     // zero while the poll is a draft, the feeds are ordered by it
+	CreatedAt         time.Time
+	Attachment        *Attachment // only its ID is given on the creation
+	Options           []PollOption
+	Scale             *PollScale // scale polls only
+	Tags              []PollTag
+	Participants      Participants // private polls only
+}
+
+func (p *Poll) IsPublished() bool {
+	return !p.PublishedAt.IsZero()
+}
+
+// IsClosed tells whether the poll doesn't take votes anymore, the time limit of the drafts
+// doesn't run until they are published
+func (p *Poll) IsClosed(now time.Time) bool {
+	if p.TimeLimit > 0 && p.IsPublished() && !now.Before(p.PublishedAt.Add(p.TimeLimit)) {
+		return true
+	}
+	return !p.ClosesAt.IsZero() && !now.Before(p.ClosesAt)
 }
 
 type PollOption struct {
-	Content string
-	Sort    int
+	ID         PollOptionID // stable, unlike the index which depends on the order and the voter
+	Content    string
+	Sort       int
+	Correct    bool        // quizzes only, stays false until the poll is closed
+	Slot       *PollSlot   // schedule polls only
+	Attachment *Attachment // only its ID is given on the creation
+}
+
+// PollSlot is the time range of an option of the schedule polls
+type PollSlot struct {
+	StartsAt time.Time
+	EndsAt   time.Time
+	TimeZone string // IANA name of the zone the slot is proposed in
+}
+
+// PollScale are the values from Min to Max (both included) by Step
+type PollScale struct {
+	Min    int
+	Max    int
+	Step   int
+	Labels map[int]string // optional label of the values
+}
+
+func (s *PollScale) Contains(value int) bool {
+	return value >= s.Min && value <= s.Max && (value-s.Min)%s.Step == 0
+}
+
+// IsNPS tells whether the scale is the 0 to 10 one of the net promoter score
+func (s *PollScale) IsNPS() bool {
+	return s.Min == 0 && s.Max == 10 && s.Step == 1
 }
 
 type PollTag struct {
@@ -23,11 +164,53 @@ type PollTag struct {
 }
 
 type PollStatistics struct {
-	PoolID PollID
-	Votes  []PollStatisticsVote
+	PoolID   PollID
+	Votes    []PollStatisticsVote
+	Scale    *PollScaleStatistics    // scale polls only
+	Schedule *PollScheduleStatistics // schedule polls only
+	Other    *uint64                 // the "other" free-text answers, only if the poll allows them
 }
 
 type PollStatisticsVote struct {
-	Option string
-	Count  uint64
+	OptionID PollOptionID `json:"optionId"`
+	Option   string
+	Count    uint64
+	Correct  *bool // nil unless the poll is a closed quiz
+}
+
+type PollScaleStatistics struct {
+	Distribution []PollScaleBucket // every value of the scale in order
+	Count        uint64
+	Mean         float64
+	Median       float64
+	StdDev       float64
+	NPS          *float64 // 0 to 10 scales only, promoters minus detractors in percent
+}
+
+type PollScaleBucket struct {
+	Value int
+	Label string
+	Count uint64
+}
+
+type PollScheduleStatistics struct {
+	Slots []PollScheduleSlot // in the order of the options
+	Best  []int              // indexes of the slots anyone is available at, by yes and then if-need-be counts
+	// BestOptionIDs are the option IDs of the best slots in the same order
+	BestOptionIDs []PollOptionID      `json:"bestOptionIds"`
+	Matrix        []PollScheduleVoter // the availability of every voter
+}
+
+type PollScheduleSlot struct {
+	OptionID PollOptionID `json:"optionId"`
+	Option   string
+	Slot     PollSlot
+	Yes      uint64
+	IfNeedBe uint64
+	No       uint64
+}
+
+type PollScheduleVoter struct {
+	UserID       UserID
+	Availability []Availability // in the order of the slots
 }
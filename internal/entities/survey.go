@@ -0,0 +1,52 @@
+package entities
+
+import "time"
+
+type SurveyID int64
+
+type Survey struct {
+	ID        SurveyID
+	UserID    UserID
+	Title     string
+	Questions []SurveyQuestion
+	CreatedAt time.Time
+}
+
+// SurveyQuestion is a poll placed on the survey, positions start from 1
+type SurveyQuestion struct {
+	Position int
+	PollID   PollID
+	Poll     *Poll // only filled when retrieving the survey
+	Rules    []SurveyRule
+}
+
+// SurveyRule jumps to another question once the option is chosen, the option is given either by its ID
+// or by its index. The index is in the original order of the options on the creation, and in the order
+// the viewer is shown them (which may be shuffled) on the retrieval.
+type SurveyRule struct {
+	OptionID PollOptionID // taken over the index when given
+	Option   int          // deprecated in favor of OptionID
+	JumpTo   int          // position of the question, zero ends the survey
+}
+
+// SurveyAnswer is the answer to the question at the position, nil ballot means skipped
+type SurveyAnswer struct {
+	Position int
+	Ballot   *Ballot
+}
+
+type SurveyStatistics struct {
+	SurveyID    SurveyID
+	Respondents uint64
+	Questions   []SurveyQuestionStatistics
+}
+
+// SurveyQuestionStatistics tells how many respondents reached the question through the
+// skip logic and how many of them answered it rather than skipping
+type SurveyQuestionStatistics struct {
+	Position       int
+	PollID         PollID
+	Reached        uint64
+	Answered       uint64
+	CompletionRate float64 // answered over reached
+}
@@ -1,6 +1,71 @@
 package entities
 
+import "time"
+
 type Vote struct {
 	UserID UserID
 	PollID PollID
 }
+
+// Voter is the attributed vote of a user, anonymous polls never expose them
+type Voter struct {
+	UserID   UserID
+	OptionID PollOptionID // zero for the values and the free-text answers
+	Option   string
+	Other    string // the free-text answer, if the "other" choice is taken
+	ActedAt  time.Time
+}
+
+// OtherAnswer is the free-text answer of a voter to the "other" choice
+type OtherAnswer struct {
+	UserID  UserID
+	Text    string
+	Hidden  bool // by the moderation
+	ActedAt time.Time
+}
+
+type OtherAnswerFrequency struct {
+	Text  string // lowercased and trimmed
+	Count uint64
+}
+
+type Availability string
+
+const (
+	AvailabilityYes      Availability = "yes"
+	AvailabilityIfNeedBe Availability = "if_need_be"
+	AvailabilityNo       Availability = "no"
+)
+
+func (a Availability) IsValid() bool {
+	switch a {
+	case AvailabilityYes, AvailabilityIfNeedBe, AvailabilityNo:
+		return true
+	}
+	return false
+}
+
+// Ballot is the choice of a voter, the fields which matter depend on the type of the poll
+type Ballot struct {
+	OptionID PollOptionID // the stable ID of the option, taken over the index when given
+	Option   *int         // index of the option, deprecated in favor of OptionID
+	Value    *int         // scale polls only, required by them
+	Other    string       // the free-text answer, chosen over the option when given
+	// schedule polls only, the availability for every slot in the order of the options
+	Availability []Availability
+}
+
+// VoteResult is the outcome of a vote, only quizzes have something to tell
+type VoteResult struct {
+	Quiz    bool
+	Correct bool
+	Points  int
+}
+
+// LeaderboardEntry is the standing of a user over a tag or a set of quizzes
+type LeaderboardEntry struct {
+	UserID   UserID
+	Points   int64
+	Correct  int64
+	Answered int64
+}
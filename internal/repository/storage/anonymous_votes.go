@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mohammadne/porsesh/pkg/databases/postgres"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+// AnonymousBallot records that someone has acted on an anonymous poll, the chosen option (if any)
+// is counted by IncrementAnonymousTally apart from it, so nothing in the database links the voter to the choice
+type AnonymousBallot struct {
+	PollID    int64
+	UserID    int64
+	VoterHash string
+}
+
+var (
+	ErrInsertingAnonymousBallot = errors.New("")
+)
+
+const (
+	queryCreateAnonymousBallot = `
+	INSERT INTO anonymous_ballots (poll_id, voter_hash)
+	VALUES ($1, $2)`
+
+	queryIncrementAnonymousDailyActions = `
+	INSERT INTO anonymous_daily_actions (user_id, acted_on, actions)
+	VALUES ($1, CURRENT_DATE, 1)
+	ON CONFLICT (user_id, acted_on) DO UPDATE SET actions = anonymous_daily_actions.actions + 1`
+)
+
+func (v *votes) CreateAnonymousBallot(ctx context.Context, tx *sqlx.Tx, ballot *AnonymousBallot) (err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "create_anonymous_ballot")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "create_anonymous_ballot", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "create_anonymous_ballot", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "create_anonymous_ballot")
+	}(time.Now())
+
+	_, err = tx.ExecContext(ctx, queryCreateAnonymousBallot, ballot.PollID, ballot.VoterHash)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == postgres.ForeignKeyViolatedCode {
+			return ErrCreateVotePollNotExists
+		} else if ok && pgErr.Code == postgres.UniqueConstraintViolatedCode {
+			return ErrCreateVoteAlreadyExists
+		}
+		return errors.Join(ErrInsertingAnonymousBallot, err)
+	}
+
+	_, err = tx.ExecContext(ctx, queryIncrementAnonymousDailyActions, ballot.UserID)
+	if err != nil {
+		return errors.Join(ErrInsertingAnonymousBallot, err)
+	}
+
+	return nil
+}
+
+var (
+	ErrDeletingAnonymousBallot = errors.New("")
+)
+
+const (
+	queryDeleteAnonymousBallot = `
+	DELETE FROM anonymous_ballots
+	WHERE poll_id = $1 AND voter_hash = $2`
+
+	queryDecrementAnonymousDailyActions = `
+	UPDATE anonymous_daily_actions SET actions = actions - 1
+	WHERE user_id = $1 AND acted_on = CURRENT_DATE AND actions > 0`
+)
+
+// RevokeAnonymousBallot takes back the ballot whose option couldn't be tallied, along with its daily action,
+// so the voter can cast it again
+func (v *votes) RevokeAnonymousBallot(ctx context.Context, tx *sqlx.Tx, ballot *AnonymousBallot) (err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "revoke_anonymous_ballot")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "revoke_anonymous_ballot", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "revoke_anonymous_ballot", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "revoke_anonymous_ballot")
+	}(time.Now())
+
+	_, err = tx.ExecContext(ctx, queryDeleteAnonymousBallot, ballot.PollID, ballot.VoterHash)
+	if err != nil {
+		return errors.Join(ErrDeletingAnonymousBallot, err)
+	}
+
+	_, err = tx.ExecContext(ctx, queryDecrementAnonymousDailyActions, ballot.UserID)
+	if err != nil {
+		return errors.Join(ErrDeletingAnonymousBallot, err)
+	}
+
+	return nil
+}
+
+var (
+	ErrIncrementingAnonymousTally = errors.New("")
+)
+
+const queryIncrementAnonymousTally = `
+INSERT INTO anonymous_tallies (option_id, poll_id, votes)
+VALUES ($1, $2, 1)
+ON CONFLICT (option_id) DO UPDATE SET votes = anonymous_tallies.votes + 1`
+
+// IncrementAnonymousTally counts the option of an anonymous ballot, it must run apart from the transaction
+// of the ballot, otherwise both rows share the transaction id (xmin) and the voter is linked to the option
+func (v *votes) IncrementAnonymousTally(ctx context.Context, pollID, optionID int64) (err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "increment_anonymous_tally")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "increment_anonymous_tally", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "increment_anonymous_tally", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "increment_anonymous_tally")
+	}(time.Now())
+
+	_, err = v.db.ExecContext(ctx, queryIncrementAnonymousTally, optionID, pollID)
+	if err != nil {
+		return errors.Join(ErrIncrementingAnonymousTally, err)
+	}
+
+	return nil
+}
+
+var (
+	errQueryHasAnonymousBallot = errors.New("")
+)
+
+const queryHasAnonymousBallot = `
+SELECT EXISTS (
+	SELECT 1 FROM anonymous_ballots WHERE poll_id = $1 AND voter_hash = $2
+)`
+
+func (v *votes) HasAnonymousBallot(ctx context.Context, pollID int64, voterHash string) (result bool, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "has_anonymous_ballot")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "has_anonymous_ballot", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "has_anonymous_ballot", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "has_anonymous_ballot")
+	}(time.Now())
+
+	err = v.db.QueryRowContext(ctx, queryHasAnonymousBallot, pollID, voterHash).Scan(&result)
+	if err != nil {
+		return false, errors.Join(errQueryHasAnonymousBallot, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errQueryGetAnonymousTallies              = errors.New("")
+	errScanningTallyInGetAnonymousTallies    = errors.New("")
+	errIteratingTalliesInGetAnonymousTallies = errors.New("")
+)
+
+const queryGetAnonymousTallies = `
+SELECT option_id, votes
+FROM anonymous_tallies
+WHERE poll_id = $1`
+
+// GetAnonymousTallies returns the number of votes per option id of the anonymous poll
+func (v *votes) GetAnonymousTallies(ctx context.Context, pollID int64) (result map[int64]uint64, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "get_anonymous_tallies")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "get_anonymous_tallies", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "get_anonymous_tallies", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "get_anonymous_tallies")
+	}(time.Now())
+
+	rows, err := v.db.QueryContext(ctx, queryGetAnonymousTallies, pollID)
+	if err != nil {
+		return nil, errors.Join(errQueryGetAnonymousTallies, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make(map[int64]uint64)
+	for rows.Next() {
+		var optionID int64
+		var count uint64
+		if err = rows.Scan(&optionID, &count); err != nil {
+			return nil, errors.Join(errScanningTallyInGetAnonymousTallies, err)
+		}
+		result[optionID] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingTalliesInGetAnonymousTallies, err)
+	}
+
+	return result, nil
+}
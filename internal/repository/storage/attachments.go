@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+type Attachment struct {
+	ID           int64
+	UserID       int64
+	Key          string
+	ThumbnailKey sql.NullString
+	ContentType  string
+	Size         int64
+	Width        int
+	Height       int
+	CreatedAt    time.Time
+}
+
+var (
+	errInsertingAttachment = errors.New("")
+)
+
+const queryCreateAttachment = `
+INSERT INTO attachments (user_id, key, thumbnail_key, content_type, size, width, height)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id`
+
+func (c *polls) CreateAttachment(ctx context.Context, tx *sqlx.Tx, attachment *Attachment) (id int64, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "create_attachment")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "create_attachment", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "create_attachment", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "create_attachment")
+	}(time.Now())
+
+	err = tx.QueryRowContext(ctx, queryCreateAttachment, attachment.UserID, attachment.Key, attachment.ThumbnailKey,
+		attachment.ContentType, attachment.Size, attachment.Width, attachment.Height).Scan(&id)
+	if err != nil {
+		return -1, errors.Join(errInsertingAttachment, err)
+	}
+
+	return id, nil
+}
+
+var (
+	errQueryGetAttachmentsByIDs                 = errors.New("")
+	errScanningAttachmentInGetAttachmentsByIDs  = errors.New("")
+	errScanningAttachmentsInGetAttachmentsByIDs = errors.New("")
+)
+
+const queryGetAttachmentsByIDs = `
+SELECT id, user_id, key, thumbnail_key, content_type, size, width, height, created_at
+FROM attachments
+WHERE id = ANY($1)`
+
+func (c *polls) GetAttachmentsByIDs(ctx context.Context, ids []int64) (result []Attachment, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "get_attachments_by_ids")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "get_attachments_by_ids", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "get_attachments_by_ids", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "get_attachments_by_ids")
+	}(time.Now())
+
+	rows, err := c.db.QueryContext(ctx, queryGetAttachmentsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, errors.Join(errQueryGetAttachmentsByIDs, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]Attachment, 0)
+	for rows.Next() {
+		a := Attachment{}
+		err = rows.Scan(&a.ID, &a.UserID, &a.Key, &a.ThumbnailKey, &a.ContentType, &a.Size, &a.Width, &a.Height, &a.CreatedAt)
+		if err != nil {
+			return nil, errors.Join(errScanningAttachmentInGetAttachmentsByIDs, err)
+		}
+		result = append(result, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errScanningAttachmentsInGetAttachmentsByIDs, err)
+	}
+
+	return result, nil
+}
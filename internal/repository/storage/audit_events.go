@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mohammadne/porsesh/pkg/databases/postgres"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"go.uber.org/zap"
+)
+
+type AuditEvents interface {
+	// CreateAuditEvent writes the event within the given transaction, a nil tx writes it directly
+	CreateAuditEvent(ctx context.Context, tx *sqlx.Tx, event *AuditEvent) (err error)
+	ListAuditEvents(ctx context.Context, filter *AuditEventFilter, limit, offset int) (result []AuditEvent, err error)
+}
+
+func NewAuditEvents(lg *zap.Logger, database *postgres.Postgres) AuditEvents {
+	return &auditEvents{logger: lg, db: database}
+}
+
+type auditEvents struct {
+	logger *zap.Logger
+	db     *postgres.Postgres
+}
+
+type AuditEvent struct {
+	ID         int64
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetID   int64
+	RequestID  string
+	Metadata   json.RawMessage
+	CreatedAt  time.Time
+}
+
+type AuditEventFilter struct {
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetID   int64
+	RequestID  string
+	From       sql.NullTime
+	To         sql.NullTime
+	// keyset of the last listed event, it pages deep listings without rescanning the offset
+	BeforeCreatedAt sql.NullTime
+	BeforeID        int64
+}
+
+var (
+	ErrInsertingAuditEvent = errors.New("")
+)
+
+const queryCreateAuditEvent = `
+INSERT INTO audit_events (actor_id, action, target_type, target_id, request_id, metadata, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+func (a *auditEvents) CreateAuditEvent(ctx context.Context, tx *sqlx.Tx, event *AuditEvent) (err error) {
+	ctx, span := a.db.StartSpan(ctx, "audit_events", "create_audit_event")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			a.db.Vectors.Counter.IncrementVector("audit_events", "create_audit_event", metrics.StatusFailure)
+			return
+		}
+		a.db.Vectors.Counter.IncrementVector("audit_events", "create_audit_event", metrics.StatusSuccess)
+		a.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "audit_events", "create_audit_event")
+	}(time.Now())
+
+	var execer sqlx.ExecerContext = a.db
+	if tx != nil {
+		execer = tx
+	}
+
+	metadata := event.Metadata
+	if len(metadata) == 0 {
+		metadata = json.RawMessage("{}")
+	}
+
+	_, err = execer.ExecContext(ctx, queryCreateAuditEvent, event.ActorID, event.Action,
+		event.TargetType, event.TargetID, event.RequestID, string(metadata), time.Now())
+	if err != nil {
+		return errors.Join(ErrInsertingAuditEvent, err)
+	}
+
+	return nil
+}
+
+var (
+	errListAuditEvents                = errors.New("")
+	errScanningEventInListAuditEvents = errors.New("")
+	errIteratingInListAuditEvents     = errors.New("")
+)
+
+const queryListAuditEvents = `
+SELECT id, actor_id, action, target_type, target_id, request_id, metadata, created_at
+FROM audit_events
+WHERE ($1::BIGINT = 0 OR actor_id = $1)
+	AND ($2::TEXT = '' OR action = $2)
+	AND ($3::TEXT = '' OR target_type = $3)
+	AND ($4::BIGINT = 0 OR target_id = $4)
+	AND ($5::TEXT = '' OR request_id = $5)
+	AND ($6::TIMESTAMP IS NULL OR created_at >= $6)
+	AND ($7::TIMESTAMP IS NULL OR created_at < $7)
+	AND ($8::TIMESTAMP IS NULL OR (created_at, id) < ($8, $9))
+ORDER BY created_at DESC, id DESC
+LIMIT $10 OFFSET $11`
+
+func (a *auditEvents) ListAuditEvents(ctx context.Context, filter *AuditEventFilter, limit, offset int) (result []AuditEvent, err error) {
+	ctx, span := a.db.StartSpan(ctx, "audit_events", "list_audit_events")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			a.db.Vectors.Counter.IncrementVector("audit_events", "list_audit_events", metrics.StatusFailure)
+			return
+		}
+		a.db.Vectors.Counter.IncrementVector("audit_events", "list_audit_events", metrics.StatusSuccess)
+		a.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "audit_events", "list_audit_events")
+	}(time.Now())
+
+	rows, err := a.db.QueryContext(ctx, queryListAuditEvents,
+		filter.ActorID, filter.Action, filter.TargetType, filter.TargetID, filter.RequestID,
+		filter.From, filter.To, filter.BeforeCreatedAt, filter.BeforeID, limit, offset)
+	if err != nil {
+		return nil, errors.Join(errListAuditEvents, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]AuditEvent, 0)
+	for rows.Next() {
+		event, metadata := AuditEvent{}, []byte{}
+		err = rows.Scan(&event.ID, &event.ActorID, &event.Action, &event.TargetType,
+			&event.TargetID, &event.RequestID, &metadata, &event.CreatedAt)
+		if err != nil {
+			return nil, errors.Join(errScanningEventInListAuditEvents, err)
+		}
+		event.Metadata = metadata
+		result = append(result, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInListAuditEvents, err)
+	}
+
+	return result, nil
+}
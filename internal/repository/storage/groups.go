@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mohammadne/porsesh/pkg/databases/postgres"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"go.uber.org/zap"
+)
+
+type Groups interface {
+	StartTransaction(ctx context.Context) (*sqlx.Tx, error)
+
+	CreateGroup(ctx context.Context, tx *sqlx.Tx, group *Group) (id int64, err error)
+	GetGroupsByIDs(ctx context.Context, ids []int64) (result []Group, err error)
+
+	AddGroupMembers(ctx context.Context, tx *sqlx.Tx, groupID int64, userIDs []int64) (err error)
+	RemoveGroupMembers(ctx context.Context, tx *sqlx.Tx, groupID int64, userIDs []int64) (err error)
+	ListGroupMembers(ctx context.Context, groupID int64) (result []int64, err error)
+}
+
+func NewGroups(lg *zap.Logger, database *postgres.Postgres) Groups {
+	return &groups{logger: lg, db: database}
+}
+
+type groups struct {
+	logger *zap.Logger
+	db     *postgres.Postgres
+}
+
+func (g *groups) StartTransaction(ctx context.Context) (*sqlx.Tx, error) {
+	return g.db.BeginTxx(ctx, nil)
+}
+
+type Group struct {
+	ID        int64
+	OwnerID   int64
+	Name      string
+	CreatedAt time.Time
+}
+
+var (
+	ErrInsertingGroup           = errors.New("")
+	ErrCreateGroupAlreadyExists = errors.New("")
+)
+
+const queryCreateGroup = `
+INSERT INTO user_groups (owner_id, name, created_at)
+VALUES ($1, $2, $3)
+RETURNING id`
+
+func (g *groups) CreateGroup(ctx context.Context, tx *sqlx.Tx, group *Group) (id int64, err error) {
+	ctx, span := g.db.StartSpan(ctx, "groups", "create_group")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			g.db.Vectors.Counter.IncrementVector("groups", "create_group", metrics.StatusFailure)
+			return
+		}
+		g.db.Vectors.Counter.IncrementVector("groups", "create_group", metrics.StatusSuccess)
+		g.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "groups", "create_group")
+	}(time.Now())
+
+	err = tx.QueryRowContext(ctx, queryCreateGroup, group.OwnerID, group.Name, time.Now()).Scan(&id)
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == postgres.UniqueConstraintViolatedCode {
+			return -1, ErrCreateGroupAlreadyExists
+		}
+		return -1, errors.Join(ErrInsertingGroup, err)
+	}
+
+	return id, nil
+}
+
+var (
+	errGetGroupsByIDs                = errors.New("")
+	errScanningGroupInGetGroupsByIDs = errors.New("")
+	errIteratingInGetGroupsByIDs     = errors.New("")
+)
+
+const queryGetGroupsByIDs = `
+SELECT id, owner_id, name, created_at
+FROM user_groups
+WHERE id = ANY($1)`
+
+func (g *groups) GetGroupsByIDs(ctx context.Context, ids []int64) (result []Group, err error) {
+	ctx, span := g.db.StartSpan(ctx, "groups", "get_groups_by_ids")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			g.db.Vectors.Counter.IncrementVector("groups", "get_groups_by_ids", metrics.StatusFailure)
+			return
+		}
+		g.db.Vectors.Counter.IncrementVector("groups", "get_groups_by_ids", metrics.StatusSuccess)
+		g.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "groups", "get_groups_by_ids")
+	}(time.Now())
+
+	rows, err := g.db.QueryContext(ctx, queryGetGroupsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, errors.Join(errGetGroupsByIDs, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]Group, 0, len(ids))
+	for rows.Next() {
+		group := Group{}
+		if err = rows.Scan(&group.ID, &group.OwnerID, &group.Name, &group.CreatedAt); err != nil {
+			return nil, errors.Join(errScanningGroupInGetGroupsByIDs, err)
+		}
+		result = append(result, group)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetGroupsByIDs, err)
+	}
+
+	return result, nil
+}
+
+var (
+	ErrInsertingGroupMember = errors.New("")
+)
+
+const queryAddGroupMember = `
+INSERT INTO user_group_members (group_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (group_id, user_id) DO NOTHING`
+
+func (g *groups) AddGroupMembers(ctx context.Context, tx *sqlx.Tx, groupID int64, userIDs []int64) (err error) {
+	ctx, span := g.db.StartSpan(ctx, "groups", "add_group_members")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			g.db.Vectors.Counter.IncrementVector("groups", "add_group_members", metrics.StatusFailure)
+			return
+		}
+		g.db.Vectors.Counter.IncrementVector("groups", "add_group_members", metrics.StatusSuccess)
+		g.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "groups", "add_group_members")
+	}(time.Now())
+
+	for _, userID := range userIDs {
+		_, err := tx.ExecContext(ctx, queryAddGroupMember, groupID, userID)
+		if err != nil {
+			return errors.Join(ErrInsertingGroupMember, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	ErrDeletingGroupMembers = errors.New("")
+)
+
+const queryRemoveGroupMembers = `
+DELETE FROM user_group_members
+WHERE group_id = $1 AND user_id = ANY($2)`
+
+func (g *groups) RemoveGroupMembers(ctx context.Context, tx *sqlx.Tx, groupID int64, userIDs []int64) (err error) {
+	ctx, span := g.db.StartSpan(ctx, "groups", "remove_group_members")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			g.db.Vectors.Counter.IncrementVector("groups", "remove_group_members", metrics.StatusFailure)
+			return
+		}
+		g.db.Vectors.Counter.IncrementVector("groups", "remove_group_members", metrics.StatusSuccess)
+		g.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "groups", "remove_group_members")
+	}(time.Now())
+
+	_, err = tx.ExecContext(ctx, queryRemoveGroupMembers, groupID, pq.Array(userIDs))
+	if err != nil {
+		return errors.Join(ErrDeletingGroupMembers, err)
+	}
+
+	return nil
+}
+
+var (
+	errListGroupMembers                 = errors.New("")
+	errScanningMemberInListGroupMembers = errors.New("")
+	errIteratingInListGroupMembers      = errors.New("")
+)
+
+const queryListGroupMembers = `
+SELECT user_id
+FROM user_group_members
+WHERE group_id = $1
+ORDER BY added_at`
+
+func (g *groups) ListGroupMembers(ctx context.Context, groupID int64) (result []int64, err error) {
+	ctx, span := g.db.StartSpan(ctx, "groups", "list_group_members")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			g.db.Vectors.Counter.IncrementVector("groups", "list_group_members", metrics.StatusFailure)
+			return
+		}
+		g.db.Vectors.Counter.IncrementVector("groups", "list_group_members", metrics.StatusSuccess)
+		g.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "groups", "list_group_members")
+	}(time.Now())
+
+	rows, err := g.db.QueryContext(ctx, queryListGroupMembers, groupID)
+	if err != nil {
+		return nil, errors.Join(errListGroupMembers, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]int64, 0)
+	for rows.Next() {
+		var userID int64
+		if err = rows.Scan(&userID); err != nil {
+			return nil, errors.Join(errScanningMemberInListGroupMembers, err)
+		}
+		result = append(result, userID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInListGroupMembers, err)
+	}
+
+	return result, nil
+}
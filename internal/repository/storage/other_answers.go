@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+type OtherAnswerFrequency struct {
+	Text  string
+	Count uint64
+}
+
+// the free-text answers are grouped regardless of their case and surrounding spaces
+const otherAnswerKey = `lower(trim(other_text))`
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+var (
+	errQueryGetPollOtherVotesCount = errors.New("")
+)
+
+const queryGetPollOtherVotesCount = `
+SELECT COUNT(*)
+FROM votes
+WHERE poll_id = $1 AND other_text IS NOT NULL AND NOT other_hidden`
+
+// GetPollOtherVotesCount counts the visible free-text answers, the hidden ones are left out of the results
+func (v *votes) GetPollOtherVotesCount(ctx context.Context, pollID int64) (result uint64, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "get_poll_other_votes_count")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "get_poll_other_votes_count", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "get_poll_other_votes_count", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "get_poll_other_votes_count")
+	}(time.Now())
+
+	err = v.db.QueryRowContext(ctx, queryGetPollOtherVotesCount, pollID).Scan(&result)
+	if err != nil {
+		return 0, errors.Join(errQueryGetPollOtherVotesCount, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errListOtherAnswers               = errors.New("")
+	errScanningVoteInListOtherAnswers = errors.New("")
+	errIteratingInListOtherAnswers    = errors.New("")
+)
+
+const queryListOtherAnswers = `
+SELECT user_id, poll_id, other_text, other_hidden, acted_at
+FROM votes
+WHERE poll_id = $1 AND other_text IS NOT NULL AND ($2::TEXT = '' OR other_text ILIKE '%' || $2::TEXT || '%')
+ORDER BY acted_at DESC, user_id
+LIMIT $3 OFFSET $4`
+
+// ListOtherAnswers lists the free-text answers of the poll (including the hidden ones),
+// optionally only the ones containing the search text
+func (v *votes) ListOtherAnswers(ctx context.Context, pollID int64, search string, limit, offset int) (result []Vote, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "list_other_answers")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "list_other_answers", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "list_other_answers", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "list_other_answers")
+	}(time.Now())
+
+	rows, err := v.db.QueryContext(ctx, queryListOtherAnswers, pollID, likeEscaper.Replace(search), limit, offset)
+	if err != nil {
+		return nil, errors.Join(errListOtherAnswers, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]Vote, 0)
+	for rows.Next() {
+		vote := Vote{}
+		err = rows.Scan(&vote.UserID, &vote.PollID, &vote.OtherText, &vote.OtherHidden, &vote.ActedAt)
+		if err != nil {
+			return nil, errors.Join(errScanningVoteInListOtherAnswers, err)
+		}
+		result = append(result, vote)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInListOtherAnswers, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errGetFrequentOtherAnswers                    = errors.New("")
+	errScanningFrequencyInGetFrequentOtherAnswers = errors.New("")
+	errIteratingInGetFrequentOtherAnswers         = errors.New("")
+)
+
+const queryGetFrequentOtherAnswers = `
+SELECT ` + otherAnswerKey + ` AS text, COUNT(*) AS count
+FROM votes
+WHERE poll_id = $1 AND other_text IS NOT NULL AND NOT other_hidden
+GROUP BY ` + otherAnswerKey + `
+ORDER BY count DESC, text
+LIMIT $2`
+
+// GetFrequentOtherAnswers groups the visible free-text answers of the poll by the most frequent ones
+func (v *votes) GetFrequentOtherAnswers(ctx context.Context, pollID int64, limit int) (result []OtherAnswerFrequency, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "get_frequent_other_answers")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "get_frequent_other_answers", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "get_frequent_other_answers", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "get_frequent_other_answers")
+	}(time.Now())
+
+	rows, err := v.db.QueryContext(ctx, queryGetFrequentOtherAnswers, pollID, limit)
+	if err != nil {
+		return nil, errors.Join(errGetFrequentOtherAnswers, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]OtherAnswerFrequency, 0)
+	for rows.Next() {
+		frequency := OtherAnswerFrequency{}
+		err = rows.Scan(&frequency.Text, &frequency.Count)
+		if err != nil {
+			return nil, errors.Join(errScanningFrequencyInGetFrequentOtherAnswers, err)
+		}
+		result = append(result, frequency)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetFrequentOtherAnswers, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errSetOtherAnswerHidden          = errors.New("")
+	ErrSetOtherAnswerHiddenNotExists = errors.New("")
+)
+
+const querySetOtherAnswerHidden = `
+UPDATE votes
+SET other_hidden = $3
+WHERE poll_id = $1 AND user_id = $2 AND other_text IS NOT NULL`
+
+func (v *votes) SetOtherAnswerHidden(ctx context.Context, tx *sqlx.Tx, pollID, userID int64, hidden bool) (err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "set_other_answer_hidden")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "set_other_answer_hidden", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "set_other_answer_hidden", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "set_other_answer_hidden")
+	}(time.Now())
+
+	execResult, err := tx.ExecContext(ctx, querySetOtherAnswerHidden, pollID, userID, hidden)
+	if err != nil {
+		return errors.Join(errSetOtherAnswerHidden, err)
+	}
+
+	if affected, err := execResult.RowsAffected(); err != nil {
+		return errors.Join(errSetOtherAnswerHidden, err)
+	} else if affected == 0 {
+		return ErrSetOtherAnswerHiddenNotExists
+	}
+
+	return nil
+}
+
+var (
+	errPromoteOtherAnswer = errors.New("")
+)
+
+const queryPromoteOtherAnswer = `
+UPDATE votes
+SET option_id = $2, other_text = NULL, other_hidden = false
+WHERE poll_id = $1 AND other_text IS NOT NULL AND NOT other_hidden AND ` + otherAnswerKey + ` = lower(trim($3::TEXT))`
+
+// PromoteOtherAnswer moves the visible free-text answers matching the text into the option,
+// the number of the moved votes is returned
+func (v *votes) PromoteOtherAnswer(ctx context.Context, tx *sqlx.Tx, pollID, optionID int64, text string) (result int64, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "promote_other_answer")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "promote_other_answer", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "promote_other_answer", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "promote_other_answer")
+	}(time.Now())
+
+	execResult, err := tx.ExecContext(ctx, queryPromoteOtherAnswer, pollID, optionID, text)
+	if err != nil {
+		return 0, errors.Join(errPromoteOtherAnswer, err)
+	}
+
+	result, err = execResult.RowsAffected()
+	if err != nil {
+		return 0, errors.Join(errPromoteOtherAnswer, err)
+	}
+
+	return result, nil
+}
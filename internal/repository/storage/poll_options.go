@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
 )
 
 var (
@@ -15,28 +17,37 @@ var (
 )
 
 const queryCreatePollOptions = `
-INSERT INTO poll_options (poll_id, content, sort)
-VALUES ($1, $2, $3)`
+INSERT INTO poll_options (poll_id, content, sort, correct, starts_at, ends_at, time_zone, attachment_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
 type PollOption struct {
 	ID      int64
 	PollID  int64
 	Content string
 	Sort    int
+	Correct bool // the answer of a quiz, never expose it before the poll is closed
+	// the time range of the slot, only set on the schedule polls
+	StartsAt     sql.NullTime
+	EndsAt       sql.NullTime
+	TimeZone     string
+	AttachmentID sql.NullInt64
 }
 
 func (c *polls) CreatePollOptions(ctx context.Context, tx *sqlx.Tx, pollID int64, options []PollOption) (err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "create_poll_options")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			c.db.Vectors.Counter.IncrementVector("polls", "create_poll_options", metrics.StatusFailure)
 			return
 		}
 		c.db.Vectors.Counter.IncrementVector("polls", "create_poll_options", metrics.StatusSuccess)
-		c.db.Vectors.Histogram.ObserveResponseTime(start, "polls", "create_poll_options")
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "create_poll_options")
 	}(time.Now())
 
 	for _, option := range options {
-		_, err := tx.ExecContext(ctx, queryCreatePollOptions, pollID, option.Content, option.Sort)
+		_, err := tx.ExecContext(ctx, queryCreatePollOptions,
+			pollID, option.Content, option.Sort, option.Correct, option.StartsAt, option.EndsAt, option.TimeZone, option.AttachmentID)
 		if err != nil {
 			return errors.Join(ErrInsertingPollOption, err)
 		}
@@ -45,6 +56,33 @@ func (c *polls) CreatePollOptions(ctx context.Context, tx *sqlx.Tx, pollID int64
 	return nil
 }
 
+const queryCreatePollOption = `
+INSERT INTO poll_options (poll_id, content, sort, correct, starts_at, ends_at, time_zone, attachment_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id`
+
+// CreatePollOption adds a single option to an existing poll
+func (c *polls) CreatePollOption(ctx context.Context, tx *sqlx.Tx, option *PollOption) (id int64, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "create_poll_option")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "create_poll_option", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "create_poll_option", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "create_poll_option")
+	}(time.Now())
+
+	err = tx.QueryRowContext(ctx, queryCreatePollOption,
+		option.PollID, option.Content, option.Sort, option.Correct, option.StartsAt, option.EndsAt, option.TimeZone, option.AttachmentID).Scan(&id)
+	if err != nil {
+		return -1, errors.Join(ErrInsertingPollOption, err)
+	}
+
+	return id, nil
+}
+
 var (
 	ErrQueryGetPollOptionsByPollID                 = errors.New("")
 	errScanningPollOptionInGetPollOptionsByPollID  = errors.New("")
@@ -52,18 +90,20 @@ var (
 )
 
 const queryGetPollOptionsByPollID = `
-SELECT id, poll_id, content, sort
+SELECT id, poll_id, content, sort, correct, starts_at, ends_at, time_zone, attachment_id
 FROM poll_options
 WHERE poll_id = $1`
 
 func (c *polls) GetPollOptionsByPollID(ctx context.Context, pollID int64) (result []PollOption, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "get_poll_options_by_poll_id")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			c.db.Vectors.Counter.IncrementVector("polls", "get_poll_options_by_poll_id", metrics.StatusFailure)
 			return
 		}
 		c.db.Vectors.Counter.IncrementVector("polls", "get_poll_options_by_poll_id", metrics.StatusSuccess)
-		c.db.Vectors.Histogram.ObserveResponseTime(start, "polls", "get_poll_options_by_poll_id")
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "get_poll_options_by_poll_id")
 	}(time.Now())
 
 	rows, err := c.db.QueryContext(ctx, queryGetPollOptionsByPollID, pollID)
@@ -78,7 +118,7 @@ func (c *polls) GetPollOptionsByPollID(ctx context.Context, pollID int64) (resul
 	result = make([]PollOption, 0)
 	for rows.Next() {
 		po := PollOption{}
-		err = rows.Scan(&po.ID, &po.PollID, &po.Content, &po.Sort)
+		err = rows.Scan(&po.ID, &po.PollID, &po.Content, &po.Sort, &po.Correct, &po.StartsAt, &po.EndsAt, &po.TimeZone, &po.AttachmentID)
 		if err != nil {
 			return nil, errors.Join(errScanningPollOptionInGetPollOptionsByPollID, err)
 		}
@@ -90,3 +130,144 @@ func (c *polls) GetPollOptionsByPollID(ctx context.Context, pollID int64) (resul
 
 	return result, nil
 }
+
+var (
+	errLockPoll                             = errors.New("")
+	errQueryLockPollOptions                 = errors.New("")
+	errScanningPollOptionInLockPollOptions  = errors.New("")
+	errScanningPollOptionsInLockPollOptions = errors.New("")
+)
+
+const queryLockPoll = `
+SELECT id
+FROM polls
+WHERE id = $1
+FOR UPDATE`
+
+const queryLockPollOptions = `
+SELECT id, poll_id, content, sort, correct, starts_at, ends_at, time_zone, attachment_id
+FROM poll_options
+WHERE poll_id = $1
+ORDER BY sort`
+
+// LockPollOptions locks the row of the poll until the end of the transaction, so the options can't
+// be changed concurrently, and retrieves the options in their order
+func (c *polls) LockPollOptions(ctx context.Context, tx *sqlx.Tx, pollID int64) (result []PollOption, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "lock_poll_options")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "lock_poll_options", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "lock_poll_options", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "lock_poll_options")
+	}(time.Now())
+
+	var id int64
+	if err = tx.QueryRowContext(ctx, queryLockPoll, pollID).Scan(&id); err != nil {
+		return nil, errors.Join(errLockPoll, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, queryLockPollOptions, pollID)
+	if err != nil {
+		return nil, errors.Join(errQueryLockPollOptions, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]PollOption, 0)
+	for rows.Next() {
+		po := PollOption{}
+		err = rows.Scan(&po.ID, &po.PollID, &po.Content, &po.Sort, &po.Correct, &po.StartsAt, &po.EndsAt, &po.TimeZone, &po.AttachmentID)
+		if err != nil {
+			return nil, errors.Join(errScanningPollOptionInLockPollOptions, err)
+		}
+		result = append(result, po)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errScanningPollOptionsInLockPollOptions, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errQueryGetPollOptionsByPollIDs                 = errors.New("")
+	errScanningPollOptionInGetPollOptionsByPollIDs  = errors.New("")
+	errScanningPollOptionsInGetPollOptionsByPollIDs = errors.New("")
+)
+
+const queryGetPollOptionsByPollIDs = `
+SELECT id, poll_id, content, sort, correct, starts_at, ends_at, time_zone, attachment_id
+FROM poll_options
+WHERE poll_id = ANY($1)`
+
+func (c *polls) GetPollOptionsByPollIDs(ctx context.Context, pollIDs []int64) (result []PollOption, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "get_poll_options_by_poll_ids")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "get_poll_options_by_poll_ids", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "get_poll_options_by_poll_ids", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "get_poll_options_by_poll_ids")
+	}(time.Now())
+
+	rows, err := c.db.QueryContext(ctx, queryGetPollOptionsByPollIDs, pq.Array(pollIDs))
+	if err != nil {
+		return nil, errors.Join(errQueryGetPollOptionsByPollIDs, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]PollOption, 0)
+	for rows.Next() {
+		po := PollOption{}
+		err = rows.Scan(&po.ID, &po.PollID, &po.Content, &po.Sort, &po.Correct, &po.StartsAt, &po.EndsAt, &po.TimeZone, &po.AttachmentID)
+		if err != nil {
+			return nil, errors.Join(errScanningPollOptionInGetPollOptionsByPollIDs, err)
+		}
+		result = append(result, po)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errScanningPollOptionsInGetPollOptionsByPollIDs, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errGetPollOption = errors.New("")
+)
+
+// the option is looked up along with its poll, so an option of another poll is never found
+const queryGetPollOption = `
+SELECT id, poll_id, content, sort, correct, starts_at, ends_at, time_zone, attachment_id
+FROM poll_options
+WHERE id = $1 AND poll_id = $2`
+
+// GetPollOption retrieves the option of the poll, nil is returned if the poll has no such option
+func (c *polls) GetPollOption(ctx context.Context, pollID, optionID int64) (result *PollOption, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "get_poll_option")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "get_poll_option", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "get_poll_option", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "get_poll_option")
+	}(time.Now())
+
+	po := PollOption{}
+	err = c.db.QueryRowContext(ctx, queryGetPollOption, optionID, pollID).Scan(
+		&po.ID, &po.PollID, &po.Content, &po.Sort, &po.Correct, &po.StartsAt, &po.EndsAt, &po.TimeZone, &po.AttachmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Join(errGetPollOption, err)
+	}
+
+	return &po, nil
+}
@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+var (
+	ErrInsertingPollParticipant = errors.New("")
+)
+
+const queryAddPollParticipant = `
+INSERT INTO poll_participants (poll_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (poll_id, user_id) DO NOTHING`
+
+func (c *polls) AddPollParticipants(ctx context.Context, tx *sqlx.Tx, pollID int64, userIDs []int64) (err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "add_poll_participants")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "add_poll_participants", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "add_poll_participants", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "add_poll_participants")
+	}(time.Now())
+
+	for _, userID := range userIDs {
+		_, err := tx.ExecContext(ctx, queryAddPollParticipant, pollID, userID)
+		if err != nil {
+			return errors.Join(ErrInsertingPollParticipant, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	ErrDeletingPollParticipants = errors.New("")
+)
+
+const queryRemovePollParticipants = `
+DELETE FROM poll_participants
+WHERE poll_id = $1 AND user_id = ANY($2)`
+
+func (c *polls) RemovePollParticipants(ctx context.Context, tx *sqlx.Tx, pollID int64, userIDs []int64) (err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "remove_poll_participants")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "remove_poll_participants", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "remove_poll_participants", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "remove_poll_participants")
+	}(time.Now())
+
+	_, err = tx.ExecContext(ctx, queryRemovePollParticipants, pollID, pq.Array(userIDs))
+	if err != nil {
+		return errors.Join(ErrDeletingPollParticipants, err)
+	}
+
+	return nil
+}
+
+var (
+	errListPollParticipants                      = errors.New("")
+	errScanningParticipantInListPollParticipants = errors.New("")
+	errIteratingInListPollParticipants           = errors.New("")
+)
+
+const queryListPollParticipants = `
+SELECT user_id
+FROM poll_participants
+WHERE poll_id = $1
+ORDER BY added_at`
+
+func (c *polls) ListPollParticipants(ctx context.Context, pollID int64) (result []int64, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "list_poll_participants")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "list_poll_participants", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "list_poll_participants", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "list_poll_participants")
+	}(time.Now())
+
+	rows, err := c.db.QueryContext(ctx, queryListPollParticipants, pollID)
+	if err != nil {
+		return nil, errors.Join(errListPollParticipants, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]int64, 0)
+	for rows.Next() {
+		var userID int64
+		if err = rows.Scan(&userID); err != nil {
+			return nil, errors.Join(errScanningParticipantInListPollParticipants, err)
+		}
+		result = append(result, userID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInListPollParticipants, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errQueryIsPollParticipant = errors.New("")
+)
+
+const queryIsPollParticipant = `
+SELECT EXISTS (
+	SELECT 1 FROM poll_participants WHERE poll_id = $1 AND user_id = $2
+) OR EXISTS (
+	SELECT 1 FROM poll_participant_groups ppg
+	JOIN user_group_members ugm ON ugm.group_id = ppg.group_id
+	WHERE ppg.poll_id = $1 AND ugm.user_id = $2
+)`
+
+func (c *polls) IsPollParticipant(ctx context.Context, pollID, userID int64) (result bool, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "is_poll_participant")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "is_poll_participant", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "is_poll_participant", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "is_poll_participant")
+	}(time.Now())
+
+	err = c.db.QueryRowContext(ctx, queryIsPollParticipant, pollID, userID).Scan(&result)
+	if err != nil {
+		return false, errors.Join(errQueryIsPollParticipant, err)
+	}
+
+	return result, nil
+}
+
+var (
+	ErrInsertingPollParticipantGroup = errors.New("")
+)
+
+const queryAddPollParticipantGroup = `
+INSERT INTO poll_participant_groups (poll_id, group_id)
+VALUES ($1, $2)
+ON CONFLICT (poll_id, group_id) DO NOTHING`
+
+func (c *polls) AddPollParticipantGroups(ctx context.Context, tx *sqlx.Tx, pollID int64, groupIDs []int64) (err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "add_poll_participant_groups")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "add_poll_participant_groups", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "add_poll_participant_groups", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "add_poll_participant_groups")
+	}(time.Now())
+
+	for _, groupID := range groupIDs {
+		_, err := tx.ExecContext(ctx, queryAddPollParticipantGroup, pollID, groupID)
+		if err != nil {
+			return errors.Join(ErrInsertingPollParticipantGroup, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	ErrDeletingPollParticipantGroups = errors.New("")
+)
+
+const queryRemovePollParticipantGroups = `
+DELETE FROM poll_participant_groups
+WHERE poll_id = $1 AND group_id = ANY($2)`
+
+func (c *polls) RemovePollParticipantGroups(ctx context.Context, tx *sqlx.Tx, pollID int64, groupIDs []int64) (err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "remove_poll_participant_groups")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "remove_poll_participant_groups", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "remove_poll_participant_groups", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "remove_poll_participant_groups")
+	}(time.Now())
+
+	_, err = tx.ExecContext(ctx, queryRemovePollParticipantGroups, pollID, pq.Array(groupIDs))
+	if err != nil {
+		return errors.Join(ErrDeletingPollParticipantGroups, err)
+	}
+
+	return nil
+}
+
+var (
+	errListPollParticipantGroups                = errors.New("")
+	errScanningGroupInListPollParticipantGroups = errors.New("")
+	errIteratingInListPollParticipantGroups     = errors.New("")
+)
+
+const queryListPollParticipantGroups = `
+SELECT group_id
+FROM poll_participant_groups
+WHERE poll_id = $1
+ORDER BY added_at`
+
+func (c *polls) ListPollParticipantGroups(ctx context.Context, pollID int64) (result []int64, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "list_poll_participant_groups")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "list_poll_participant_groups", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "list_poll_participant_groups", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "list_poll_participant_groups")
+	}(time.Now())
+
+	rows, err := c.db.QueryContext(ctx, queryListPollParticipantGroups, pollID)
+	if err != nil {
+		return nil, errors.Join(errListPollParticipantGroups, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]int64, 0)
+	for rows.Next() {
+		var groupID int64
+		if err = rows.Scan(&groupID); err != nil {
+			return nil, errors.Join(errScanningGroupInListPollParticipantGroups, err)
+		}
+		result = append(result, groupID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInListPollParticipantGroups, err)
+	}
+
+	return result, nil
+}
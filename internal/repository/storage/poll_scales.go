@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+var (
+	ErrInsertingPollScale = errors.New("")
+)
+
+const queryCreatePollScale = `
+INSERT INTO poll_scales (poll_id, min, max, step, labels)
+VALUES ($1, $2, $3, $4, $5)`
+
+type PollScale struct {
+	PollID int64
+	Min    int
+	Max    int
+	Step   int
+	Labels json.RawMessage // object of the value to its label
+}
+
+func (c *polls) CreatePollScale(ctx context.Context, tx *sqlx.Tx, scale *PollScale) (err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "create_poll_scale")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "create_poll_scale", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "create_poll_scale", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "create_poll_scale")
+	}(time.Now())
+
+	labels := "{}"
+	if len(scale.Labels) != 0 {
+		labels = string(scale.Labels)
+	}
+
+	_, err = tx.ExecContext(ctx, queryCreatePollScale, scale.PollID, scale.Min, scale.Max, scale.Step, labels)
+	if err != nil {
+		return errors.Join(ErrInsertingPollScale, err)
+	}
+
+	return nil
+}
+
+var (
+	errQueryGetPollScalesByPollIDs               = errors.New("")
+	errScanningPollScaleInGetPollScalesByPollIDs = errors.New("")
+	errIteratingInGetPollScalesByPollIDs         = errors.New("")
+)
+
+const queryGetPollScalesByPollIDs = `
+SELECT poll_id, min, max, step, labels
+FROM poll_scales
+WHERE poll_id = ANY($1)`
+
+func (c *polls) GetPollScalesByPollIDs(ctx context.Context, pollIDs []int64) (result []PollScale, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "get_poll_scales_by_poll_ids")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "get_poll_scales_by_poll_ids", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "get_poll_scales_by_poll_ids", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "get_poll_scales_by_poll_ids")
+	}(time.Now())
+
+	rows, err := c.db.QueryContext(ctx, queryGetPollScalesByPollIDs, pq.Array(pollIDs))
+	if err != nil {
+		return nil, errors.Join(errQueryGetPollScalesByPollIDs, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]PollScale, 0)
+	for rows.Next() {
+		ps, labels := PollScale{}, []byte{}
+		err = rows.Scan(&ps.PollID, &ps.Min, &ps.Max, &ps.Step, &labels)
+		if err != nil {
+			return nil, errors.Join(errScanningPollScaleInGetPollScalesByPollIDs, err)
+		}
+		ps.Labels = labels
+		result = append(result, ps)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetPollScalesByPollIDs, err)
+	}
+
+	return result, nil
+}
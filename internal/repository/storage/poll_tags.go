@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
 )
 
 var (
@@ -18,18 +20,21 @@ INSERT INTO poll_tags (poll_id, tag_id)
 VALUES ($1, $2)`
 
 type PollTag struct {
-	ID   uint64
-	Name string
+	ID     uint64
+	PollID int64
+	Name   string
 }
 
 func (c *polls) CreatePollTags(ctx context.Context, tx *sqlx.Tx, pollID int64, tagIDs []int64) (err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "create_poll_tags")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			c.db.Vectors.Counter.IncrementVector("polls", "create_poll_tags", metrics.StatusFailure)
 			return
 		}
 		c.db.Vectors.Counter.IncrementVector("polls", "create_poll_tags", metrics.StatusSuccess)
-		c.db.Vectors.Histogram.ObserveResponseTime(start, "polls", "create_poll_tags")
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "create_poll_tags")
 	}(time.Now())
 
 	for _, tagID := range tagIDs {
@@ -41,3 +46,49 @@ func (c *polls) CreatePollTags(ctx context.Context, tx *sqlx.Tx, pollID int64, t
 
 	return nil
 }
+
+var (
+	errQueryGetPollTagsByPollIDs              = errors.New("")
+	errScanningPollTagInGetPollTagsByPollIDs  = errors.New("")
+	errScanningPollTagsInGetPollTagsByPollIDs = errors.New("")
+)
+
+const queryGetPollTagsByPollIDs = `
+SELECT t.id, pt.poll_id, t.name
+FROM poll_tags pt
+JOIN tags t ON t.id = pt.tag_id
+WHERE pt.poll_id = ANY($1)`
+
+func (c *polls) GetPollTagsByPollIDs(ctx context.Context, pollIDs []int64) (result []PollTag, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "get_poll_tags_by_poll_ids")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "get_poll_tags_by_poll_ids", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "get_poll_tags_by_poll_ids", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "get_poll_tags_by_poll_ids")
+	}(time.Now())
+
+	rows, err := c.db.QueryContext(ctx, queryGetPollTagsByPollIDs, pq.Array(pollIDs))
+	if err != nil {
+		return nil, errors.Join(errQueryGetPollTagsByPollIDs, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]PollTag, 0)
+	for rows.Next() {
+		pt := PollTag{}
+		err = rows.Scan(&pt.ID, &pt.PollID, &pt.Name)
+		if err != nil {
+			return nil, errors.Join(errScanningPollTagInGetPollTagsByPollIDs, err)
+		}
+		result = append(result, pt)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errScanningPollTagsInGetPollTagsByPollIDs, err)
+	}
+
+	return result, nil
+}
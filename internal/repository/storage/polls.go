@@ -9,6 +9,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/mohammadne/porsesh/pkg/databases/postgres"
 	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
 	"go.uber.org/zap"
 )
 
@@ -16,12 +17,35 @@ type Polls interface {
 	StartTransaction(ctx context.Context) (*sqlx.Tx, error)
 
 	CreatePoll(ctx context.Context, tx *sqlx.Tx, poll *Poll) (id int64, err error)
-	ListPollsByTag(ctx context.Context, userID, tagID int64, limit, offset int) (result []Poll, err error)
+	GetPollByID(ctx context.Context, id int64) (result *Poll, err error)
+	GetPollBySlug(ctx context.Context, slug string) (result *Poll, err error)
+	ListPollsByTag(ctx context.Context, userID, tagID int64, voterKey, search string, limit, offset int) (result []Poll, err error)
+	PublishPoll(ctx context.Context, tx *sqlx.Tx, id int64, publishAt, publishedAt sql.NullTime) (err error)
+	PublishDuePolls(ctx context.Context, tx *sqlx.Tx, limit int) (result []Poll, err error)
 
 	CreatePollOptions(ctx context.Context, tx *sqlx.Tx, pollID int64, options []PollOption) (err error)
+	CreatePollOption(ctx context.Context, tx *sqlx.Tx, option *PollOption) (id int64, err error)
 	GetPollOptionsByPollID(ctx context.Context, pollID int64) (result []PollOption, err error)
+	GetPollOptionsByPollIDs(ctx context.Context, pollIDs []int64) (result []PollOption, err error)
+	GetPollOption(ctx context.Context, pollID, optionID int64) (result *PollOption, err error)
+	LockPollOptions(ctx context.Context, tx *sqlx.Tx, pollID int64) (result []PollOption, err error)
 
 	CreatePollTags(ctx context.Context, tx *sqlx.Tx, pollID int64, tagIDs []int64) (err error)
+	GetPollTagsByPollIDs(ctx context.Context, pollIDs []int64) (result []PollTag, err error)
+
+	CreatePollScale(ctx context.Context, tx *sqlx.Tx, scale *PollScale) (err error)
+	GetPollScalesByPollIDs(ctx context.Context, pollIDs []int64) (result []PollScale, err error)
+
+	AddPollParticipants(ctx context.Context, tx *sqlx.Tx, pollID int64, userIDs []int64) (err error)
+	RemovePollParticipants(ctx context.Context, tx *sqlx.Tx, pollID int64, userIDs []int64) (err error)
+	ListPollParticipants(ctx context.Context, pollID int64) (result []int64, err error)
+	IsPollParticipant(ctx context.Context, pollID, userID int64) (result bool, err error)
+	AddPollParticipantGroups(ctx context.Context, tx *sqlx.Tx, pollID int64, groupIDs []int64) (err error)
+	RemovePollParticipantGroups(ctx context.Context, tx *sqlx.Tx, pollID int64, groupIDs []int64) (err error)
+	ListPollParticipantGroups(ctx context.Context, pollID int64) (result []int64, err error)
+
+	CreateAttachment(ctx context.Context, tx *sqlx.Tx, attachment *Attachment) (id int64, err error)
+	GetAttachmentsByIDs(ctx context.Context, ids []int64) (result []Attachment, err error)
 }
 
 func NewPools(lg *zap.Logger, database *postgres.Postgres) Polls {
@@ -38,77 +62,208 @@ func (c *polls) StartTransaction(ctx context.Context) (*sqlx.Tx, error) {
 }
 
 type Poll struct {
-	ID        int64
-	UserID    int64
-	Title     string
-	CreatedAt time.Time
+	ID                int64
+	UserID            int64
+	Slug              string
+	Type              string
+	Title             string
+	Description       string // the Markdown source
+	DescriptionHTML   string // rendered and sanitized on write
+	Visibility        string
+	ResultsVisibility string
+	Anonymous         bool
+	TimeLimitSeconds  int
+	QuizSet           string
+	OtherMaxLength    int
+	ShuffleOptions    bool
+	AttachmentID      sql.NullInt64
+	ClosesAt          sql.NullTime
+	PublishAt         sql.NullTime // the scheduled publish time of the draft
+	PublishedAt       sql.NullTime // NULL while the poll is a draft
+	CreatedAt         time.Time
+}
+
+// pollColumns are the selected columns of the polls (aliased as p) in the order of scanPoll
+const pollColumns = `p.id, p.user_id, p.slug, p.type, p.title, p.description, p.description_html, p.visibility,
+	p.results_visibility, p.anonymous, p.time_limit_seconds, p.quiz_set, p.other_max_length,
+	p.shuffle_options, p.attachment_id, p.closes_at, p.publish_at, p.published_at, p.created_at`
+
+func scanPoll(scanner interface{ Scan(...any) error }, poll *Poll) error {
+	return scanner.Scan(&poll.ID, &poll.UserID, &poll.Slug, &poll.Type, &poll.Title, &poll.Description,
+		&poll.DescriptionHTML, &poll.Visibility, &poll.ResultsVisibility, &poll.Anonymous,
+		&poll.TimeLimitSeconds, &poll.QuizSet, &poll.OtherMaxLength, &poll.ShuffleOptions, &poll.AttachmentID,
+		&poll.ClosesAt, &poll.PublishAt, &poll.PublishedAt, &poll.CreatedAt)
 }
 
 var (
 	ErrInsertingPoll                = errors.New("")
 	ErrRetrievingLastInsertedPollID = errors.New("")
+	ErrCreatePollSlugExists         = errors.New("")
 )
 
+// the conflicting slug doesn't abort the transaction, so the caller can retry with another one
 const queryCreatePoll = `
-INSERT INTO polls (user_id, title, created_at)
-VALUES ($1, $2, $3)
+INSERT INTO polls (user_id, slug, type, title, description, description_html, visibility, results_visibility,
+	anonymous, time_limit_seconds, quiz_set, other_max_length, shuffle_options, attachment_id, closes_at,
+	publish_at, published_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+ON CONFLICT (slug) DO NOTHING
 RETURNING id`
 
 func (c *polls) CreatePoll(ctx context.Context, tx *sqlx.Tx, poll *Poll) (id int64, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "create_poll")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			c.db.Vectors.Counter.IncrementVector("polls", "create_poll", metrics.StatusFailure)
 			return
 		}
 		c.db.Vectors.Counter.IncrementVector("polls", "create_poll", metrics.StatusSuccess)
-		c.db.Vectors.Histogram.ObserveResponseTime(start, "polls", "create_poll")
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "create_poll")
 	}(time.Now())
 
-	err = tx.QueryRowContext(ctx, queryCreatePoll, poll.UserID, poll.Title, time.Now()).Scan(&id)
+	err = tx.QueryRowContext(ctx, queryCreatePoll, poll.UserID, poll.Slug, poll.Type, poll.Title, poll.Description,
+		poll.DescriptionHTML, poll.Visibility, poll.ResultsVisibility, poll.Anonymous,
+		poll.TimeLimitSeconds, poll.QuizSet, poll.OtherMaxLength, poll.ShuffleOptions, poll.AttachmentID,
+		poll.ClosesAt, poll.PublishAt, poll.PublishedAt, time.Now()).Scan(&id)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return -1, ErrCreatePollSlugExists
+		}
 		return -1, errors.Join(ErrInsertingPoll, err)
 	}
 
 	return id, nil
 }
 
+var (
+	errGetPollByID = errors.New("")
+)
+
+const queryGetPollByID = `
+SELECT ` + pollColumns + `
+FROM polls p
+WHERE p.id = $1`
+
+func (c *polls) GetPollByID(ctx context.Context, id int64) (result *Poll, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "get_poll_by_id")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "get_poll_by_id", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "get_poll_by_id", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "get_poll_by_id")
+	}(time.Now())
+
+	result = new(Poll)
+	err = scanPoll(c.db.QueryRowContext(ctx, queryGetPollByID, id), result)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Join(errGetPollByID, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errGetPollBySlug = errors.New("")
+)
+
+const queryGetPollBySlug = `
+SELECT ` + pollColumns + `
+FROM polls p
+WHERE p.slug = $1`
+
+func (c *polls) GetPollBySlug(ctx context.Context, slug string) (result *Poll, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "get_poll_by_slug")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "get_poll_by_slug", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "get_poll_by_slug", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "get_poll_by_slug")
+	}(time.Now())
+
+	result = new(Poll)
+	err = scanPoll(c.db.QueryRowContext(ctx, queryGetPollBySlug, slug), result)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Join(errGetPollBySlug, err)
+	}
+
+	return result, nil
+}
+
 var (
 	errListPolls               = errors.New("")
 	errScanningPollInListPolls = errors.New("")
 	errIteratingInListPolls    = errors.New("")
 )
 
+// drafts and unlisted polls never show up in the feed, private ones only for their owner and participants,
+// the feed is ordered by the publish time. Closed polls (including the quizzes out of time) can't be voted
+// anymore so they are left out too. The anonymous ballots are looked up by the voter hash of the poll, which
+// is sha256(voter_key:poll_id) by the voter key ($4) of the user.
+// The search ($5) matches the title and the description by their full-text vector, empty matches all of the polls.
 const (
+	// the user is invited either directly or by one of the groups of the poll
+	queryIsFeedParticipant = `(
+		EXISTS (SELECT 1 FROM poll_participants pp WHERE pp.poll_id = p.id AND pp.user_id = $1) OR
+		EXISTS (SELECT 1 FROM poll_participant_groups ppg
+			JOIN user_group_members ugm ON ugm.group_id = ppg.group_id
+			WHERE ppg.poll_id = p.id AND ugm.user_id = $1))`
+
 	queryListPollsWithoutTag = `
-	SELECT p.id, p.title, p.created_at
+	SELECT ` + pollColumns + `
 	FROM polls p
 	LEFT JOIN votes v ON v.poll_id = p.id AND v.user_id = $1
-	WHERE v.poll_id IS NULL
-	ORDER BY p.created_at DESC
+	WHERE v.poll_id IS NULL AND p.published_at IS NOT NULL AND (p.closes_at IS NULL OR p.closes_at > now())
+		AND (p.time_limit_seconds = 0 OR p.published_at + make_interval(secs => p.time_limit_seconds) > now())
+		AND NOT EXISTS (SELECT 1 FROM anonymous_ballots ab WHERE ab.poll_id = p.id AND ab.voter_hash =
+			encode(sha256(convert_to($4 || ':' || p.id::TEXT, 'UTF8')), 'hex'))
+		AND (p.visibility = 'public' OR (p.visibility = 'private' AND (p.user_id = $1 OR ` + queryIsFeedParticipant + `)))
+		AND ($5 = '' OR p.search_vector @@ websearch_to_tsquery('simple', $5))
+	ORDER BY p.published_at DESC, p.id DESC
 	LIMIT $2 OFFSET $3`
 
 	queryListPollsByTag = `
-	SELECT p.id, p.title, p.created_at
+	SELECT ` + pollColumns + `
 	FROM polls p
 	JOIN poll_tags pt ON pt.poll_id = p.id
 	LEFT JOIN votes v ON v.poll_id = p.id AND v.user_id = $1
-	WHERE v.poll_id IS NULL AND pt.tag_id = $4
-	ORDER BY p.created_at DESC
+	WHERE v.poll_id IS NULL AND pt.tag_id = $6 AND p.published_at IS NOT NULL
+		AND (p.closes_at IS NULL OR p.closes_at > now())
+		AND (p.time_limit_seconds = 0 OR p.published_at + make_interval(secs => p.time_limit_seconds) > now())
+		AND NOT EXISTS (SELECT 1 FROM anonymous_ballots ab WHERE ab.poll_id = p.id AND ab.voter_hash =
+			encode(sha256(convert_to($4 || ':' || p.id::TEXT, 'UTF8')), 'hex'))
+		AND (p.visibility = 'public' OR (p.visibility = 'private' AND (p.user_id = $1 OR ` + queryIsFeedParticipant + `)))
+		AND ($5 = '' OR p.search_vector @@ websearch_to_tsquery('simple', $5))
+	ORDER BY p.published_at DESC, p.id DESC
 	LIMIT $2 OFFSET $3`
 )
 
-func (c *polls) ListPollsByTag(ctx context.Context, userID, tagID int64, limit, offset int) (result []Poll, err error) {
+func (c *polls) ListPollsByTag(ctx context.Context, userID, tagID int64, voterKey, search string, limit, offset int) (result []Poll, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "list_polls")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			c.db.Vectors.Counter.IncrementVector("polls", "list_polls", metrics.StatusFailure)
 			return
 		}
 		c.db.Vectors.Counter.IncrementVector("polls", "list_polls", metrics.StatusSuccess)
-		c.db.Vectors.Histogram.ObserveResponseTime(start, "polls", "list_polls")
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "list_polls")
 	}(time.Now())
 
 	query := queryListPollsWithoutTag
-	args := []any{userID, limit, offset}
+	args := []any{userID, limit, offset, voterKey, search}
 	if tagID > 0 {
 		query = queryListPollsByTag
 		args = append(args, tagID)
@@ -126,7 +281,7 @@ func (c *polls) ListPollsByTag(ctx context.Context, userID, tagID int64, limit,
 	result = make([]Poll, 0)
 	for rows.Next() {
 		poll := Poll{}
-		err = rows.Scan(&poll.ID, &poll.Title, &poll.CreatedAt)
+		err = scanPoll(rows, &poll)
 		if err != nil {
 			return nil, errors.Join(errScanningPollInListPolls, err)
 		}
@@ -138,3 +293,94 @@ func (c *polls) ListPollsByTag(ctx context.Context, userID, tagID int64, limit,
 
 	return result, nil
 }
+
+var (
+	errPublishPoll                 = errors.New("")
+	ErrPublishPollAlreadyPublished = errors.New("")
+)
+
+const queryPublishPoll = `
+UPDATE polls
+SET publish_at = $2, published_at = $3
+WHERE id = $1 AND published_at IS NULL`
+
+// PublishPoll publishes the draft (publishedAt is given) or schedules it (only publishAt is given),
+// the published polls can't be changed anymore
+func (c *polls) PublishPoll(ctx context.Context, tx *sqlx.Tx, id int64, publishAt, publishedAt sql.NullTime) (err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "publish_poll")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "publish_poll", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "publish_poll", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "publish_poll")
+	}(time.Now())
+
+	result, err := tx.ExecContext(ctx, queryPublishPoll, id, publishAt, publishedAt)
+	if err != nil {
+		return errors.Join(errPublishPoll, err)
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return errors.Join(errPublishPoll, err)
+	} else if affected == 0 {
+		return ErrPublishPollAlreadyPublished
+	}
+
+	return nil
+}
+
+var (
+	errPublishDuePolls               = errors.New("")
+	errScanningPollInPublishDuePolls = errors.New("")
+	errIteratingInPublishDuePolls    = errors.New("")
+)
+
+// the locked rows are skipped, so the schedulers of several instances never publish the same draft twice
+const queryPublishDuePolls = `
+UPDATE polls p
+SET published_at = p.publish_at
+WHERE p.id IN (
+	SELECT id FROM polls
+	WHERE published_at IS NULL AND publish_at <= now()
+	ORDER BY publish_at
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED)
+RETURNING ` + pollColumns
+
+// PublishDuePolls publishes the drafts whose scheduled time has come, they are published at that time
+// rather than now so the feeds keep their order even if the scheduler falls behind
+func (c *polls) PublishDuePolls(ctx context.Context, tx *sqlx.Tx, limit int) (result []Poll, err error) {
+	ctx, span := c.db.StartSpan(ctx, "polls", "publish_due_polls")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			c.db.Vectors.Counter.IncrementVector("polls", "publish_due_polls", metrics.StatusFailure)
+			return
+		}
+		c.db.Vectors.Counter.IncrementVector("polls", "publish_due_polls", metrics.StatusSuccess)
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "polls", "publish_due_polls")
+	}(time.Now())
+
+	rows, err := tx.QueryContext(ctx, queryPublishDuePolls, limit)
+	if err != nil {
+		return nil, errors.Join(errPublishDuePolls, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]Poll, 0)
+	for rows.Next() {
+		poll := Poll{}
+		if err = scanPoll(rows, &poll); err != nil {
+			return nil, errors.Join(errScanningPollInPublishDuePolls, err)
+		}
+		result = append(result, poll)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInPublishDuePolls, err)
+	}
+
+	return result, nil
+}
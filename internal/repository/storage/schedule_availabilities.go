@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+// ScheduleAvailability is the answer of a voter of the schedule poll for one of its slots
+type ScheduleAvailability struct {
+	PollID       int64
+	OptionID     int64
+	UserID       int64
+	Availability string // yes, if_need_be or no
+}
+
+var (
+	errInsertingScheduleAvailability = errors.New("")
+)
+
+const queryCreateScheduleAvailability = `
+INSERT INTO schedule_availabilities (poll_id, option_id, user_id, availability)
+VALUES ($1, $2, $3, $4)`
+
+// CreateScheduleAvailabilities stores the availabilities of a voter, the vote itself has to be created
+// within the same transaction
+func (v *votes) CreateScheduleAvailabilities(ctx context.Context, tx *sqlx.Tx, availabilities []ScheduleAvailability) (err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "create_schedule_availabilities")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "create_schedule_availabilities", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "create_schedule_availabilities", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "create_schedule_availabilities")
+	}(time.Now())
+
+	for _, availability := range availabilities {
+		_, err = tx.ExecContext(ctx, queryCreateScheduleAvailability,
+			availability.PollID, availability.OptionID, availability.UserID, availability.Availability)
+		if err != nil {
+			return errors.Join(errInsertingScheduleAvailability, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	errQueryGetScheduleAvailabilities                  = errors.New("")
+	errScanningAvailabilityInGetScheduleAvailabilities = errors.New("")
+	errIteratingInGetScheduleAvailabilities            = errors.New("")
+)
+
+const queryGetScheduleAvailabilities = `
+SELECT poll_id, option_id, user_id, availability
+FROM schedule_availabilities
+WHERE poll_id = $1
+ORDER BY user_id, option_id`
+
+// GetScheduleAvailabilities returns every availability of the schedule poll grouped by the voters
+func (v *votes) GetScheduleAvailabilities(ctx context.Context, pollID int64) (result []ScheduleAvailability, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "get_schedule_availabilities")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "get_schedule_availabilities", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "get_schedule_availabilities", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "get_schedule_availabilities")
+	}(time.Now())
+
+	rows, err := v.db.QueryContext(ctx, queryGetScheduleAvailabilities, pollID)
+	if err != nil {
+		return nil, errors.Join(errQueryGetScheduleAvailabilities, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]ScheduleAvailability, 0)
+	for rows.Next() {
+		sa := ScheduleAvailability{}
+		if err = rows.Scan(&sa.PollID, &sa.OptionID, &sa.UserID, &sa.Availability); err != nil {
+			return nil, errors.Join(errScanningAvailabilityInGetScheduleAvailabilities, err)
+		}
+		result = append(result, sa)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetScheduleAvailabilities, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,451 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mohammadne/porsesh/pkg/databases/postgres"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"go.uber.org/zap"
+)
+
+type Surveys interface {
+	StartTransaction(ctx context.Context) (*sqlx.Tx, error)
+
+	CreateSurvey(ctx context.Context, tx *sqlx.Tx, survey *Survey) (id int64, err error)
+	GetSurveyByID(ctx context.Context, id int64) (result *Survey, err error)
+	CreateSurveyQuestions(ctx context.Context, tx *sqlx.Tx, surveyID int64, questions []SurveyQuestion) (err error)
+	GetSurveyQuestions(ctx context.Context, surveyID int64) (result []SurveyQuestion, err error)
+	CreateSurveyRules(ctx context.Context, tx *sqlx.Tx, surveyID int64, rules []SurveyRule) (err error)
+	GetSurveyRules(ctx context.Context, surveyID int64) (result []SurveyRule, err error)
+
+	CreateSurveyResponse(ctx context.Context, tx *sqlx.Tx, response *SurveyResponse) (err error)
+	DeleteSurveyResponse(ctx context.Context, tx *sqlx.Tx, surveyID, userID int64) (err error)
+	GetSurveyRespondentsCount(ctx context.Context, surveyID int64) (result uint64, err error)
+	GetSurveyQuestionsStatistics(ctx context.Context, surveyID int64) (result []SurveyQuestionStatistics, err error)
+}
+
+func NewSurveys(lg *zap.Logger, database *postgres.Postgres) Surveys {
+	return &surveys{logger: lg, db: database}
+}
+
+type surveys struct {
+	logger *zap.Logger
+	db     *postgres.Postgres
+}
+
+func (s *surveys) StartTransaction(ctx context.Context) (*sqlx.Tx, error) {
+	return s.db.BeginTxx(ctx, nil)
+}
+
+type Survey struct {
+	ID        int64
+	UserID    int64
+	Title     string
+	CreatedAt time.Time
+}
+
+type SurveyQuestion struct {
+	SurveyID int64
+	Position int
+	PollID   int64
+}
+
+type SurveyRule struct {
+	SurveyID   int64
+	Position   int
+	OptionSort int
+	JumpTo     int // zero ends the survey
+}
+
+type SurveyResponse struct {
+	SurveyID int64
+	UserID   int64
+	Answers  []SurveyAnswer
+}
+
+type SurveyAnswer struct {
+	Position int
+	Answered bool
+}
+
+type SurveyQuestionStatistics struct {
+	Position int
+	Reached  uint64
+	Answered uint64
+}
+
+var (
+	ErrInsertingSurvey = errors.New("")
+)
+
+const queryCreateSurvey = `
+INSERT INTO surveys (user_id, title, created_at)
+VALUES ($1, $2, $3)
+RETURNING id`
+
+func (s *surveys) CreateSurvey(ctx context.Context, tx *sqlx.Tx, survey *Survey) (id int64, err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "create_survey")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "create_survey", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "create_survey", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "create_survey")
+	}(time.Now())
+
+	err = tx.QueryRowContext(ctx, queryCreateSurvey, survey.UserID, survey.Title, time.Now()).Scan(&id)
+	if err != nil {
+		return -1, errors.Join(ErrInsertingSurvey, err)
+	}
+
+	return id, nil
+}
+
+var (
+	errGetSurveyByID = errors.New("")
+)
+
+const queryGetSurveyByID = `
+SELECT id, user_id, title, created_at
+FROM surveys
+WHERE id = $1`
+
+func (s *surveys) GetSurveyByID(ctx context.Context, id int64) (result *Survey, err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "get_survey_by_id")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_by_id", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_by_id", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "get_survey_by_id")
+	}(time.Now())
+
+	result = &Survey{}
+	err = s.db.QueryRowContext(ctx, queryGetSurveyByID, id).
+		Scan(&result.ID, &result.UserID, &result.Title, &result.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Join(errGetSurveyByID, err)
+	}
+
+	return result, nil
+}
+
+var (
+	ErrInsertingSurveyQuestion        = errors.New("")
+	ErrCreateSurveyQuestionsDuplicate = errors.New("")
+)
+
+const queryCreateSurveyQuestion = `
+INSERT INTO survey_questions (survey_id, position, poll_id)
+VALUES ($1, $2, $3)`
+
+func (s *surveys) CreateSurveyQuestions(ctx context.Context, tx *sqlx.Tx, surveyID int64, questions []SurveyQuestion) (err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "create_survey_questions")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "create_survey_questions", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "create_survey_questions", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "create_survey_questions")
+	}(time.Now())
+
+	for _, question := range questions {
+		_, err := tx.ExecContext(ctx, queryCreateSurveyQuestion, surveyID, question.Position, question.PollID)
+		if err != nil {
+			if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == postgres.UniqueConstraintViolatedCode {
+				return ErrCreateSurveyQuestionsDuplicate
+			}
+			return errors.Join(ErrInsertingSurveyQuestion, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	errGetSurveyQuestions                   = errors.New("")
+	errScanningQuestionInGetSurveyQuestions = errors.New("")
+	errIteratingInGetSurveyQuestions        = errors.New("")
+)
+
+const queryGetSurveyQuestions = `
+SELECT survey_id, position, poll_id
+FROM survey_questions
+WHERE survey_id = $1
+ORDER BY position`
+
+func (s *surveys) GetSurveyQuestions(ctx context.Context, surveyID int64) (result []SurveyQuestion, err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "get_survey_questions")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_questions", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_questions", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "get_survey_questions")
+	}(time.Now())
+
+	rows, err := s.db.QueryContext(ctx, queryGetSurveyQuestions, surveyID)
+	if err != nil {
+		return nil, errors.Join(errGetSurveyQuestions, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]SurveyQuestion, 0)
+	for rows.Next() {
+		question := SurveyQuestion{}
+		err = rows.Scan(&question.SurveyID, &question.Position, &question.PollID)
+		if err != nil {
+			return nil, errors.Join(errScanningQuestionInGetSurveyQuestions, err)
+		}
+		result = append(result, question)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetSurveyQuestions, err)
+	}
+
+	return result, nil
+}
+
+var (
+	ErrInsertingSurveyRule = errors.New("")
+)
+
+const queryCreateSurveyRule = `
+INSERT INTO survey_rules (survey_id, position, option_sort, jump_to)
+VALUES ($1, $2, $3, $4)`
+
+func (s *surveys) CreateSurveyRules(ctx context.Context, tx *sqlx.Tx, surveyID int64, rules []SurveyRule) (err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "create_survey_rules")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "create_survey_rules", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "create_survey_rules", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "create_survey_rules")
+	}(time.Now())
+
+	for _, rule := range rules {
+		_, err := tx.ExecContext(ctx, queryCreateSurveyRule, surveyID, rule.Position, rule.OptionSort, rule.JumpTo)
+		if err != nil {
+			return errors.Join(ErrInsertingSurveyRule, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	errGetSurveyRules               = errors.New("")
+	errScanningRuleInGetSurveyRules = errors.New("")
+	errIteratingInGetSurveyRules    = errors.New("")
+)
+
+const queryGetSurveyRules = `
+SELECT survey_id, position, option_sort, jump_to
+FROM survey_rules
+WHERE survey_id = $1
+ORDER BY position, option_sort`
+
+func (s *surveys) GetSurveyRules(ctx context.Context, surveyID int64) (result []SurveyRule, err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "get_survey_rules")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_rules", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_rules", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "get_survey_rules")
+	}(time.Now())
+
+	rows, err := s.db.QueryContext(ctx, queryGetSurveyRules, surveyID)
+	if err != nil {
+		return nil, errors.Join(errGetSurveyRules, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]SurveyRule, 0)
+	for rows.Next() {
+		rule := SurveyRule{}
+		err = rows.Scan(&rule.SurveyID, &rule.Position, &rule.OptionSort, &rule.JumpTo)
+		if err != nil {
+			return nil, errors.Join(errScanningRuleInGetSurveyRules, err)
+		}
+		result = append(result, rule)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetSurveyRules, err)
+	}
+
+	return result, nil
+}
+
+var (
+	ErrInsertingSurveyResponse           = errors.New("")
+	ErrInsertingSurveyAnswer             = errors.New("")
+	ErrCreateSurveyResponseAlreadyExists = errors.New("")
+)
+
+const queryCreateSurveyResponse = `
+INSERT INTO survey_responses (survey_id, user_id, submitted_at)
+VALUES ($1, $2, $3)`
+
+const queryCreateSurveyAnswer = `
+INSERT INTO survey_answers (survey_id, user_id, position, answered)
+VALUES ($1, $2, $3, $4)`
+
+// CreateSurveyResponse stores the response along with the questions on its path
+func (s *surveys) CreateSurveyResponse(ctx context.Context, tx *sqlx.Tx, response *SurveyResponse) (err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "create_survey_response")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "create_survey_response", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "create_survey_response", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "create_survey_response")
+	}(time.Now())
+
+	_, err = tx.ExecContext(ctx, queryCreateSurveyResponse, response.SurveyID, response.UserID, time.Now())
+	if err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == postgres.UniqueConstraintViolatedCode {
+			return ErrCreateSurveyResponseAlreadyExists
+		}
+		return errors.Join(ErrInsertingSurveyResponse, err)
+	}
+
+	for _, answer := range response.Answers {
+		_, err = tx.ExecContext(ctx, queryCreateSurveyAnswer, response.SurveyID, response.UserID, answer.Position, answer.Answered)
+		if err != nil {
+			return errors.Join(ErrInsertingSurveyAnswer, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	ErrDeletingSurveyResponse = errors.New("")
+)
+
+const queryDeleteSurveyResponse = `
+DELETE FROM survey_responses
+WHERE survey_id = $1 AND user_id = $2`
+
+// DeleteSurveyResponse takes back the response along with its answers, so it can be submitted again
+func (s *surveys) DeleteSurveyResponse(ctx context.Context, tx *sqlx.Tx, surveyID, userID int64) (err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "delete_survey_response")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "delete_survey_response", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "delete_survey_response", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "delete_survey_response")
+	}(time.Now())
+
+	_, err = tx.ExecContext(ctx, queryDeleteSurveyResponse, surveyID, userID)
+	if err != nil {
+		return errors.Join(ErrDeletingSurveyResponse, err)
+	}
+
+	return nil
+}
+
+var (
+	errQueryGetSurveyRespondentsCount = errors.New("")
+)
+
+const queryGetSurveyRespondentsCount = `
+SELECT COUNT(*)
+FROM survey_responses
+WHERE survey_id = $1`
+
+func (s *surveys) GetSurveyRespondentsCount(ctx context.Context, surveyID int64) (result uint64, err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "get_survey_respondents_count")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_respondents_count", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_respondents_count", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "get_survey_respondents_count")
+	}(time.Now())
+
+	err = s.db.QueryRowContext(ctx, queryGetSurveyRespondentsCount, surveyID).Scan(&result)
+	if err != nil {
+		return 0, errors.Join(errQueryGetSurveyRespondentsCount, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errGetSurveyQuestionsStatistics                     = errors.New("")
+	errScanningStatisticsInGetSurveyQuestionsStatistics = errors.New("")
+	errIteratingInGetSurveyQuestionsStatistics          = errors.New("")
+)
+
+const queryGetSurveyQuestionsStatistics = `
+SELECT q.position, COUNT(a.user_id) AS reached, COUNT(a.user_id) FILTER (WHERE a.answered) AS answered
+FROM survey_questions q
+LEFT JOIN survey_answers a ON a.survey_id = q.survey_id AND a.position = q.position
+WHERE q.survey_id = $1
+GROUP BY q.position
+ORDER BY q.position`
+
+func (s *surveys) GetSurveyQuestionsStatistics(ctx context.Context, surveyID int64) (result []SurveyQuestionStatistics, err error) {
+	ctx, span := s.db.StartSpan(ctx, "surveys", "get_survey_questions_statistics")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_questions_statistics", metrics.StatusFailure)
+			return
+		}
+		s.db.Vectors.Counter.IncrementVector("surveys", "get_survey_questions_statistics", metrics.StatusSuccess)
+		s.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "surveys", "get_survey_questions_statistics")
+	}(time.Now())
+
+	rows, err := s.db.QueryContext(ctx, queryGetSurveyQuestionsStatistics, surveyID)
+	if err != nil {
+		return nil, errors.Join(errGetSurveyQuestionsStatistics, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]SurveyQuestionStatistics, 0)
+	for rows.Next() {
+		statistics := SurveyQuestionStatistics{}
+		err = rows.Scan(&statistics.Position, &statistics.Reached, &statistics.Answered)
+		if err != nil {
+			return nil, errors.Join(errScanningStatisticsInGetSurveyQuestionsStatistics, err)
+		}
+		result = append(result, statistics)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetSurveyQuestionsStatistics, err)
+	}
+
+	return result, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/mohammadne/porsesh/pkg/databases/postgres"
 	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
 	"go.uber.org/zap"
 )
 
@@ -43,13 +44,15 @@ ON CONFLICT (name) DO NOTHING
 RETURNING id`
 
 func (c *tags) CreateTags(ctx context.Context, tx *sqlx.Tx, tags []Tag) (idsMap map[string]int64, err error) {
+	ctx, span := c.db.StartSpan(ctx, "tags", "create_tags")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			c.db.Vectors.Counter.IncrementVector("tags", "create_tags", metrics.StatusFailure)
 			return
 		}
 		c.db.Vectors.Counter.IncrementVector("tags", "create_tags", metrics.StatusSuccess)
-		c.db.Vectors.Histogram.ObserveResponseTime(start, "tags", "create_tags")
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "tags", "create_tags")
 	}(time.Now())
 
 	idsMap = make(map[string]int64, len(tags))
@@ -81,13 +84,15 @@ FROM tags
 WHERE NAME IN (?)`
 
 func (c *tags) GetTagsByNames(ctx context.Context, tx *sqlx.Tx, names []string) (result []Tag, err error) {
+	ctx, span := c.db.StartSpan(ctx, "tags", "get_tags_by_names")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			c.db.Vectors.Counter.IncrementVector("tags", "get_tags_by_names", metrics.StatusFailure)
 			return
 		}
 		c.db.Vectors.Counter.IncrementVector("tags", "get_tags_by_names", metrics.StatusSuccess)
-		c.db.Vectors.Histogram.ObserveResponseTime(start, "tags", "get_tags_by_names")
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "tags", "get_tags_by_names")
 	}(time.Now())
 
 	expandedQuery, args, err := sqlx.In(queryGetTagsByNames, names)
@@ -131,13 +136,15 @@ FROM tags
 WHERE NAME = $1`
 
 func (c *tags) GetTagByName(ctx context.Context, name string) (result *Tag, err error) {
+	ctx, span := c.db.StartSpan(ctx, "tags", "get_tag_by_name")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			c.db.Vectors.Counter.IncrementVector("tags", "get_tag_by_name", metrics.StatusFailure)
 			return
 		}
 		c.db.Vectors.Counter.IncrementVector("tags", "get_tag_by_name", metrics.StatusSuccess)
-		c.db.Vectors.Histogram.ObserveResponseTime(start, "tags", "get_tag_by_name")
+		c.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "tags", "get_tag_by_name")
 	}(time.Now())
 
 	result = new(Tag)
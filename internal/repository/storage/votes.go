@@ -6,16 +6,39 @@ import (
 	"errors"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/mohammadne/porsesh/pkg/databases/postgres"
 	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
 	"go.uber.org/zap"
 )
 
 type Votes interface {
-	CreateVote(ctx context.Context, vote *Vote) (err error)
+	StartTransaction(ctx context.Context) (*sqlx.Tx, error)
+
+	CreateVote(ctx context.Context, tx *sqlx.Tx, vote *Vote) (err error)
 	GetPollOptionVotesCount(ctx context.Context, optionID int64) (result uint64, err error)
 	GetCurrentDateUserVoteCount(ctx context.Context, userID int64) (result int64, err error)
+	HasUserActed(ctx context.Context, userID, pollID int64) (result bool, err error)
+	ListPollVoters(ctx context.Context, pollID, optionID int64, limit, offset int) (result []Vote, err error)
+	GetPollValuesDistribution(ctx context.Context, pollID int64) (result map[int]uint64, err error)
+
+	GetPollOtherVotesCount(ctx context.Context, pollID int64) (result uint64, err error)
+	ListOtherAnswers(ctx context.Context, pollID int64, search string, limit, offset int) (result []Vote, err error)
+	GetFrequentOtherAnswers(ctx context.Context, pollID int64, limit int) (result []OtherAnswerFrequency, err error)
+	SetOtherAnswerHidden(ctx context.Context, tx *sqlx.Tx, pollID, userID int64, hidden bool) (err error)
+	PromoteOtherAnswer(ctx context.Context, tx *sqlx.Tx, pollID, optionID int64, text string) (result int64, err error)
+	GetQuizLeaderboard(ctx context.Context, tagID int64, quizSet string, limit, offset int) (result []LeaderboardEntry, err error)
+
+	CreateScheduleAvailabilities(ctx context.Context, tx *sqlx.Tx, availabilities []ScheduleAvailability) (err error)
+	GetScheduleAvailabilities(ctx context.Context, pollID int64) (result []ScheduleAvailability, err error)
+
+	CreateAnonymousBallot(ctx context.Context, tx *sqlx.Tx, ballot *AnonymousBallot) (err error)
+	RevokeAnonymousBallot(ctx context.Context, tx *sqlx.Tx, ballot *AnonymousBallot) (err error)
+	IncrementAnonymousTally(ctx context.Context, pollID, optionID int64) (err error)
+	HasAnonymousBallot(ctx context.Context, pollID int64, voterHash string) (result bool, err error)
+	GetAnonymousTallies(ctx context.Context, pollID int64) (result map[int64]uint64, err error)
 }
 
 func NewVotes(lg *zap.Logger, database *postgres.Postgres) Votes {
@@ -27,37 +50,59 @@ type votes struct {
 	db     *postgres.Postgres
 }
 
+func (v *votes) StartTransaction(ctx context.Context) (*sqlx.Tx, error) {
+	return v.db.BeginTxx(ctx, nil)
+}
+
 type Vote struct {
 	UserID   int64
 	PollID   int64
+	Skipped  bool
 	OptionID sql.NullInt64
-	ActedAt  time.Time
+	Value    sql.NullInt64 // only set on the scale polls
+	// the free-text answer of the "other" choice, hidden by the moderation
+	OtherText   sql.NullString
+	OtherHidden bool
+	Correct     sql.NullBool // only set on the quizzes
+	Points      int
+	ActedAt     time.Time // now when zero
 }
 
 var (
 	ErrInsertingVote           = errors.New("")
 	ErrCreateVotePollNotExists = errors.New("")
+	ErrCreateVoteAlreadyExists = errors.New("")
 )
 
 const queryCreateVote = `
-INSERT INTO votes (user_id, poll_id, option_id, acted_at)
-VALUES ($1, $2, $3, $4)`
+INSERT INTO votes (user_id, poll_id, skipped, option_id, value, other_text, other_hidden, correct, points, acted_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
-func (v *votes) CreateVote(ctx context.Context, vote *Vote) (err error) {
+func (v *votes) CreateVote(ctx context.Context, tx *sqlx.Tx, vote *Vote) (err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "create_vote")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			v.db.Vectors.Counter.IncrementVector("votes", "create_vote", metrics.StatusFailure)
 			return
 		}
 		v.db.Vectors.Counter.IncrementVector("votes", "create_vote", metrics.StatusSuccess)
-		v.db.Vectors.Histogram.ObserveResponseTime(start, "votes", "create_vote")
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "create_vote")
 	}(time.Now())
 
-	_, err = v.db.ExecContext(ctx, queryCreateVote,
-		vote.UserID, vote.PollID, vote.OptionID, time.Now())
+	actedAt := vote.ActedAt
+	if actedAt.IsZero() {
+		actedAt = time.Now()
+	}
+
+	_, err = tx.ExecContext(ctx, queryCreateVote,
+		vote.UserID, vote.PollID, vote.Skipped, vote.OptionID, vote.Value, vote.OtherText, vote.OtherHidden,
+		vote.Correct, vote.Points, actedAt)
 	if err != nil {
 		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == postgres.ForeignKeyViolatedCode {
 			return ErrCreateVotePollNotExists
+		} else if ok && pgErr.Code == postgres.UniqueConstraintViolatedCode {
+			return ErrCreateVoteAlreadyExists
 		}
 		return errors.Join(ErrInsertingVote, err)
 	}
@@ -76,13 +121,15 @@ WHERE option_id = $1
 GROUP BY option_id`
 
 func (v *votes) GetPollOptionVotesCount(ctx context.Context, optionID int64) (result uint64, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "get_poll_option_votes_count")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			v.db.Vectors.Counter.IncrementVector("votes", "get_poll_option_votes_count", metrics.StatusFailure)
 			return
 		}
 		v.db.Vectors.Counter.IncrementVector("votes", "get_poll_option_votes_count", metrics.StatusSuccess)
-		v.db.Vectors.Histogram.ObserveResponseTime(start, "votes", "get_poll_option_votes_count")
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "get_poll_option_votes_count")
 	}(time.Now())
 
 	err = v.db.QueryRowContext(ctx, queryGetPollOptionCount, optionID).Scan(&result)
@@ -100,19 +147,24 @@ var (
 	errQueryGetCurrentDateUserVoteCount = errors.New("")
 )
 
+// anonymous ballots aren't in votes, their daily actions are kept apart from the polls
 const queryGetCurrentDateUserVoteCount = `
-SELECT COUNT(*) 
-FROM votes 
-WHERE user_id = $1 AND acted_at::date = CURRENT_DATE`
+SELECT (
+	SELECT COUNT(*) FROM votes WHERE user_id = $1 AND acted_at::date = CURRENT_DATE
+) + COALESCE((
+	SELECT actions FROM anonymous_daily_actions WHERE user_id = $1 AND acted_on = CURRENT_DATE
+), 0)`
 
 func (v *votes) GetCurrentDateUserVoteCount(ctx context.Context, userID int64) (result int64, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "get_current_date_user_vote_count")
 	defer func(start time.Time) {
+		tracing.End(span, err)
 		if err != nil {
 			v.db.Vectors.Counter.IncrementVector("votes", "get_current_date_user_vote_count", metrics.StatusFailure)
 			return
 		}
 		v.db.Vectors.Counter.IncrementVector("votes", "get_current_date_user_vote_count", metrics.StatusSuccess)
-		v.db.Vectors.Histogram.ObserveResponseTime(start, "votes", "get_current_date_user_vote_count")
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "get_current_date_user_vote_count")
 	}(time.Now())
 
 	err = v.db.QueryRowContext(ctx, queryGetCurrentDateUserVoteCount, userID).Scan(&result)
@@ -125,3 +177,190 @@ func (v *votes) GetCurrentDateUserVoteCount(ctx context.Context, userID int64) (
 
 	return result, nil
 }
+
+var (
+	errQueryHasUserActed = errors.New("")
+)
+
+const queryHasUserActed = `
+SELECT EXISTS (
+	SELECT 1 FROM votes WHERE user_id = $1 AND poll_id = $2
+)`
+
+// HasUserActed reports whether the user has either voted or skipped the poll
+func (v *votes) HasUserActed(ctx context.Context, userID, pollID int64) (result bool, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "has_user_acted")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "has_user_acted", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "has_user_acted", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "has_user_acted")
+	}(time.Now())
+
+	err = v.db.QueryRowContext(ctx, queryHasUserActed, userID, pollID).Scan(&result)
+	if err != nil {
+		return false, errors.Join(errQueryHasUserActed, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errListPollVoters               = errors.New("")
+	errScanningVoteInListPollVoters = errors.New("")
+	errIteratingInListPollVoters    = errors.New("")
+)
+
+const queryListPollVoters = `
+SELECT user_id, poll_id, option_id, value, other_text, other_hidden, acted_at
+FROM votes
+WHERE poll_id = $1 AND NOT skipped AND ($2::BIGINT = 0 OR option_id = $2)
+ORDER BY acted_at, user_id
+LIMIT $3 OFFSET $4`
+
+// ListPollVoters lists the users who have voted on the poll (optionally only for the given option)
+func (v *votes) ListPollVoters(ctx context.Context, pollID, optionID int64, limit, offset int) (result []Vote, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "list_poll_voters")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "list_poll_voters", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "list_poll_voters", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "list_poll_voters")
+	}(time.Now())
+
+	rows, err := v.db.QueryContext(ctx, queryListPollVoters, pollID, optionID, limit, offset)
+	if err != nil {
+		return nil, errors.Join(errListPollVoters, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]Vote, 0)
+	for rows.Next() {
+		vote := Vote{}
+		err = rows.Scan(&vote.UserID, &vote.PollID, &vote.OptionID, &vote.Value,
+			&vote.OtherText, &vote.OtherHidden, &vote.ActedAt)
+		if err != nil {
+			return nil, errors.Join(errScanningVoteInListPollVoters, err)
+		}
+		result = append(result, vote)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInListPollVoters, err)
+	}
+
+	return result, nil
+}
+
+var (
+	errGetPollValuesDistribution                = errors.New("")
+	errScanningValueInGetPollValuesDistribution = errors.New("")
+	errIteratingInGetPollValuesDistribution     = errors.New("")
+)
+
+const queryGetPollValuesDistribution = `
+SELECT value, COUNT(*)
+FROM votes
+WHERE poll_id = $1 AND value IS NOT NULL
+GROUP BY value`
+
+// GetPollValuesDistribution counts the votes of every value on the scale poll
+func (v *votes) GetPollValuesDistribution(ctx context.Context, pollID int64) (result map[int]uint64, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "get_poll_values_distribution")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "get_poll_values_distribution", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "get_poll_values_distribution", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "get_poll_values_distribution")
+	}(time.Now())
+
+	rows, err := v.db.QueryContext(ctx, queryGetPollValuesDistribution, pollID)
+	if err != nil {
+		return nil, errors.Join(errGetPollValuesDistribution, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make(map[int]uint64)
+	for rows.Next() {
+		var value int
+		var count uint64
+		if err = rows.Scan(&value, &count); err != nil {
+			return nil, errors.Join(errScanningValueInGetPollValuesDistribution, err)
+		}
+		result[value] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetPollValuesDistribution, err)
+	}
+
+	return result, nil
+}
+
+type LeaderboardEntry struct {
+	UserID   int64
+	Points   int64
+	Correct  int64
+	Answered int64
+}
+
+var (
+	errGetQuizLeaderboard                = errors.New("")
+	errScanningEntryInGetQuizLeaderboard = errors.New("")
+	errIteratingInGetQuizLeaderboard     = errors.New("")
+)
+
+// skips aren't answers, so they neither count as answered nor score
+const queryGetQuizLeaderboard = `
+SELECT v.user_id, SUM(v.points) AS points, COUNT(*) FILTER (WHERE v.correct) AS correct, COUNT(*) AS answered
+FROM votes v
+JOIN polls p ON p.id = v.poll_id
+WHERE p.type = 'quiz' AND NOT v.skipped AND p.visibility = 'public' AND p.published_at IS NOT NULL
+	AND ($1::BIGINT = 0 OR EXISTS (SELECT 1 FROM poll_tags pt WHERE pt.poll_id = p.id AND pt.tag_id = $1))
+	AND ($2::TEXT = '' OR p.quiz_set = $2)
+GROUP BY v.user_id
+ORDER BY points DESC, correct DESC, v.user_id
+LIMIT $3 OFFSET $4`
+
+// GetQuizLeaderboard ranks the users over the quizzes of a tag and/or a quiz set (zero values match all),
+// only the published public quizzes count as the leaderboard is served to anyone
+func (v *votes) GetQuizLeaderboard(ctx context.Context, tagID int64, quizSet string, limit, offset int) (result []LeaderboardEntry, err error) {
+	ctx, span := v.db.StartSpan(ctx, "votes", "get_quiz_leaderboard")
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		if err != nil {
+			v.db.Vectors.Counter.IncrementVector("votes", "get_quiz_leaderboard", metrics.StatusFailure)
+			return
+		}
+		v.db.Vectors.Counter.IncrementVector("votes", "get_quiz_leaderboard", metrics.StatusSuccess)
+		v.db.Vectors.Histogram.ObserveResponseTimeContext(ctx, start, "votes", "get_quiz_leaderboard")
+	}(time.Now())
+
+	rows, err := v.db.QueryContext(ctx, queryGetQuizLeaderboard, tagID, quizSet, limit, offset)
+	if err != nil {
+		return nil, errors.Join(errGetQuizLeaderboard, err)
+	}
+	defer rows.Close() // ignore error
+
+	result = make([]LeaderboardEntry, 0)
+	for rows.Next() {
+		entry := LeaderboardEntry{}
+		err = rows.Scan(&entry.UserID, &entry.Points, &entry.Correct, &entry.Answered)
+		if err != nil {
+			return nil, errors.Join(errScanningEntryInGetQuizLeaderboard, err)
+		}
+		result = append(result, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Join(errIteratingInGetQuizLeaderboard, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+var (
+	ErrPollAccessDenied = errors.New("")
+)
+
+// accessiblePoll retrieves the poll (nil if it doesn't exist) and makes sure the user
+// is allowed to interact with it, the drafts and the private polls don't exist for anyone
+// but the owner and (for the private ones) the participants, so their ids can't be probed
+func (p *pools) accessiblePoll(ctx context.Context, pollID entities.PollID, u entities.UserID) (*storage.Poll, error) {
+	storagePoll, err := p.pollsStorage.GetPollByID(ctx, int64(pollID))
+	if err != nil || storagePoll == nil {
+		return nil, err
+	}
+
+	if !storagePoll.PublishedAt.Valid && storagePoll.UserID != int64(u) {
+		return nil, nil
+	}
+
+	if entities.PollVisibility(storagePoll.Visibility) != entities.PollVisibilityPrivate ||
+		storagePoll.UserID == int64(u) {
+		return storagePoll, nil
+	}
+
+	participant, err := p.pollsStorage.IsPollParticipant(ctx, int64(pollID), int64(u))
+	if err != nil || !participant {
+		return nil, err
+	}
+
+	return storagePoll, nil
+}
+
+// ownedPoll retrieves the poll (nil if it doesn't exist for the user) and makes sure the user is its owner
+func (p *pools) ownedPoll(ctx context.Context, pollID entities.PollID, owner entities.UserID) (*storage.Poll, error) {
+	storagePoll, err := p.accessiblePoll(ctx, pollID, owner)
+	if err != nil || storagePoll == nil {
+		return nil, err
+	}
+
+	if storagePoll.UserID != int64(owner) {
+		return nil, ErrPollAccessDenied
+	}
+
+	return storagePoll, nil
+}
+
+func userIDs(users []entities.UserID) []int64 {
+	result := make([]int64, 0, len(users))
+	for _, user := range users {
+		result = append(result, int64(user))
+	}
+	return result
+}
+
+func groupIDs(groups []entities.GroupID) []int64 {
+	result := make([]int64, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, int64(group))
+	}
+	return result
+}
+
+var (
+	ErrInvalidResolvePollArguments = errors.New("")
+	ErrResolvePollPollNotExists    = errors.New("")
+)
+
+// ResolvePoll translates the reference (either the numeric id or the slug) into the poll id,
+// unlisted polls can only be referenced by their slug unless the user is the owner
+func (p *pools) ResolvePoll(ctx context.Context, ref string, u entities.UserID) (result entities.PollID, err error) {
+	ctx, span := tracer.Start(ctx, "pools.ResolvePoll")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if len(ref) == 0 || len(ref) > pollSlugLength*2 {
+			return -1, ErrInvalidResolvePollArguments
+		}
+	}
+
+	storagePoll, err := p.resolvePoll(ctx, ref, u)
+	if err != nil {
+		return -1, err
+	} else if storagePoll == nil {
+		return -1, ErrResolvePollPollNotExists
+	}
+
+	return entities.PollID(storagePoll.ID), nil
+}
+
+func (p *pools) resolvePoll(ctx context.Context, ref string, u entities.UserID) (*storage.Poll, error) {
+	id, err := strconv.ParseInt(ref, 10, 64)
+	if err != nil { // slugs always contain a letter
+		return p.pollsStorage.GetPollBySlug(ctx, ref)
+	}
+
+	storagePoll, err := p.pollsStorage.GetPollByID(ctx, id)
+	if err != nil || storagePoll == nil {
+		return nil, err
+	}
+
+	if entities.PollVisibility(storagePoll.Visibility) == entities.PollVisibilityUnlisted &&
+		storagePoll.UserID != int64(u) {
+		return nil, nil // ids are enumerable, don't reveal the unlisted polls through them
+	}
+
+	return storagePoll, nil
+}
+
+// resultsVisible tells whether the user is allowed to see the results of the poll, the owner always is
+func (p *pools) resultsVisible(ctx context.Context, storagePoll *storage.Poll, u entities.UserID) (bool, error) {
+	poll := pollEntity(storagePoll)
+	if poll.UserID == u {
+		return true, nil
+	}
+
+	switch poll.ResultsVisibility {
+	case entities.ResultsVisibilityAlways:
+		return true, nil
+	case entities.ResultsVisibilityAfterVote:
+		return p.hasActed(ctx, storagePoll, u)
+	case entities.ResultsVisibilityAfterClose:
+		return poll.IsClosed(time.Now()), nil
+	default:
+		return false, nil
+	}
+}
+
+// hasActed tells whether the user has voted on or skipped the poll, the anonymous ballots are looked up by
+// the voter hash
+func (p *pools) hasActed(ctx context.Context, storagePoll *storage.Poll, u entities.UserID) (bool, error) {
+	if storagePoll.Anonymous {
+		return p.votesStorage.HasAnonymousBallot(ctx, storagePoll.ID, voterHash(voterKey(p.voterPepper, u), storagePoll.ID))
+	}
+	return p.votesStorage.HasUserActed(ctx, int64(u), storagePoll.ID)
+}
@@ -0,0 +1,329 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	_ "image/gif" // registers the decoder
+	_ "image/png" // registers the decoder
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/blobs"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"github.com/mohammadne/porsesh/pkg/slugs"
+	"go.uber.org/zap"
+)
+
+type Attachments interface {
+	Upload(ctx context.Context, u entities.UserID, contentType string, content []byte) (*entities.Attachment, error)
+	Media(ctx context.Context, key string) (*blobs.Blob, error)
+}
+
+func NewAttachments(logger *zap.Logger, auditor Auditor, store blobs.Store, ps storage.Polls) Attachments {
+	return &attachments{
+		logger:       logger,
+		auditor:      auditor,
+		store:        store,
+		pollsStorage: ps,
+	}
+}
+
+type attachments struct {
+	logger  *zap.Logger
+	auditor Auditor
+	store   blobs.Store
+	// storages
+	pollsStorage storage.Polls
+}
+
+const (
+	MaxAttachmentSize   = 8 << 20 // bytes
+	attachmentKeyPrefix = "attachments/"
+	attachmentKeyLength = 24
+	maxImagePixels      = 25_000_000 // guards the decoding against the decompression bombs
+	thumbnailSize       = 320        // of the longer side
+	thumbnailQuality    = 80
+)
+
+// attachmentTypes are the accepted content types by their extensions, the thumbnails
+// are only generated for the ones which have a decoder registered
+var attachmentTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+	"audio/mpeg": ".mp3",
+}
+
+var (
+	ErrInvalidUploadArguments = errors.New("")
+	ErrUploadTooLarge         = errors.New("")
+	ErrUploadUnsupportedType  = errors.New("")
+)
+
+// Upload stores the media of the user, the declared content type must match the sniffed one
+func (a *attachments) Upload(ctx context.Context, u entities.UserID, contentType string, content []byte) (result *entities.Attachment, err error) {
+	ctx, span := tracer.Start(ctx, "attachments.Upload")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if u <= 0 || len(content) == 0 {
+			return nil, ErrInvalidUploadArguments
+		} else if len(content) > MaxAttachmentSize {
+			return nil, ErrUploadTooLarge
+		}
+
+		declared, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, ErrUploadUnsupportedType
+		}
+
+		sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(content))
+		if _, allowed := attachmentTypes[declared]; !allowed || declared != sniffed {
+			return nil, ErrUploadUnsupportedType
+		}
+		contentType = declared
+	}
+
+	name, err := slugs.Generate(attachmentKeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	directory := attachmentKeyPrefix + now.Format("2006/01") + "/"
+	attachment := &entities.Attachment{
+		UserID:      u,
+		Key:         directory + name + attachmentTypes[contentType],
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		CreatedAt:   now,
+	}
+
+	var thumbnailContent []byte
+	if config, _, err := image.DecodeConfig(bytes.NewReader(content)); err == nil {
+		if config.Width*config.Height > maxImagePixels {
+			return nil, ErrInvalidUploadArguments
+		}
+
+		decoded, _, err := image.Decode(bytes.NewReader(content))
+		if err != nil {
+			return nil, ErrInvalidUploadArguments
+		}
+
+		var buffer bytes.Buffer
+		if err = jpeg.Encode(&buffer, thumbnail(decoded, thumbnailSize), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return nil, err
+		}
+
+		attachment.Width, attachment.Height = config.Width, config.Height
+		attachment.ThumbnailKey, thumbnailContent = directory+name+"_thumb.jpg", buffer.Bytes()
+	}
+
+	if err = a.store.Put(ctx, attachment.Key, contentType, content); err != nil {
+		return nil, err
+	}
+	if len(thumbnailContent) != 0 {
+		if err = a.store.Put(ctx, attachment.ThumbnailKey, "image/jpeg", thumbnailContent); err != nil {
+			a.discard(ctx, attachment)
+			return nil, err
+		}
+	}
+
+	if err = a.createAttachment(ctx, attachment); err != nil {
+		a.discard(ctx, attachment)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (a *attachments) createAttachment(ctx context.Context, attachment *entities.Attachment) error {
+	tx, err := a.pollsStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	id, err := a.pollsStorage.CreateAttachment(ctx, tx, &storage.Attachment{
+		UserID:       int64(attachment.UserID),
+		Key:          attachment.Key,
+		ThumbnailKey: sql.NullString{Valid: len(attachment.ThumbnailKey) != 0, String: attachment.ThumbnailKey},
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		Width:        attachment.Width,
+		Height:       attachment.Height,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = a.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    attachment.UserID,
+		Action:     entities.AuditActionUploadAttachment,
+		TargetType: entities.AuditTargetAttachment,
+		TargetID:   id,
+		Metadata:   map[string]any{"content_type": attachment.ContentType, "size": attachment.Size},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	attachment.ID = entities.AttachmentID(id)
+	return nil
+}
+
+// discard removes the blobs of the attachment which couldn't be stored, the orphans are only logged
+func (a *attachments) discard(ctx context.Context, attachment *entities.Attachment) {
+	for _, key := range []string{attachment.Key, attachment.ThumbnailKey} {
+		if len(key) == 0 {
+			continue
+		}
+		if err := a.store.Delete(ctx, key); err != nil {
+			logger.FromContext(ctx, a.logger).Error("error discarding the attachment blob", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+var (
+	ErrMediaNotExists = errors.New("")
+)
+
+// Media retrieves the blob of an attachment or its thumbnail, the caller has to close its body
+func (a *attachments) Media(ctx context.Context, key string) (result *blobs.Blob, err error) {
+	ctx, span := tracer.Start(ctx, "attachments.Media")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if !strings.HasPrefix(key, attachmentKeyPrefix) || !blobs.ValidKey(key) {
+			return nil, ErrMediaNotExists
+		}
+	}
+
+	blob, err := a.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobs.ErrNotFound) {
+			return nil, ErrMediaNotExists
+		}
+		return nil, err
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(key)); len(blob.ContentType) == 0 && len(contentType) != 0 {
+		blob.ContentType = contentType
+	}
+
+	return blob, nil
+}
+
+// thumbnail downscales the image to fit the size by averaging the covered pixels,
+// the transparent areas are flattened on white since the thumbnails are JPEGs
+func thumbnail(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	targetWidth, targetHeight := width, height
+	if longer := max(width, height); longer > size {
+		targetWidth, targetHeight = max(1, width*size/longer), max(1, height*size/longer)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := range targetHeight {
+		y0, y1 := bounds.Min.Y+y*height/targetHeight, bounds.Min.Y+(y+1)*height/targetHeight
+		for x := range targetWidth {
+			x0, x1 := bounds.Min.X+x*width/targetWidth, bounds.Min.X+(x+1)*width/targetWidth
+
+			var r, g, b, alpha, count uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA() // alpha-premultiplied
+					r, g, b, alpha, count = r+uint64(pr), g+uint64(pg), b+uint64(pb), alpha+uint64(pa), count+1
+				}
+			}
+
+			background := 0xffff - alpha/count
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r/count + background), G: uint16(g/count + background), B: uint16(b/count + background), A: 0xffff,
+			})
+		}
+	}
+
+	return dst
+}
+
+// ownAttachments makes sure the attachments of the poll and its options exist and are uploaded by its owner
+func (p *pools) ownAttachments(ctx context.Context, poll *entities.Poll) error {
+	ids := make([]int64, 0)
+	if poll.Attachment != nil {
+		ids = append(ids, int64(poll.Attachment.ID))
+	}
+	for _, option := range poll.Options {
+		if option.Attachment != nil {
+			ids = append(ids, int64(option.Attachment.ID))
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	storageAttachments, err := p.pollsStorage.GetAttachmentsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	owned := make(map[int64]bool, len(storageAttachments))
+	for _, storageAttachment := range storageAttachments {
+		owned[storageAttachment.ID] = storageAttachment.UserID == int64(poll.UserID)
+	}
+
+	for _, id := range ids {
+		if !owned[id] {
+			return ErrInvalidCreatePollArguments
+		}
+	}
+
+	return nil
+}
+
+func storageAttachmentID(attachment *entities.Attachment) sql.NullInt64 {
+	if attachment == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Valid: true, Int64: int64(attachment.ID)}
+}
+
+// attachmentEntity converts the storage attachment, nil stays nil
+func attachmentEntity(storageAttachment *storage.Attachment) *entities.Attachment {
+	if storageAttachment == nil {
+		return nil
+	}
+
+	return &entities.Attachment{
+		ID:           entities.AttachmentID(storageAttachment.ID),
+		UserID:       entities.UserID(storageAttachment.UserID),
+		Key:          storageAttachment.Key,
+		ThumbnailKey: storageAttachment.ThumbnailKey.String,
+		ContentType:  storageAttachment.ContentType,
+		Size:         storageAttachment.Size,
+		Width:        storageAttachment.Width,
+		Height:       storageAttachment.Height,
+		CreatedAt:    storageAttachment.CreatedAt,
+	}
+}
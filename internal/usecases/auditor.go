@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"github.com/mohammadne/porsesh/pkg/requestid"
+	"go.uber.org/zap"
+)
+
+// Auditor keeps track of every state-changing operation, it should be called
+// with the transaction of the change (if there is one) so both land together
+type Auditor interface {
+	Record(ctx context.Context, tx *sqlx.Tx, event *entities.AuditEvent) error
+	Events(ctx context.Context, filter *entities.AuditFilter, page, limit int) ([]entities.AuditEvent, error)
+}
+
+func NewAuditor(logger *zap.Logger, as storage.AuditEvents) Auditor {
+	return &auditor{
+		logger:             logger,
+		auditEventsStorage: as,
+	}
+}
+
+type auditor struct {
+	logger *zap.Logger
+	// storages
+	auditEventsStorage storage.AuditEvents
+}
+
+var (
+	ErrInvalidAuditEventArguments = errors.New("")
+)
+
+func (a *auditor) Record(ctx context.Context, tx *sqlx.Tx, event *entities.AuditEvent) (err error) {
+	ctx, span := tracer.Start(ctx, "auditor.Record")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if len(event.Action) == 0 || len(event.TargetType) == 0 {
+			return ErrInvalidAuditEventArguments
+		}
+	}
+
+	requestID := event.RequestID
+	if len(requestID) == 0 {
+		requestID = requestid.FromContext(ctx)
+	}
+
+	var metadata []byte
+	if len(event.Metadata) != 0 {
+		var err error
+		if metadata, err = json.Marshal(event.Metadata); err != nil {
+			return errors.Join(ErrInvalidAuditEventArguments, err)
+		}
+	}
+
+	return a.auditEventsStorage.CreateAuditEvent(ctx, tx, &storage.AuditEvent{
+		ActorID:    int64(event.ActorID),
+		Action:     string(event.Action),
+		TargetType: string(event.TargetType),
+		TargetID:   event.TargetID,
+		RequestID:  requestID,
+		Metadata:   metadata,
+	})
+}
+
+var (
+	ErrInvalidAuditFilterArguments = errors.New("")
+)
+
+func (a *auditor) Events(ctx context.Context, filter *entities.AuditFilter, page, limit int) (result []entities.AuditEvent, err error) {
+	ctx, span := tracer.Start(ctx, "auditor.Events")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if limit < 1 {
+			limit = 50
+		} else if limit > 1000 {
+			limit = 1000
+		}
+
+		if page < 1 {
+			page = 1
+		}
+
+		if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+			return nil, ErrInvalidAuditFilterArguments
+		}
+	}
+
+	storageFilter := storage.AuditEventFilter{
+		ActorID:    int64(filter.ActorID),
+		Action:     string(filter.Action),
+		TargetType: string(filter.TargetType),
+		TargetID:   filter.TargetID,
+		RequestID:  filter.RequestID,
+		From:       sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		To:         sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+
+		BeforeCreatedAt: sql.NullTime{Time: filter.BeforeCreatedAt, Valid: !filter.BeforeCreatedAt.IsZero()},
+		BeforeID:        filter.BeforeID,
+	}
+
+	storageEvents, err := a.auditEventsStorage.ListAuditEvents(ctx, &storageFilter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result = make([]entities.AuditEvent, 0, len(storageEvents))
+	for _, storageEvent := range storageEvents {
+		event := entities.AuditEvent{
+			ID:         storageEvent.ID,
+			ActorID:    entities.UserID(storageEvent.ActorID),
+			Action:     entities.AuditAction(storageEvent.Action),
+			TargetType: entities.AuditTarget(storageEvent.TargetType),
+			TargetID:   storageEvent.TargetID,
+			RequestID:  storageEvent.RequestID,
+			CreatedAt:  storageEvent.CreatedAt,
+		}
+		if err := json.Unmarshal(storageEvent.Metadata, &event.Metadata); err != nil {
+			logger.FromContext(ctx, a.logger).Error("error decoding audit event metadata", zap.Int64("id", storageEvent.ID), zap.Error(err))
+		}
+		result = append(result, event)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,366 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"go.uber.org/zap"
+)
+
+// voterKey is the secret of the user on the anonymous polls, the HMAC of the user id by the server-side
+// pepper. Only the holders of the pepper can derive it, so the voter hashes can't be reversed from the database.
+func voterKey(pepper []byte, u entities.UserID) string {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(strconv.FormatInt(int64(u), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// voterHash identifies the voter of an anonymous poll for deduplication, it must be kept
+// in sync with the hash computed by the feed queries in storage
+func voterHash(key string, pollID int64) string {
+	sum := sha256.Sum256([]byte(key + ":" + strconv.FormatInt(pollID, 10)))
+	return hex.EncodeToString(sum[:])
+}
+
+// castVote stores the vote (or the skip) within the transaction, anonymous polls keep the voter
+// apart from the chosen option which is tallied by tallyAnonymous once the transaction is committed
+func (p *pools) castVote(ctx context.Context, tx *sqlx.Tx, storagePoll *storage.Poll, vote *storage.Vote) error {
+	if storagePoll.Anonymous {
+		return p.votesStorage.CreateAnonymousBallot(ctx, tx, &storage.AnonymousBallot{
+			PollID:    storagePoll.ID,
+			UserID:    vote.UserID,
+			VoterHash: voterHash(voterKey(p.voterPepper, entities.UserID(vote.UserID)), storagePoll.ID),
+		})
+	}
+
+	return p.votesStorage.CreateVote(ctx, tx, vote)
+}
+
+const (
+	anonymousTallyAttempts = 3
+	anonymousTallyBackoff  = 100 * time.Millisecond // grows by the attempts
+)
+
+// tallyAnonymous counts the chosen option of the anonymous poll after the ballot is committed, in a
+// transaction of its own so the rows can't be linked by their transaction ids. It's retried as the ballot
+// is already cast by then, the error is returned once all of the attempts have failed so the caller
+// revokes the ballot by revokeAnonymous rather than losing the vote.
+func (p *pools) tallyAnonymous(ctx context.Context, storagePoll *storage.Poll, choice *ballotChoice) (err error) {
+	if !storagePoll.Anonymous || choice == nil || choice.option == nil {
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx) // the ballot is committed, leaving the request doesn't leave it untallied
+	for attempt := 1; ; attempt++ {
+		err = p.votesStorage.IncrementAnonymousTally(ctx, storagePoll.ID, choice.option.ID)
+		if err == nil || attempt == anonymousTallyAttempts {
+			return err
+		}
+
+		logger.FromContext(ctx, p.logger).Warn("error tallying anonymous ballot, retrying",
+			zap.Int64("poll_id", storagePoll.ID), zap.Int("attempt", attempt), zap.Error(err))
+		time.Sleep(time.Duration(attempt) * anonymousTallyBackoff)
+	}
+}
+
+// revokeAnonymous takes back the anonymous ballots of the polls whose tallies have failed, so the voter can
+// cast them again. The extra revocation (e.g. of the survey response) is done within the same transaction.
+// The failure of the tallies is returned either way, joined by the one of the revocation if it has failed.
+func (p *pools) revokeAnonymous(ctx context.Context, storagePolls []*storage.Poll, u entities.UserID,
+	revoke func(tx *sqlx.Tx) error, tallyErr error) error {
+	ctx = context.WithoutCancel(ctx)
+
+	err := func() error {
+		tx, err := p.votesStorage.StartTransaction(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for _, storagePoll := range storagePolls {
+			err = p.votesStorage.RevokeAnonymousBallot(ctx, tx, &storage.AnonymousBallot{
+				PollID:    storagePoll.ID,
+				UserID:    int64(u),
+				VoterHash: voterHash(voterKey(p.voterPepper, u), storagePoll.ID),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if revoke != nil {
+			if err = revoke(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}()
+	if err != nil { // the ballots are kept without their tallies, the votes are lost
+		logger.FromContext(ctx, p.logger).Error("error revoking the untallied anonymous ballots",
+			zap.Int64("user_id", int64(u)), zap.Error(err))
+		return errors.Join(tallyErr, err)
+	}
+	return tallyErr
+}
+
+var (
+	errInvalidBallot = errors.New("")
+)
+
+// ballotChoice is the ballot resolved against the poll, only one of the option, the value,
+// the free-text answer or the availabilities is set
+type ballotChoice struct {
+	option         *storage.PollOption
+	value          sql.NullInt64
+	other          sql.NullString
+	availabilities []storage.ScheduleAvailability
+}
+
+// resolveBallot checks the ballot of the voter against the type of the poll, errInvalidBallot is returned
+// when it doesn't fit the poll. The option index is in the order the voter has been shown.
+func (p *pools) resolveBallot(ctx context.Context, storagePoll *storage.Poll, ballot *entities.Ballot, u entities.UserID) (*ballotChoice, error) {
+	if len(ballot.Other) != 0 {
+		text := strings.TrimSpace(ballot.Other)
+		if storagePoll.OtherMaxLength == 0 || len(text) == 0 || utf8.RuneCountInString(text) > storagePoll.OtherMaxLength {
+			return nil, errInvalidBallot
+		}
+		return &ballotChoice{other: sql.NullString{Valid: true, String: text}}, nil
+	}
+
+	if entities.PollType(storagePoll.Type) == entities.PollTypeScale {
+		scale, err := p.pollScale(ctx, storagePoll.ID)
+		if err != nil {
+			return nil, err
+		} else if scale == nil || ballot.Value == nil || !scale.Contains(*ballot.Value) {
+			return nil, errInvalidBallot // zero may be on the scale, so the missing value isn't taken as it
+		}
+		return &ballotChoice{value: sql.NullInt64{Valid: true, Int64: int64(*ballot.Value)}}, nil
+	}
+
+	if ballot.OptionID != 0 { // the option is validated to belong to the poll by the same lookup
+		if entities.PollType(storagePoll.Type) == entities.PollTypeSchedule {
+			return nil, errInvalidBallot
+		}
+
+		option, err := p.pollsStorage.GetPollOption(ctx, storagePoll.ID, int64(ballot.OptionID))
+		if err != nil {
+			return nil, err
+		} else if option == nil {
+			return nil, errInvalidBallot
+		}
+		return &ballotChoice{option: option}, nil
+	}
+
+	storageOptions, err := p.sortedOptions(ctx, entities.PollID(storagePoll.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	if entities.PollType(storagePoll.Type) == entities.PollTypeSchedule {
+		availabilities, err := resolveAvailability(storageOptions, ballot.Availability)
+		if err != nil {
+			return nil, err
+		}
+		return &ballotChoice{availabilities: availabilities}, nil
+	}
+
+	if ballot.Option == nil || *ballot.Option < 0 || *ballot.Option >= len(storageOptions) {
+		return nil, errInvalidBallot // the first option is never taken for the missing one
+	}
+
+	shuffleOptions(storagePoll, storageOptions, u)
+
+	return &ballotChoice{option: &storageOptions[*ballot.Option]}, nil
+}
+
+// vote casts the resolved ballot on the poll within the transaction and audits it,
+// the quizzes are scored by the time of acting
+func (p *pools) vote(ctx context.Context, tx *sqlx.Tx, storagePoll *storage.Poll, choice *ballotChoice,
+	u entities.UserID, actedAt time.Time) (*entities.VoteResult, error) {
+	result := &entities.VoteResult{}
+	storageVote := storage.Vote{
+		UserID:  int64(u),
+		PollID:  storagePoll.ID,
+		Value:   choice.value,
+		ActedAt: actedAt,
+	}
+
+	metadata := map[string]any{"value": choice.value.Int64}
+	if choice.option != nil {
+		storageVote.OptionID = sql.NullInt64{Valid: true, Int64: choice.option.ID}
+		metadata = map[string]any{"option_id": choice.option.ID}
+	} else if choice.other.Valid { // the text itself is kept out of the audit trail
+		storageVote.OtherText, storageVote.OtherHidden = choice.other, p.moderate(ctx, storagePoll.ID, choice.other.String)
+		metadata = map[string]any{"other": true, "hidden": storageVote.OtherHidden}
+	} else if len(choice.availabilities) != 0 {
+		metadata = map[string]any{"availabilities": len(choice.availabilities)}
+	}
+
+	if poll := pollEntity(storagePoll); poll.Type == entities.PollTypeQuiz {
+		result.Quiz, result.Correct = true, choice.option.Correct
+		result.Points = quizPoints(&poll, actedAt, result.Correct)
+		storageVote.Correct = sql.NullBool{Valid: true, Bool: result.Correct}
+		storageVote.Points = result.Points
+	}
+
+	if err := p.castVote(ctx, tx, storagePoll, &storageVote); err != nil {
+		return nil, err
+	}
+
+	if len(choice.availabilities) != 0 { // after the vote, a second ballot fails on it as already acted
+		for index := range choice.availabilities {
+			choice.availabilities[index].PollID, choice.availabilities[index].UserID = storagePoll.ID, int64(u)
+		}
+		if err := p.votesStorage.CreateScheduleAvailabilities(ctx, tx, choice.availabilities); err != nil {
+			return nil, err
+		}
+	}
+
+	if storagePoll.Anonymous { // the audit trail must not link the voter to the choice either
+		metadata = map[string]any{"anonymous": true}
+	}
+
+	err := p.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    u,
+		Action:     entities.AuditActionVotePoll,
+		TargetType: entities.AuditTargetPoll,
+		TargetID:   storagePoll.ID,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// skip casts the skip of the poll within the transaction and audits it
+func (p *pools) skip(ctx context.Context, tx *sqlx.Tx, storagePoll *storage.Poll, u entities.UserID) error {
+	storageVote := storage.Vote{UserID: int64(u), PollID: storagePoll.ID, Skipped: true}
+	if err := p.castVote(ctx, tx, storagePoll, &storageVote); err != nil {
+		return err
+	}
+
+	return p.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    u,
+		Action:     entities.AuditActionSkipPoll,
+		TargetType: entities.AuditTargetPoll,
+		TargetID:   storagePoll.ID,
+	})
+}
+
+var (
+	ErrInvalidVotersArguments = errors.New("")
+	ErrVotersPollNotExists    = errors.New("")
+	ErrVotersPollAnonymous    = errors.New("")
+)
+
+// Voters lists who has voted for what on the attributed poll, it's filtered by the (optional) option
+// given either by its ID or by its index which is deprecated
+func (p *pools) Voters(ctx context.Context, pollID entities.PollID, owner entities.UserID,
+	optionID entities.PollOptionID, option *int, page, limit int) (result []entities.Voter, err error) {
+	ctx, span := tracer.Start(ctx, "pools.Voters")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if pollID < 0 || optionID < 0 || (option != nil && *option < 0) {
+			return nil, ErrInvalidVotersArguments
+		}
+
+		if limit < 1 {
+			limit = 20
+		} else if limit > 100 {
+			limit = 100
+		}
+
+		if page < 1 {
+			page = 1
+		}
+	}
+
+	storagePoll, err := p.ownedPoll(ctx, pollID, owner)
+	if err != nil {
+		return nil, err
+	} else if storagePoll == nil {
+		return nil, ErrVotersPollNotExists
+	} else if storagePoll.Anonymous {
+		return nil, ErrVotersPollAnonymous
+	}
+
+	storageOptions, err := p.sortedOptions(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter int64
+	if optionID != 0 {
+		if !slices.ContainsFunc(storageOptions, func(so storage.PollOption) bool { return so.ID == int64(optionID) }) {
+			return nil, ErrInvalidVotersArguments
+		}
+		filter = int64(optionID)
+	} else if option != nil {
+		if *option > len(storageOptions)-1 {
+			return nil, ErrInvalidVotersArguments
+		}
+		filter = storageOptions[*option].ID
+	}
+
+	storageVotes, err := p.votesStorage.ListPollVoters(ctx, int64(pollID), filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make(map[int64]string, len(storageOptions))
+	for _, so := range storageOptions {
+		contents[so.ID] = so.Content
+	}
+
+	result = make([]entities.Voter, 0, len(storageVotes))
+	for _, storageVote := range storageVotes {
+		result = append(result, entities.Voter{
+			UserID:   entities.UserID(storageVote.UserID),
+			OptionID: entities.PollOptionID(storageVote.OptionID.Int64),
+			Option:   voterChoice(contents, &storageVote),
+			Other:    storageVote.OtherText.String,
+			ActedAt:  storageVote.ActedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// voterChoice is the content of the chosen option or the value on the scale polls
+func voterChoice(contents map[int64]string, storageVote *storage.Vote) string {
+	if storageVote.Value.Valid {
+		return strconv.FormatInt(storageVote.Value.Int64, 10)
+	}
+	return contents[storageVote.OptionID.Int64]
+}
+
+// sortedOptions retrieves the options of the poll in the order their indexes refer to
+func (p *pools) sortedOptions(ctx context.Context, pollID entities.PollID) ([]storage.PollOption, error) {
+	storageOptions, err := p.pollsStorage.GetPollOptionsByPollID(ctx, int64(pollID))
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(storageOptions, func(a, b storage.PollOption) int {
+		return a.Sort - b.Sort
+	})
+
+	return storageOptions, nil
+}
@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Config struct {
+	// VoterPepper keys the voter hashes of the anonymous polls, it's kept out of the database so the
+	// hashes can't be reversed by looping over the user ids. Changing it forgets who has voted.
+	VoterPepper string `split_words:"true" required:"true" secret:"true"`
+	// FeedMetricTags are the tags the feed metrics are labeled by, the feeds of the other tags are
+	// counted under "other" so the number of the series stays bounded
+	FeedMetricTags []string `split_words:"true"`
+}
+
+const (
+	minVoterPepperLength = 32
+	maxFeedMetricTags    = 50
+)
+
+func (cfg *Config) Validate() error {
+	var errs []error
+	if len(cfg.VoterPepper) < minVoterPepperLength {
+		errs = append(errs, fmt.Errorf("voter pepper is shorter than %d characters", minVoterPepperLength))
+	}
+	if len(cfg.FeedMetricTags) > maxFeedMetricTags {
+		errs = append(errs, fmt.Errorf("feed metric tags are more than %d", maxFeedMetricTags))
+	}
+	for _, tag := range cfg.FeedMetricTags {
+		if len(tag) == 0 || tag == feedUntagged || tag == feedOtherTag {
+			errs = append(errs, fmt.Errorf("feed metric tag %q is reserved or empty", tag))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -2,29 +2,60 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/mohammadne/porsesh/internal/entities"
 	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
 	"go.uber.org/zap"
 )
 
 type Feeds interface {
-	GetUserFeed(ctx context.Context, userID entities.UserID, tag string, page, limit int) (entities.Feed, error)
+	GetUserFeed(ctx context.Context, userID entities.UserID, tag, search string, page, limit int) (entities.Feed, error)
 }
 
-func NewFeeds(logger *zap.Logger) Feeds {
-	return &feeds{logger: logger}
+func NewFeeds(cfg *Config, logger *zap.Logger, metrics *Metrics, ps storage.Polls, ts storage.Tags, vs storage.Votes) Feeds {
+	metricTags := make(map[string]bool, len(cfg.FeedMetricTags))
+	for _, tag := range cfg.FeedMetricTags {
+		metricTags[tag] = true
+	}
+
+	return &feeds{
+		voterPepper:  []byte(cfg.VoterPepper),
+		metricTags:   metricTags,
+		logger:       logger,
+		metrics:      metrics,
+		pollsStorage: ps,
+		tagsStorage:  ts,
+		votesStorage: vs,
+	}
 }
 
 type feeds struct {
-	logger *zap.Logger
+	voterPepper []byte          // leaves out the anonymous polls the user has acted on
+	metricTags  map[string]bool // the tags the feed metrics are labeled by, the others are grouped
+	logger      *zap.Logger
+	metrics     *Metrics
 	// storages
 	pollsStorage storage.Polls
 	tagsStorage  storage.Tags
 	votesStorage storage.Votes
 }
 
-func (f *feeds) GetUserFeed(ctx context.Context, userID entities.UserID, tag string, page, limit int) (entities.Feed, error) {
+var (
+	ErrInvalidFeedArguments = errors.New("")
+)
+
+const maxFeedSearchRunes = 100
+
+func (f *feeds) GetUserFeed(ctx context.Context, userID entities.UserID, tag, search string, page, limit int) (result entities.Feed, err error) {
+	ctx, span := tracer.Start(ctx, "feeds.GetUserFeed")
+	defer func() { tracing.End(span, err) }()
+
 	{ // validation
 		if limit < 5 {
 			limit = 5
@@ -35,34 +66,40 @@ func (f *feeds) GetUserFeed(ctx context.Context, userID entities.UserID, tag str
 		if page < 1 {
 			page = 1
 		}
+
+		if search = strings.TrimSpace(search); utf8.RuneCountInString(search) > maxFeedSearchRunes {
+			return nil, ErrInvalidFeedArguments
+		}
 	}
 
 	var tagID int64
+	tagLabel := feedUntagged
 	if len(tag) != 0 {
-		result, err := f.tagsStorage.GetTagByName(ctx, tag)
+		tagLabel = feedOtherTag
+		if f.metricTags[tag] {
+			tagLabel = tag
+		}
+
+		storageTag, err := f.tagsStorage.GetTagByName(ctx, tag)
 		if err != nil {
-			f.logger.Error("error retrieving tag", zap.Error(err))
-		} else if result != nil {
-			tagID = result.ID
+			logger.FromContext(ctx, f.logger).Error("error retrieving tag", zap.Error(err))
+		} else if storageTag != nil {
+			tagID = storageTag.ID
 		}
 	}
 
-	storagePolls, err := f.pollsStorage.ListPollsByTag(ctx, int64(userID), tagID, page, limit)
+	storagePolls, err := f.pollsStorage.ListPollsByTag(ctx, int64(userID), tagID, voterKey(f.voterPepper, userID), search, limit, (page-1)*limit)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]entities.Poll, 0, len(storagePolls))
-	for _, storagePoll := range storagePolls {
-		result = append(result, entities.Poll{
-			ID:        entities.PollID(storagePoll.ID),
-			Title:     storagePoll.Title,
-			UserID:    userID,
-			CreatedAt: storagePoll.CreatedAt,
-			Options:   nil, // TODO: implement
-			Tags:      nil, // TODO: implement
-		})
+	result, err = hydratePolls(ctx, f.pollsStorage, storagePolls, userID)
+	if err != nil {
+		return nil, err
 	}
 
+	f.metrics.Feeds.IncrementVectorContext(ctx, tagLabel, strconv.FormatBool(len(result) == 0))
+	f.metrics.FeedSizes.ObserveVectorContext(ctx, float64(len(result)), tagLabel)
+
 	return result, nil
 }
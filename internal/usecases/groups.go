@@ -0,0 +1,204 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"go.uber.org/zap"
+)
+
+// Groups keeps the named lists of users, the owners invite them to their private polls at once
+// and the later changes of the members apply to those polls as well
+type Groups interface {
+	CreateGroup(context.Context, *entities.Group) error
+	AddMembers(ctx context.Context, g entities.GroupID, owner entities.UserID, members []entities.UserID) error
+	RemoveMembers(ctx context.Context, g entities.GroupID, owner entities.UserID, members []entities.UserID) error
+	Members(ctx context.Context, g entities.GroupID, owner entities.UserID) ([]entities.UserID, error)
+}
+
+func NewGroups(logger *zap.Logger, auditor Auditor, gs storage.Groups) Groups {
+	return &groups{
+		logger:        logger,
+		auditor:       auditor,
+		groupsStorage: gs,
+	}
+}
+
+type groups struct {
+	logger  *zap.Logger
+	auditor Auditor
+	// storages
+	groupsStorage storage.Groups
+}
+
+var (
+	ErrInvalidCreateGroupArguments = errors.New("")
+	ErrCreateGroupAlreadyExists    = errors.New("")
+)
+
+const (
+	maxGroupNameLength    = 64
+	maxGroupMembersChange = 100
+)
+
+func (g *groups) CreateGroup(ctx context.Context, group *entities.Group) (err error) {
+	ctx, span := tracer.Start(ctx, "groups.CreateGroup")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		group.Name = strings.TrimSpace(group.Name)
+		if group.OwnerID <= 0 || len(group.Name) == 0 || len(group.Name) > maxGroupNameLength ||
+			len(group.Members) > maxGroupMembersChange {
+			return ErrInvalidCreateGroupArguments
+		}
+	}
+
+	tx, err := g.groupsStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	groupID, err := g.groupsStorage.CreateGroup(ctx, tx, &storage.Group{
+		OwnerID: int64(group.OwnerID),
+		Name:    group.Name,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrCreateGroupAlreadyExists) {
+			return ErrCreateGroupAlreadyExists
+		}
+		return err
+	}
+
+	if len(group.Members) != 0 {
+		err = g.groupsStorage.AddGroupMembers(ctx, tx, groupID, userIDs(group.Members))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = g.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    group.OwnerID,
+		Action:     entities.AuditActionCreateGroup,
+		TargetType: entities.AuditTargetGroup,
+		TargetID:   groupID,
+		Metadata:   map[string]any{"name": group.Name, "members": len(group.Members)},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	group.ID = entities.GroupID(groupID)
+	return nil
+}
+
+var (
+	ErrInvalidGroupMembersArguments = errors.New("")
+	ErrGroupMembersGroupNotExists   = errors.New("")
+)
+
+func (g *groups) AddMembers(ctx context.Context, groupID entities.GroupID, owner entities.UserID, members []entities.UserID) (err error) {
+	ctx, span := tracer.Start(ctx, "groups.AddMembers")
+	defer func() { tracing.End(span, err) }()
+
+	return g.changeMembers(ctx, groupID, owner, members, entities.AuditActionAddGroupMembers)
+}
+
+func (g *groups) RemoveMembers(ctx context.Context, groupID entities.GroupID, owner entities.UserID, members []entities.UserID) (err error) {
+	ctx, span := tracer.Start(ctx, "groups.RemoveMembers")
+	defer func() { tracing.End(span, err) }()
+
+	return g.changeMembers(ctx, groupID, owner, members, entities.AuditActionRemoveGroupMembers)
+}
+
+func (g *groups) changeMembers(ctx context.Context, groupID entities.GroupID, owner entities.UserID,
+	members []entities.UserID, action entities.AuditAction) error {
+	{ // validation
+		if groupID <= 0 || len(members) == 0 || len(members) > maxGroupMembersChange {
+			return ErrInvalidGroupMembersArguments
+		}
+	}
+
+	if owned, err := g.ownedGroup(ctx, groupID, owner); err != nil {
+		return err
+	} else if !owned {
+		return ErrGroupMembersGroupNotExists
+	}
+
+	tx, err := g.groupsStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if action == entities.AuditActionAddGroupMembers {
+		err = g.groupsStorage.AddGroupMembers(ctx, tx, int64(groupID), userIDs(members))
+	} else {
+		err = g.groupsStorage.RemoveGroupMembers(ctx, tx, int64(groupID), userIDs(members))
+	}
+	if err != nil {
+		return err
+	}
+
+	err = g.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    owner,
+		Action:     action,
+		TargetType: entities.AuditTargetGroup,
+		TargetID:   int64(groupID),
+		Metadata:   map[string]any{"members": userIDs(members)},
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (g *groups) Members(ctx context.Context, groupID entities.GroupID, owner entities.UserID) (result []entities.UserID, err error) {
+	ctx, span := tracer.Start(ctx, "groups.Members")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if groupID <= 0 {
+			return nil, ErrInvalidGroupMembersArguments
+		}
+	}
+
+	if owned, err := g.ownedGroup(ctx, groupID, owner); err != nil {
+		return nil, err
+	} else if !owned {
+		return nil, ErrGroupMembersGroupNotExists
+	}
+
+	storageMembers, err := g.groupsStorage.ListGroupMembers(ctx, int64(groupID))
+	if err != nil {
+		return nil, err
+	}
+
+	result = make([]entities.UserID, 0, len(storageMembers))
+	for _, member := range storageMembers {
+		result = append(result, entities.UserID(member))
+	}
+
+	return result, nil
+}
+
+// ownedGroup tells whether the group exists and belongs to the owner, the groups of the others
+// are treated as missing so their existence isn't revealed
+func (g *groups) ownedGroup(ctx context.Context, groupID entities.GroupID, owner entities.UserID) (bool, error) {
+	storageGroups, err := g.groupsStorage.GetGroupsByIDs(ctx, []int64{int64(groupID)})
+	if err != nil {
+		return false, err
+	}
+
+	return len(storageGroups) == 1 && storageGroups[0].OwnerID == int64(owner), nil
+}
@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+)
+
+// hydratePolls converts the storage polls into entities along with their sorted options, scales, attachments and tags,
+// every relation is retrieved by a single query regardless of the number of polls. The options are in the order of the viewer.
+func hydratePolls(ctx context.Context, ps storage.Polls, storagePolls []storage.Poll, viewer entities.UserID) ([]entities.Poll, error) {
+	result := make([]entities.Poll, 0, len(storagePolls))
+	if len(storagePolls) == 0 {
+		return result, nil
+	}
+
+	pollIDs := make([]int64, 0, len(storagePolls))
+	for _, storagePoll := range storagePolls {
+		pollIDs = append(pollIDs, storagePoll.ID)
+	}
+
+	storageOptions, err := ps.GetPollOptionsByPollIDs(ctx, pollIDs)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(storageOptions, func(a, b storage.PollOption) int {
+		return a.Sort - b.Sort
+	})
+
+	storageTags, err := ps.GetPollTagsByPollIDs(ctx, pollIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	scalePollIDs := make([]int64, 0)
+	for _, storagePoll := range storagePolls {
+		if entities.PollType(storagePoll.Type) == entities.PollTypeScale {
+			scalePollIDs = append(scalePollIDs, storagePoll.ID)
+		}
+	}
+
+	scales := make(map[int64]*entities.PollScale, len(scalePollIDs))
+	if len(scalePollIDs) != 0 {
+		storageScales, err := ps.GetPollScalesByPollIDs(ctx, scalePollIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, storageScale := range storageScales {
+			scales[storageScale.PollID] = scaleEntity(&storageScale)
+		}
+	}
+
+	attachmentIDs := make([]int64, 0)
+	for _, storagePoll := range storagePolls {
+		if storagePoll.AttachmentID.Valid {
+			attachmentIDs = append(attachmentIDs, storagePoll.AttachmentID.Int64)
+		}
+	}
+	for _, so := range storageOptions {
+		if so.AttachmentID.Valid {
+			attachmentIDs = append(attachmentIDs, so.AttachmentID.Int64)
+		}
+	}
+
+	attachments := make(map[int64]*entities.Attachment, len(attachmentIDs))
+	if len(attachmentIDs) != 0 {
+		storageAttachments, err := ps.GetAttachmentsByIDs(ctx, attachmentIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, storageAttachment := range storageAttachments {
+			attachments[storageAttachment.ID] = attachmentEntity(&storageAttachment)
+		}
+	}
+
+	// the answers of the quizzes are revealed only once they are closed
+	revealed := make(map[int64]bool, len(storagePolls))
+	now := time.Now()
+	for _, storagePoll := range storagePolls {
+		poll := pollEntity(&storagePoll)
+		revealed[storagePoll.ID] = poll.Type == entities.PollTypeQuiz && poll.IsClosed(now)
+	}
+
+	grouped := make(map[int64][]storage.PollOption, len(storagePolls))
+	for _, so := range storageOptions {
+		grouped[so.PollID] = append(grouped[so.PollID], so)
+	}
+
+	options := make(map[int64][]entities.PollOption, len(storagePolls))
+	for _, storagePoll := range storagePolls {
+		shuffleOptions(&storagePoll, grouped[storagePoll.ID], viewer)
+		for _, so := range grouped[storagePoll.ID] {
+			options[so.PollID] = append(options[so.PollID], entities.PollOption{
+				ID:         entities.PollOptionID(so.ID),
+				Content:    so.Content,
+				Sort:       so.Sort,
+				Correct:    so.Correct && revealed[so.PollID],
+				Slot:       slotEntity(&so),
+				Attachment: attachments[so.AttachmentID.Int64],
+			})
+		}
+	}
+
+	tags := make(map[int64][]entities.PollTag, len(storagePolls))
+	for _, st := range storageTags {
+		tags[st.PollID] = append(tags[st.PollID], entities.PollTag{Name: st.Name})
+	}
+
+	for _, storagePoll := range storagePolls {
+		poll := pollEntity(&storagePoll)
+		poll.Attachment = attachments[storagePoll.AttachmentID.Int64]
+		poll.Options = options[storagePoll.ID]
+		poll.Scale = scales[storagePoll.ID]
+		poll.Tags = tags[storagePoll.ID]
+		result = append(result, poll)
+	}
+
+	return result, nil
+}
+
+// pollEntity converts the storage poll into the entity without any of its relations
+func pollEntity(storagePoll *storage.Poll) entities.Poll {
+	status := entities.PollStatusPublished
+	if !storagePoll.PublishedAt.Valid {
+		status = entities.PollStatusDraft
+		if storagePoll.PublishAt.Valid {
+			status = entities.PollStatusScheduled
+		}
+	}
+
+	return entities.Poll{
+		ID:                entities.PollID(storagePoll.ID),
+		Slug:              storagePoll.Slug,
+		Type:              entities.PollType(storagePoll.Type),
+		Status:            status,
+		Title:             storagePoll.Title,
+		Description:       storagePoll.Description,
+		DescriptionHTML:   storagePoll.DescriptionHTML,
+		UserID:            entities.UserID(storagePoll.UserID),
+		Visibility:        entities.PollVisibility(storagePoll.Visibility),
+		ResultsVisibility: entities.ResultsVisibility(storagePoll.ResultsVisibility),
+		Anonymous:         storagePoll.Anonymous,
+		TimeLimit:         time.Duration(storagePoll.TimeLimitSeconds) * time.Second,
+		QuizSet:           storagePoll.QuizSet,
+		OtherMaxLength:    storagePoll.OtherMaxLength,
+		ShuffleOptions:    storagePoll.ShuffleOptions,
+		ClosesAt:          storagePoll.ClosesAt.Time, // zero when NULL
+		PublishAt:         storagePoll.PublishAt.Time,
+		PublishedAt:       storagePoll.PublishedAt.Time,
+		CreatedAt:         storagePoll.CreatedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+)
+
+// Metrics are the product-level metrics of the usecases, unlike the storage ones they tell how the polls
+// are used, e.g. the dashboards alert on them when the voting drops off
+type Metrics struct {
+	PollsCreated    metrics.Counter   // by the type, the option count and the tag count
+	Ballots         metrics.Counter   // the votes and the skips by the poll type, cast on their own or within surveys
+	LimitRejections metrics.Counter   // the ballots rejected by the daily limit, by where they were cast
+	Feeds           metrics.Counter   // by the tag and whether the feed was empty
+	FeedSizes       metrics.Histogram // the number of the polls in the feeds, by the tag
+}
+
+const (
+	ballotVote = "vote"
+	ballotSkip = "skip"
+
+	sourcePoll   = "poll"
+	sourceSurvey = "survey"
+
+	feedUntagged = "all"   // the feed isn't filtered by any tag
+	feedOtherTag = "other" // the tag is out of the configured ones, its name is left out to bound the label values
+)
+
+var feedSizeBuckets = []float64{0, 1, 2, 5, 10, 15, 20}
+
+func NewMetrics(registry *metrics.Registry, namespace, subsystem string) (*Metrics, error) {
+	var err error
+	result := &Metrics{}
+
+	result.PollsCreated, err = registry.RegisterCounter("polls_created", namespace, subsystem, []string{"type", "options", "tags"})
+	if err != nil {
+		return nil, fmt.Errorf("error registering the polls created metric: %v", err)
+	}
+
+	result.Ballots, err = registry.RegisterCounter("ballots", namespace, subsystem, []string{"type", "ballot", "source"})
+	if err != nil {
+		return nil, fmt.Errorf("error registering the ballots metric: %v", err)
+	}
+
+	result.LimitRejections, err = registry.RegisterCounter("daily_limit_rejections", namespace, subsystem, []string{"source"})
+	if err != nil {
+		return nil, fmt.Errorf("error registering the daily limit rejections metric: %v", err)
+	}
+
+	result.Feeds, err = registry.RegisterCounter("feeds", namespace, subsystem, []string{"tag", "empty"})
+	if err != nil {
+		return nil, fmt.Errorf("error registering the feeds metric: %v", err)
+	}
+
+	result.FeedSizes, err = registry.RegisterHistogram("feed_sizes", namespace, subsystem, []string{"tag"},
+		metrics.WithBuckets(feedSizeBuckets...))
+	if err != nil {
+		return nil, fmt.Errorf("error registering the feed sizes metric: %v", err)
+	}
+
+	return result, nil
+}
+
+// NoopMetrics records nothing, e.g. for the tests
+func NoopMetrics() *Metrics {
+	return &Metrics{
+		PollsCreated:    metrics.RegisterCounterNoop(),
+		Ballots:         metrics.RegisterCounterNoop(),
+		LimitRejections: metrics.RegisterCounterNoop(),
+		Feeds:           metrics.RegisterCounterNoop(),
+		FeedSizes:       metrics.RegisterHistogramNoop(),
+	}
+}
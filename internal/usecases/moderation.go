@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+)
+
+// Moderator is the hook deciding whether a free-text answer should be hidden,
+// it's called on every submitted answer before it's stored
+type Moderator interface {
+	Moderate(ctx context.Context, pollID entities.PollID, text string) (hide bool, err error)
+}
+
+// ModeratorFunc adapts an ordinary function into the moderator
+type ModeratorFunc func(ctx context.Context, pollID entities.PollID, text string) (bool, error)
+
+func (f ModeratorFunc) Moderate(ctx context.Context, pollID entities.PollID, text string) (bool, error) {
+	return f(ctx, pollID, text)
+}
+
+// Moderators chains the hooks, the text is hidden as soon as any of them hides it
+// and nothing is hidden without any hook
+func Moderators(moderators ...Moderator) Moderator {
+	return ModeratorFunc(func(ctx context.Context, pollID entities.PollID, text string) (bool, error) {
+		for _, moderator := range moderators {
+			if hide, err := moderator.Moderate(ctx, pollID, text); err != nil || hide {
+				return hide, err
+			}
+		}
+		return false, nil
+	})
+}
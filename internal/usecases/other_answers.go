@@ -0,0 +1,250 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"go.uber.org/zap"
+)
+
+const maxOtherAnswerLength = 500
+
+// validOther checks the "other" choice settings, it's only available on the attributed single polls
+func validOther(poll *entities.Poll) bool {
+	if poll.OtherMaxLength == 0 {
+		return true
+	}
+
+	return poll.Type == entities.PollTypeSingle && !poll.Anonymous &&
+		poll.OtherMaxLength > 0 && poll.OtherMaxLength <= maxOtherAnswerLength
+}
+
+// moderate runs the moderation hooks over the free-text answer, it's hidden if they fail
+func (p *pools) moderate(ctx context.Context, pollID int64, text string) bool {
+	hide, err := p.moderator.Moderate(ctx, entities.PollID(pollID), text)
+	if err != nil {
+		logger.FromContext(ctx, p.logger).Error("error moderating the other answer", zap.Int64("poll_id", pollID), zap.Error(err))
+		return true
+	}
+	return hide
+}
+
+var (
+	ErrInvalidOtherAnswersArguments = errors.New("")
+	ErrOtherAnswersPollNotExists    = errors.New("")
+	ErrOtherAnswerNotExists         = errors.New("")
+)
+
+// otherPoll retrieves the poll owned by the user which allows the "other" choice
+func (p *pools) otherPoll(ctx context.Context, pollID entities.PollID, owner entities.UserID) (*storage.Poll, error) {
+	if pollID < 0 {
+		return nil, ErrInvalidOtherAnswersArguments
+	}
+
+	storagePoll, err := p.ownedPoll(ctx, pollID, owner)
+	if err != nil {
+		return nil, err
+	} else if storagePoll == nil {
+		return nil, ErrOtherAnswersPollNotExists
+	} else if storagePoll.OtherMaxLength == 0 {
+		return nil, ErrInvalidOtherAnswersArguments
+	}
+
+	return storagePoll, nil
+}
+
+// OtherAnswers lists the free-text answers (along with the hidden ones) to the owner of the poll
+func (p *pools) OtherAnswers(ctx context.Context, pollID entities.PollID, owner entities.UserID,
+	search string, page, limit int) (result []entities.OtherAnswer, err error) {
+	ctx, span := tracer.Start(ctx, "pools.OtherAnswers")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if len(search) > maxOtherAnswerLength {
+			return nil, ErrInvalidOtherAnswersArguments
+		}
+
+		if limit < 1 {
+			limit = 20
+		} else if limit > 100 {
+			limit = 100
+		}
+
+		if page < 1 {
+			page = 1
+		}
+	}
+
+	if _, err := p.otherPoll(ctx, pollID, owner); err != nil {
+		return nil, err
+	}
+
+	storageVotes, err := p.votesStorage.ListOtherAnswers(ctx, int64(pollID), strings.TrimSpace(search), limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result = make([]entities.OtherAnswer, 0, len(storageVotes))
+	for _, storageVote := range storageVotes {
+		result = append(result, entities.OtherAnswer{
+			UserID:  entities.UserID(storageVote.UserID),
+			Text:    storageVote.OtherText.String,
+			Hidden:  storageVote.OtherHidden,
+			ActedAt: storageVote.ActedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// FrequentOtherAnswers groups the visible free-text answers, the candidates to be promoted
+func (p *pools) FrequentOtherAnswers(ctx context.Context, pollID entities.PollID, owner entities.UserID,
+	limit int) (result []entities.OtherAnswerFrequency, err error) {
+	ctx, span := tracer.Start(ctx, "pools.FrequentOtherAnswers")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if limit < 1 {
+			limit = 10
+		} else if limit > 100 {
+			limit = 100
+		}
+	}
+
+	if _, err := p.otherPoll(ctx, pollID, owner); err != nil {
+		return nil, err
+	}
+
+	storageFrequencies, err := p.votesStorage.GetFrequentOtherAnswers(ctx, int64(pollID), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result = make([]entities.OtherAnswerFrequency, 0, len(storageFrequencies))
+	for _, storageFrequency := range storageFrequencies {
+		result = append(result, entities.OtherAnswerFrequency{Text: storageFrequency.Text, Count: storageFrequency.Count})
+	}
+
+	return result, nil
+}
+
+// HideOtherAnswer lets the owner of the poll hide (or bring back) the free-text answer of the voter
+func (p *pools) HideOtherAnswer(ctx context.Context, pollID entities.PollID, owner, voter entities.UserID, hidden bool) (err error) {
+	ctx, span := tracer.Start(ctx, "pools.HideOtherAnswer")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := p.otherPoll(ctx, pollID, owner); err != nil {
+		return err
+	}
+
+	tx, err := p.votesStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = p.votesStorage.SetOtherAnswerHidden(ctx, tx, int64(pollID), int64(voter), hidden)
+	if err != nil {
+		if errors.Is(err, storage.ErrSetOtherAnswerHiddenNotExists) {
+			return ErrOtherAnswerNotExists
+		}
+		return err
+	}
+
+	action := entities.AuditActionUnhideOtherAnswer
+	if hidden {
+		action = entities.AuditActionHideOtherAnswer
+	}
+
+	err = p.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    owner,
+		Action:     action,
+		TargetType: entities.AuditTargetPoll,
+		TargetID:   int64(pollID),
+		Metadata:   map[string]any{"voter": voter},
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PromoteOtherAnswer turns the free-text answer into a real option of the poll, the visible
+// answers matching the text (regardless of the case) are moved into it and their count is returned
+func (p *pools) PromoteOtherAnswer(ctx context.Context, pollID entities.PollID, owner entities.UserID, text string) (result int64, err error) {
+	ctx, span := tracer.Start(ctx, "pools.PromoteOtherAnswer")
+	defer func() { tracing.End(span, err) }()
+
+	text = strings.TrimSpace(text)
+	{ // validation
+		if len(text) == 0 || utf8.RuneCountInString(text) > maxOtherAnswerLength {
+			return 0, ErrInvalidOtherAnswersArguments
+		}
+	}
+
+	if _, err := p.otherPoll(ctx, pollID, owner); err != nil {
+		return 0, err
+	}
+
+	tx, err := p.pollsStorage.StartTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// the options are read under the lock of the poll, so the concurrent promotions of the
+	// same text can't both pass the duplicate check or take the same sort
+	storageOptions, err := p.pollsStorage.LockPollOptions(ctx, tx, int64(pollID))
+	if err != nil {
+		return 0, err
+	} else if len(storageOptions) >= maxPollOptions {
+		return 0, ErrInvalidOtherAnswersArguments
+	}
+
+	for _, so := range storageOptions {
+		if strings.EqualFold(strings.TrimSpace(so.Content), text) {
+			return 0, ErrInvalidOtherAnswersArguments
+		}
+	}
+
+	sort := 1
+	if len(storageOptions) != 0 {
+		sort = storageOptions[len(storageOptions)-1].Sort + 1
+	}
+
+	optionID, err := p.pollsStorage.CreatePollOption(ctx, tx, &storage.PollOption{
+		PollID: int64(pollID), Content: text, Sort: sort,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	moved, err := p.votesStorage.PromoteOtherAnswer(ctx, tx, int64(pollID), optionID, text)
+	if err != nil {
+		return 0, err
+	}
+
+	err = p.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    owner,
+		Action:     entities.AuditActionPromoteOtherAnswer,
+		TargetType: entities.AuditTargetPoll,
+		TargetID:   int64(pollID),
+		Metadata:   map[string]any{"option_id": optionID, "votes": moved},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+var (
+	ErrInvalidPollParticipantsArguments = errors.New("")
+	ErrPollParticipantsPollNotExists    = errors.New("")
+)
+
+const (
+	maxPollParticipantsChange      = 100
+	maxPollParticipantGroupsChange = 10
+)
+
+func (p *pools) AddParticipants(ctx context.Context, pollID entities.PollID, owner entities.UserID, participants entities.Participants) (err error) {
+	ctx, span := tracer.Start(ctx, "pools.AddParticipants")
+	defer func() { tracing.End(span, err) }()
+
+	return p.changeParticipants(ctx, pollID, owner, participants, entities.AuditActionAddPollParticipants)
+}
+
+func (p *pools) RemoveParticipants(ctx context.Context, pollID entities.PollID, owner entities.UserID, participants entities.Participants) (err error) {
+	ctx, span := tracer.Start(ctx, "pools.RemoveParticipants")
+	defer func() { tracing.End(span, err) }()
+
+	return p.changeParticipants(ctx, pollID, owner, participants, entities.AuditActionRemovePollParticipants)
+}
+
+func (p *pools) changeParticipants(ctx context.Context, pollID entities.PollID, owner entities.UserID,
+	participants entities.Participants, action entities.AuditAction) error {
+	{ // validation
+		if pollID < 0 || participants.IsEmpty() || !validParticipantsChange(participants) {
+			return ErrInvalidPollParticipantsArguments
+		}
+	}
+
+	storagePoll, err := p.ownedPoll(ctx, pollID, owner)
+	if err != nil {
+		return err
+	} else if storagePoll == nil {
+		return ErrPollParticipantsPollNotExists
+	} else if entities.PollVisibility(storagePoll.Visibility) != entities.PollVisibilityPrivate {
+		return ErrInvalidPollParticipantsArguments
+	}
+
+	if action == entities.AuditActionAddPollParticipants {
+		if owned, err := p.ownGroups(ctx, owner, participants.Groups); err != nil {
+			return err
+		} else if !owned {
+			return ErrInvalidPollParticipantsArguments
+		}
+	}
+
+	tx, err := p.pollsStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if action == entities.AuditActionAddPollParticipants {
+		err = p.addParticipants(ctx, tx, int64(pollID), participants)
+	} else {
+		err = p.removeParticipants(ctx, tx, int64(pollID), participants)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = p.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    owner,
+		Action:     action,
+		TargetType: entities.AuditTargetPoll,
+		TargetID:   int64(pollID),
+		Metadata:   map[string]any{"participants": userIDs(participants.Users), "groups": groupIDs(participants.Groups)},
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *pools) Participants(ctx context.Context, pollID entities.PollID, owner entities.UserID) (result *entities.Participants, err error) {
+	ctx, span := tracer.Start(ctx, "pools.Participants")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if pollID < 0 {
+			return nil, ErrInvalidPollParticipantsArguments
+		}
+	}
+
+	storagePoll, err := p.ownedPoll(ctx, pollID, owner)
+	if err != nil {
+		return nil, err
+	} else if storagePoll == nil {
+		return nil, ErrPollParticipantsPollNotExists
+	}
+
+	storageParticipants, err := p.pollsStorage.ListPollParticipants(ctx, int64(pollID))
+	if err != nil {
+		return nil, err
+	}
+
+	storageGroups, err := p.pollsStorage.ListPollParticipantGroups(ctx, int64(pollID))
+	if err != nil {
+		return nil, err
+	}
+
+	result = &entities.Participants{
+		Users:  make([]entities.UserID, 0, len(storageParticipants)),
+		Groups: make([]entities.GroupID, 0, len(storageGroups)),
+	}
+	for _, participant := range storageParticipants {
+		result.Users = append(result.Users, entities.UserID(participant))
+	}
+	for _, group := range storageGroups {
+		result.Groups = append(result.Groups, entities.GroupID(group))
+	}
+
+	return result, nil
+}
+
+func validParticipantsChange(participants entities.Participants) bool {
+	return len(participants.Users) <= maxPollParticipantsChange && len(participants.Groups) <= maxPollParticipantGroupsChange
+}
+
+func (p *pools) addParticipants(ctx context.Context, tx *sqlx.Tx, pollID int64, participants entities.Participants) error {
+	if len(participants.Users) != 0 {
+		if err := p.pollsStorage.AddPollParticipants(ctx, tx, pollID, userIDs(participants.Users)); err != nil {
+			return err
+		}
+	}
+
+	if len(participants.Groups) != 0 {
+		return p.pollsStorage.AddPollParticipantGroups(ctx, tx, pollID, groupIDs(participants.Groups))
+	}
+
+	return nil
+}
+
+func (p *pools) removeParticipants(ctx context.Context, tx *sqlx.Tx, pollID int64, participants entities.Participants) error {
+	if len(participants.Users) != 0 {
+		if err := p.pollsStorage.RemovePollParticipants(ctx, tx, pollID, userIDs(participants.Users)); err != nil {
+			return err
+		}
+	}
+
+	if len(participants.Groups) != 0 {
+		return p.pollsStorage.RemovePollParticipantGroups(ctx, tx, pollID, groupIDs(participants.Groups))
+	}
+
+	return nil
+}
+
+// ownGroups tells whether all of the groups exist and belong to the owner, only the own groups
+// can be invited to the polls
+func (p *pools) ownGroups(ctx context.Context, owner entities.UserID, groups []entities.GroupID) (bool, error) {
+	if len(groups) == 0 {
+		return true, nil
+	}
+
+	storageGroups, err := p.groupsStorage.GetGroupsByIDs(ctx, groupIDs(groups))
+	if err != nil {
+		return false, err
+	}
+
+	owned := make(map[int64]bool, len(storageGroups))
+	for _, storageGroup := range storageGroups {
+		owned[storageGroup.ID] = storageGroup.OwnerID == int64(owner)
+	}
+
+	for _, group := range groups {
+		if !owned[int64(group)] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
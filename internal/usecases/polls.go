@@ -4,52 +4,167 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/mohammadne/porsesh/internal/entities"
 	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/markdown"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"github.com/mohammadne/porsesh/pkg/slugs"
 	"go.uber.org/zap"
 )
 
 type Polls interface {
 	CreatePoll(context.Context, *entities.Poll) error
-	VotePoll(ctx context.Context, v entities.PollID, u entities.UserID, index int) error
+	GetPoll(ctx context.Context, ref string, u entities.UserID) (*entities.Poll, error)
+	ResolvePoll(ctx context.Context, ref string, u entities.UserID) (entities.PollID, error)
+	PublishPoll(ctx context.Context, v entities.PollID, owner entities.UserID, publishAt time.Time) error
+	VotePoll(ctx context.Context, v entities.PollID, u entities.UserID, ballot entities.Ballot) (*entities.VoteResult, error)
 	SkipPoll(ctx context.Context, v entities.PollID, u entities.UserID) error
-	Statistics(ctx context.Context, v entities.PollID) (*entities.PollStatistics, error)
+	Statistics(ctx context.Context, v entities.PollID, u entities.UserID) (*entities.PollStatistics, error)
+	Calendar(ctx context.Context, v entities.PollID, u entities.UserID, option entities.PollOptionID) ([]byte, error)
+
+	AddParticipants(ctx context.Context, v entities.PollID, owner entities.UserID, participants entities.Participants) error
+	RemoveParticipants(ctx context.Context, v entities.PollID, owner entities.UserID, participants entities.Participants) error
+	Participants(ctx context.Context, v entities.PollID, owner entities.UserID) (*entities.Participants, error)
+
+	Voters(ctx context.Context, v entities.PollID, owner entities.UserID, optionID entities.PollOptionID, option *int, page, limit int) ([]entities.Voter, error)
+
+	Leaderboard(ctx context.Context, tag, quizSet string, page, limit int) ([]entities.LeaderboardEntry, error)
+
+	OtherAnswers(ctx context.Context, v entities.PollID, owner entities.UserID, search string, page, limit int) ([]entities.OtherAnswer, error)
+	FrequentOtherAnswers(ctx context.Context, v entities.PollID, owner entities.UserID, limit int) ([]entities.OtherAnswerFrequency, error)
+	HideOtherAnswer(ctx context.Context, v entities.PollID, owner, voter entities.UserID, hidden bool) error
+	PromoteOtherAnswer(ctx context.Context, v entities.PollID, owner entities.UserID, text string) (int64, error)
 }
 
-func NewPolls(logger *zap.Logger, ps storage.Polls, ts storage.Tags, vs storage.Votes) Polls {
+func NewPolls(cfg *Config, logger *zap.Logger, metrics *Metrics, auditor Auditor, moderator Moderator,
+	ps storage.Polls, ts storage.Tags, vs storage.Votes, gs storage.Groups) Polls {
 	return &pools{
-		logger:       logger,
-		pollsStorage: ps,
-		tagsStorage:  ts,
-		votesStorage: vs,
+		voterPepper:   []byte(cfg.VoterPepper),
+		logger:        logger,
+		metrics:       metrics,
+		auditor:       auditor,
+		moderator:     moderator,
+		pollsStorage:  ps,
+		tagsStorage:   ts,
+		votesStorage:  vs,
+		groupsStorage: gs,
 	}
 }
 
 type pools struct {
-	logger *zap.Logger
+	voterPepper []byte
+	logger      *zap.Logger
+	metrics     *Metrics
+	auditor     Auditor
+	moderator   Moderator
 	// storages
-	pollsStorage storage.Polls
-	tagsStorage  storage.Tags
-	votesStorage storage.Votes
+	pollsStorage  storage.Polls
+	tagsStorage   storage.Tags
+	votesStorage  storage.Votes
+	groupsStorage storage.Groups
 }
 
 var (
 	ErrInvalidCreatePollArguments = errors.New("")
 )
 
+const (
+	maxPollOptions      = 5
+	maxDescriptionRunes = 4000 // polls_description_check
+	pollSlugLength      = 10   // ~59 bits of entropy
+	pollSlugAttempts    = 5
+)
+
 func (p *pools) CreatePoll(ctx context.Context, poll *entities.Poll) (err error) {
+	ctx, span := tracer.Start(ctx, "pools.CreatePoll")
+	defer func() { tracing.End(span, err) }()
+
+	// the options are normalised by the validation
+	var options []entities.PollOption
+
 	{ // validation over poll
-		if len(poll.Options) <= 0 || len(poll.Options) > 5 {
+		if len(poll.Tags) > 3 {
 			return ErrInvalidCreatePollArguments
 		}
 
-		if len(poll.Tags) > 3 {
+		if len(poll.Type) == 0 {
+			poll.Type = entities.PollTypeSingle
+		} else if !poll.Type.IsValid() {
+			return ErrInvalidCreatePollArguments
+		}
+
+		maxOptions := maxPollOptions
+		if poll.Type == entities.PollTypeSchedule {
+			maxOptions = scheduleMaxSlots
+		}
+
+		if poll.Type != entities.PollTypeScale && (len(poll.Options) <= 0 || len(poll.Options) > maxOptions) {
 			return ErrInvalidCreatePollArguments
 		}
 
+		var validSlots bool
+		if options, validSlots = scheduleOptions(poll); !validSlots {
+			return ErrInvalidCreatePollArguments
+		}
+
+		if !validQuiz(poll) || !validScale(poll) || !validOther(poll) || !validShuffle(poll) {
+			return ErrInvalidCreatePollArguments
+		}
+
+		if len(poll.Visibility) == 0 {
+			poll.Visibility = entities.PollVisibilityPublic
+		} else if !poll.Visibility.IsValid() {
+			return ErrInvalidCreatePollArguments
+		}
+
+		if len(poll.ResultsVisibility) == 0 {
+			poll.ResultsVisibility = entities.ResultsVisibilityAlways
+		} else if !poll.ResultsVisibility.IsValid() {
+			return ErrInvalidCreatePollArguments
+		}
+
+		if !poll.ClosesAt.IsZero() && !poll.ClosesAt.After(time.Now()) {
+			return ErrInvalidCreatePollArguments
+		}
+
+		if poll.Description = strings.TrimSpace(poll.Description); utf8.RuneCountInString(poll.Description) > maxDescriptionRunes {
+			return ErrInvalidCreatePollArguments
+		}
+
+		if len(poll.Status) == 0 {
+			poll.Status = entities.PollStatusPublished
+		} else if !poll.Status.IsValid() || !validPublishAt(poll) {
+			return ErrInvalidCreatePollArguments
+		}
+
+		if !validParticipantsChange(poll.Participants) ||
+			(!poll.Participants.IsEmpty() && poll.Visibility != entities.PollVisibilityPrivate) {
+			return ErrInvalidCreatePollArguments
+		}
+	}
+
+	if err = p.ownAttachments(ctx, poll); err != nil {
+		return err
+	}
+
+	if owned, err := p.ownGroups(ctx, poll.UserID, poll.Participants.Groups); err != nil {
+		return err
+	} else if !owned {
+		return ErrInvalidCreatePollArguments
+	}
+
+	poll.DescriptionHTML = ""
+	if len(poll.Description) > 0 {
+		if poll.DescriptionHTML, err = markdown.Render(poll.Description); err != nil {
+			return err
+		}
 	}
 
 	tx, err := p.pollsStorage.StartTransaction(ctx)
@@ -59,22 +174,53 @@ func (p *pools) CreatePoll(ctx context.Context, poll *entities.Poll) (err error)
 	defer tx.Rollback()
 
 	storagePoll := storage.Poll{
-		UserID: int64(poll.UserID),
-		Title:  poll.Title,
+		UserID:            int64(poll.UserID),
+		Type:              string(poll.Type),
+		Title:             poll.Title,
+		Description:       poll.Description,
+		DescriptionHTML:   poll.DescriptionHTML,
+		Visibility:        string(poll.Visibility),
+		ResultsVisibility: string(poll.ResultsVisibility),
+		Anonymous:         poll.Anonymous,
+		TimeLimitSeconds:  int(poll.TimeLimit / time.Second),
+		QuizSet:           poll.QuizSet,
+		OtherMaxLength:    poll.OtherMaxLength,
+		ShuffleOptions:    poll.ShuffleOptions,
+		ClosesAt:          sql.NullTime{Time: poll.ClosesAt, Valid: !poll.ClosesAt.IsZero()},
+		PublishAt:         sql.NullTime{Time: poll.PublishAt, Valid: poll.Status == entities.PollStatusScheduled},
+		AttachmentID:      storageAttachmentID(poll.Attachment),
 	}
 
-	pollID, err := p.pollsStorage.CreatePoll(ctx, tx, &storagePoll)
-	if err != nil {
-		return err
+	if poll.Status == entities.PollStatusPublished {
+		poll.PublishedAt = time.Now()
+		storagePoll.PublishedAt = sql.NullTime{Time: poll.PublishedAt, Valid: true}
+	}
+
+	var pollID int64
+	for attempt := 1; ; attempt++ {
+		if storagePoll.Slug, err = slugs.Generate(pollSlugLength); err != nil {
+			return err
+		}
+
+		pollID, err = p.pollsStorage.CreatePoll(ctx, tx, &storagePoll)
+		if err == nil {
+			break
+		} else if !errors.Is(err, storage.ErrCreatePollSlugExists) || attempt == pollSlugAttempts {
+			return err
+		}
 	}
 
 	{ // create poll options
-		storagePollOptions := make([]storage.PollOption, 0, len(poll.Options))
-		for _, option := range poll.Options {
-			storagePollOptions = append(storagePollOptions, storage.PollOption{
-				Content: option.Content,
-				Sort:    option.Sort,
-			})
+		storagePollOptions := make([]storage.PollOption, 0, len(options))
+		for _, option := range options {
+			storagePollOption := storage.PollOption{
+				Content:      option.Content,
+				Sort:         option.Sort,
+				Correct:      option.Correct,
+				AttachmentID: storageAttachmentID(option.Attachment),
+			}
+			storageSlot(&storagePollOption, option.Slot)
+			storagePollOptions = append(storagePollOptions, storagePollOption)
 		}
 
 		err = p.pollsStorage.CreatePollOptions(ctx, tx, pollID, storagePollOptions)
@@ -83,6 +229,18 @@ func (p *pools) CreatePoll(ctx context.Context, poll *entities.Poll) (err error)
 		}
 	}
 
+	if poll.Scale != nil {
+		storagePollScale, err := storageScale(pollID, poll.Scale)
+		if err != nil {
+			return errors.Join(ErrInvalidCreatePollArguments, err)
+		}
+
+		err = p.pollsStorage.CreatePollScale(ctx, tx, storagePollScale)
+		if err != nil {
+			return err
+		}
+	}
+
 	var tagIds []int64
 	if len(poll.Tags) != 0 {
 		storageTags := make([]storage.Tag, 0, len(poll.Tags))
@@ -120,102 +278,263 @@ func (p *pools) CreatePoll(ctx context.Context, poll *entities.Poll) (err error)
 		return err
 	}
 
+	err = p.addParticipants(ctx, tx, pollID, poll.Participants)
+	if err != nil {
+		return err
+	}
+
+	err = p.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    poll.UserID,
+		Action:     entities.AuditActionCreatePoll,
+		TargetType: entities.AuditTargetPoll,
+		TargetID:   pollID,
+		Metadata: map[string]any{"title": poll.Title, "type": poll.Type, "options": len(poll.Options), "tags": tagIds,
+			"visibility": poll.Visibility, "participants": len(poll.Participants.Users),
+			"groups": len(poll.Participants.Groups), "anonymous": poll.Anonymous,
+			"status": poll.Status},
+	})
+	if err != nil {
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return err
 	}
 
+	p.metrics.PollsCreated.IncrementVector(string(poll.Type), strconv.Itoa(len(poll.Options)), strconv.Itoa(len(tagIds)))
+
+	poll.ID, poll.Slug = entities.PollID(pollID), storagePoll.Slug
 	return nil
 }
 
+var (
+	ErrInvalidGetPollArguments = errors.New("")
+	ErrGetPollPollNotExists    = errors.New("")
+)
+
+func (p *pools) GetPoll(ctx context.Context, ref string, u entities.UserID) (result *entities.Poll, err error) {
+	ctx, span := tracer.Start(ctx, "pools.GetPoll")
+	defer func() { tracing.End(span, err) }()
+
+	pollID, err := p.ResolvePoll(ctx, ref, u)
+	if err != nil {
+		if errors.Is(err, ErrInvalidResolvePollArguments) {
+			return nil, ErrInvalidGetPollArguments
+		} else if errors.Is(err, ErrResolvePollPollNotExists) {
+			return nil, ErrGetPollPollNotExists
+		}
+		return nil, err
+	}
+
+	storagePoll, err := p.accessiblePoll(ctx, pollID, u)
+	if err != nil {
+		return nil, err
+	} else if storagePoll == nil {
+		return nil, ErrGetPollPollNotExists
+	}
+
+	polls, err := hydratePolls(ctx, p.pollsStorage, []storage.Poll{*storagePoll}, u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &polls[0], nil
+}
+
 var (
 	ErrInvalidVotePollArguments = errors.New("")
 	ErrDailyUserVotesLimit      = errors.New("")
 	ErrVotePollPollNotExists    = errors.New("")
+	ErrVotePollPollClosed       = errors.New("")
+	ErrVotePollPollNotPublished = errors.New("")
+	ErrVotePollAlreadyActed     = errors.New("")
 )
 
 const dailyUserVoteLimits = 100
 
-func (p *pools) VotePoll(ctx context.Context, pollID entities.PollID, u entities.UserID, index int) error {
+func (p *pools) VotePoll(ctx context.Context, pollID entities.PollID, u entities.UserID, ballot entities.Ballot) (result *entities.VoteResult, err error) {
+	ctx, span := tracer.Start(ctx, "pools.VotePoll")
+	defer func() { tracing.End(span, err) }()
+
 	{ // validation
-		if index < 0 || pollID < 0 {
-			return ErrInvalidVotePollArguments
+		if pollID < 0 {
+			return nil, ErrInvalidVotePollArguments
 		}
 	}
 
 	count, err := p.votesStorage.GetCurrentDateUserVoteCount(ctx, int64(u))
 	if err != nil { // log but continue
-		p.logger.Error("error retrieving user daily count", zap.Int64("user_id", int64(u)), zap.Error(err))
+		logger.FromContext(ctx, p.logger).Error("error retrieving user daily count", zap.Int64("user_id", int64(u)), zap.Error(err))
 	}
 
 	if count >= dailyUserVoteLimits {
-		return ErrDailyUserVotesLimit
+		p.metrics.LimitRejections.IncrementVector(sourcePoll)
+		return nil, ErrDailyUserVotesLimit
 	}
 
-	storageOptions, err := p.pollsStorage.GetPollOptionsByPollID(ctx, int64(pollID))
+	actedAt := time.Now() // the quiz points are based on it
+	storagePoll, err := p.accessiblePoll(ctx, pollID, u)
 	if err != nil {
-		return err
-	} else if len(storageOptions) == 0 {
-		return ErrVotePollPollNotExists
+		return nil, err
+	} else if storagePoll == nil {
+		return nil, ErrVotePollPollNotExists
+	} else if poll := pollEntity(storagePoll); !poll.IsPublished() {
+		return nil, ErrVotePollPollNotPublished
+	} else if poll.IsClosed(actedAt) {
+		return nil, ErrVotePollPollClosed
 	}
 
-	if len(storageOptions)-1 < index {
-		return ErrInvalidVotePollArguments
+	choice, err := p.resolveBallot(ctx, storagePoll, &ballot, u)
+	if err != nil {
+		if errors.Is(err, errInvalidBallot) {
+			return nil, ErrInvalidVotePollArguments
+		}
+		return nil, err
 	}
 
-	slices.SortFunc(storageOptions, func(a, b storage.PollOption) int {
-		return a.Sort - b.Sort
-	})
+	tx, err := p.votesStorage.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	nullableOption := sql.NullInt64{Valid: true, Int64: storageOptions[index].ID}
-	storagePoll := storage.Vote{UserID: int64(u), PollID: int64(pollID), OptionID: nullableOption}
-	if err := p.votesStorage.CreateVote(ctx, &storagePoll); err != nil {
-		return err
+	result, err = p.vote(ctx, tx, storagePoll, choice, u, actedAt)
+	if err != nil {
+		if errors.Is(err, storage.ErrCreateVoteAlreadyExists) {
+			return nil, ErrVotePollAlreadyActed
+		}
+		return nil, err
 	}
 
-	return nil
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	if err = p.tallyAnonymous(ctx, storagePoll, choice); err != nil {
+		return nil, p.revokeAnonymous(ctx, []*storage.Poll{storagePoll}, u, nil, err)
+	}
+
+	p.metrics.Ballots.IncrementVector(storagePoll.Type, ballotVote, sourcePoll)
+	return result, nil
 }
 
 var (
 	ErrInvalidSkipPollArguments = errors.New("")
 	ErrSkipPollPollNotExists    = errors.New("")
+	ErrSkipPollPollClosed       = errors.New("")
+	ErrSkipPollPollNotPublished = errors.New("")
+	ErrSkipPollAlreadyActed     = errors.New("")
 )
 
-func (p *pools) SkipPoll(ctx context.Context, pollID entities.PollID, u entities.UserID) error {
+func (p *pools) SkipPoll(ctx context.Context, pollID entities.PollID, u entities.UserID) (err error) {
+	ctx, span := tracer.Start(ctx, "pools.SkipPoll")
+	defer func() { tracing.End(span, err) }()
+
 	{ // validation
 		if pollID < 0 {
 			return ErrInvalidSkipPollArguments
 		}
 	}
 
-	nullableOption := sql.NullInt64{Valid: false}
-	storagePoll := storage.Vote{UserID: int64(u), PollID: int64(pollID), OptionID: nullableOption}
-	if err := p.votesStorage.CreateVote(ctx, &storagePoll); err != nil {
+	storagePoll, err := p.accessiblePoll(ctx, pollID, u)
+	if err != nil {
+		return err
+	} else if storagePoll == nil {
+		return ErrSkipPollPollNotExists
+	} else if poll := pollEntity(storagePoll); !poll.IsPublished() {
+		return ErrSkipPollPollNotPublished
+	} else if poll.IsClosed(time.Now()) {
+		return ErrSkipPollPollClosed
+	}
+
+	tx, err := p.votesStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := p.skip(ctx, tx, storagePoll, u); err != nil {
 		if errors.Is(err, storage.ErrCreateVotePollNotExists) {
 			return ErrSkipPollPollNotExists
+		} else if errors.Is(err, storage.ErrCreateVoteAlreadyExists) {
+			return ErrSkipPollAlreadyActed
 		}
 		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	p.metrics.Ballots.IncrementVector(storagePoll.Type, ballotSkip, sourcePoll)
 	return nil
 }
 
 var (
 	ErrInvalidStatisticsArguments = errors.New("")
 	ErrStatisticsPollNotExists    = errors.New("")
+	ErrStatisticsResultsHidden    = errors.New("")
 )
 
-func (p *pools) Statistics(ctx context.Context, pollID entities.PollID) (result *entities.PollStatistics, err error) {
+func (p *pools) Statistics(ctx context.Context, pollID entities.PollID, u entities.UserID) (result *entities.PollStatistics, err error) {
+	ctx, span := tracer.Start(ctx, "pools.Statistics")
+	defer func() { tracing.End(span, err) }()
+
 	{ // validation
 		if pollID < 0 {
 			return nil, ErrInvalidStatisticsArguments
 		}
 	}
 
+	storagePoll, err := p.accessiblePoll(ctx, pollID, u)
+	if err != nil {
+		return nil, err
+	} else if storagePoll == nil {
+		return nil, ErrStatisticsPollNotExists
+	}
+
+	if visible, err := p.resultsVisible(ctx, storagePoll, u); err != nil {
+		return nil, err
+	} else if !visible {
+		return nil, ErrStatisticsResultsHidden
+	}
+
 	result = &entities.PollStatistics{PoolID: pollID}
 
 	// todo: retrieve from cache
 
+	if entities.PollType(storagePoll.Type) == entities.PollTypeScale {
+		scale, err := p.pollScale(ctx, int64(pollID))
+		if err != nil {
+			return nil, err
+		} else if scale == nil {
+			return nil, ErrStatisticsPollNotExists
+		}
+
+		distribution, err := p.votesStorage.GetPollValuesDistribution(ctx, int64(pollID))
+		if err != nil {
+			return nil, err
+		}
+
+		result.Scale = scaleStatistics(scale, distribution)
+		return result, nil
+	}
+
+	if entities.PollType(storagePoll.Type) == entities.PollTypeSchedule {
+		storageOptions, err := p.sortedOptions(ctx, pollID)
+		if err != nil {
+			return nil, err
+		}
+
+		availabilities, err := p.votesStorage.GetScheduleAvailabilities(ctx, int64(pollID))
+		if err != nil {
+			return nil, err
+		}
+
+		result.Schedule = scheduleStatistics(storageOptions, availabilities)
+		return result, nil
+	}
+
 	storageOptions, err := p.pollsStorage.GetPollOptionsByPollID(ctx, int64(pollID))
 	if err != nil {
 		return nil, err
@@ -223,6 +542,33 @@ func (p *pools) Statistics(ctx context.Context, pollID entities.PollID) (result
 		return nil, ErrStatisticsPollNotExists
 	}
 
+	correct := func(so *storage.PollOption) *bool { return nil }
+	if poll := pollEntity(storagePoll); poll.Type == entities.PollTypeQuiz && poll.IsClosed(time.Now()) {
+		correct = func(so *storage.PollOption) *bool { return &so.Correct }
+	}
+
+	if storagePoll.OtherMaxLength > 0 {
+		count, err := p.votesStorage.GetPollOtherVotesCount(ctx, int64(pollID))
+		if err != nil {
+			return nil, err
+		}
+		result.Other = &count
+	}
+
+	if storagePoll.Anonymous { // there are no per-vote rows to count, only the tallies
+		tallies, err := p.votesStorage.GetAnonymousTallies(ctx, int64(pollID))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, so := range storageOptions {
+			result.Votes = append(result.Votes, entities.PollStatisticsVote{
+				OptionID: entities.PollOptionID(so.ID), Option: so.Content, Count: tallies[so.ID], Correct: correct(&so),
+			})
+		}
+		return result, nil
+	}
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	wg.Add(len(storageOptions))
@@ -233,13 +579,13 @@ func (p *pools) Statistics(ctx context.Context, pollID entities.PollID) (result
 
 			count, err := p.votesStorage.GetPollOptionVotesCount(ctx, so.ID)
 			if err != nil {
-				p.logger.Error("error counting options", zap.Int64("option_id", so.ID), zap.Error(err))
+				logger.FromContext(ctx, p.logger).Error("error counting options", zap.Int64("option_id", so.ID), zap.Error(err))
 				return
 			}
 
 			mu.Lock()
 			result.Votes = append(result.Votes, entities.PollStatisticsVote{
-				Option: so.Content, Count: count,
+				OptionID: entities.PollOptionID(so.ID), Option: so.Content, Count: count, Correct: correct(so),
 			})
 			mu.Unlock()
 		}(&so)
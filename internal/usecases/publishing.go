@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"go.uber.org/zap"
+)
+
+// validPublishAt checks the publish time is only given for the scheduled polls and it comes
+// before the close time
+func validPublishAt(poll *entities.Poll) bool {
+	if poll.Status != entities.PollStatusScheduled {
+		return poll.PublishAt.IsZero()
+	}
+
+	return poll.PublishAt.After(time.Now()) && (poll.ClosesAt.IsZero() || poll.PublishAt.Before(poll.ClosesAt))
+}
+
+var (
+	ErrInvalidPublishPollArguments = errors.New("")
+	ErrPublishPollPollNotExists    = errors.New("")
+	ErrPublishPollAlreadyPublished = errors.New("")
+)
+
+// PublishPoll publishes the draft right away when publishAt is zero, otherwise it (re)schedules it
+func (p *pools) PublishPoll(ctx context.Context, pollID entities.PollID, owner entities.UserID, publishAt time.Time) (err error) {
+	ctx, span := tracer.Start(ctx, "pools.PublishPoll")
+	defer func() { tracing.End(span, err) }()
+
+	now := time.Now()
+
+	{ // validation
+		if pollID < 0 || (!publishAt.IsZero() && !publishAt.After(now)) {
+			return ErrInvalidPublishPollArguments
+		}
+	}
+
+	storagePoll, err := p.ownedPoll(ctx, pollID, owner)
+	if err != nil {
+		return err
+	} else if storagePoll == nil {
+		return ErrPublishPollPollNotExists
+	} else if storagePoll.PublishedAt.Valid {
+		return ErrPublishPollAlreadyPublished
+	}
+
+	storagePublishAt, storagePublishedAt := sql.NullTime{Valid: true, Time: publishAt}, sql.NullTime{}
+	if publishAt.IsZero() {
+		storagePublishAt, storagePublishedAt = sql.NullTime{Valid: true, Time: now}, sql.NullTime{Valid: true, Time: now}
+	}
+
+	if storagePoll.ClosesAt.Valid && !storagePublishAt.Time.Before(storagePoll.ClosesAt.Time) {
+		return ErrInvalidPublishPollArguments
+	}
+
+	tx, err := p.pollsStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = p.pollsStorage.PublishPoll(ctx, tx, int64(pollID), storagePublishAt, storagePublishedAt)
+	if err != nil {
+		if errors.Is(err, storage.ErrPublishPollAlreadyPublished) {
+			return ErrPublishPollAlreadyPublished
+		}
+		return err
+	}
+
+	err = p.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    owner,
+		Action:     entities.AuditActionPublishPoll,
+		TargetType: entities.AuditTargetPoll,
+		TargetID:   int64(pollID),
+		Metadata:   map[string]any{"scheduled": !storagePublishedAt.Valid, "publish_at": storagePublishAt.Time},
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Scheduler publishes the scheduled drafts once their time has come
+type Scheduler interface {
+	Serve(ctx context.Context, wg *sync.WaitGroup, interval time.Duration)
+}
+
+func NewScheduler(logger *zap.Logger, auditor Auditor, ps storage.Polls) Scheduler {
+	return &scheduler{
+		logger:       logger,
+		auditor:      auditor,
+		pollsStorage: ps,
+	}
+}
+
+type scheduler struct {
+	logger  *zap.Logger
+	auditor Auditor
+	// storages
+	pollsStorage storage.Polls
+}
+
+const schedulerBatchSize = 100
+
+func (s *scheduler) Serve(ctx context.Context, wg *sync.WaitGroup, interval time.Duration) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Warn("gracefully shutdown the scheduler")
+			return
+		case <-ticker.C:
+		}
+
+		for { // the batches are drained until the last partial one
+			published, err := s.publishDuePolls(ctx)
+			if err != nil {
+				s.logger.Error("error publishing the due polls", zap.Error(err))
+				break
+			} else if published != 0 {
+				s.logger.Info("published the scheduled polls", zap.Int("count", published))
+			}
+
+			if published < schedulerBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// publishDuePolls publishes a batch of the due drafts and audits them on behalf of their owners
+func (s *scheduler) publishDuePolls(ctx context.Context) (published int, err error) {
+	ctx, span := tracer.Start(ctx, "scheduler.publishDuePolls") // a root span on every batch
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.pollsStorage.StartTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	storagePolls, err := s.pollsStorage.PublishDuePolls(ctx, tx, schedulerBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, storagePoll := range storagePolls {
+		err = s.auditor.Record(ctx, tx, &entities.AuditEvent{
+			ActorID:    entities.UserID(storagePoll.UserID),
+			Action:     entities.AuditActionPublishPoll,
+			TargetType: entities.AuditTargetPoll,
+			TargetID:   storagePoll.ID,
+			Metadata:   map[string]any{"scheduled": true, "publish_at": storagePoll.PublishAt.Time},
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(storagePolls), nil
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+const (
+	quizMaxPoints    = 1000
+	quizMinTimeLimit = 5 * time.Second
+	quizMaxTimeLimit = 24 * time.Hour
+	quizSetMaxLength = 64
+)
+
+// validQuiz checks the quiz-only settings, they must be left out of the other poll types
+func validQuiz(poll *entities.Poll) bool {
+	corrects := 0
+	for _, option := range poll.Options {
+		if option.Correct {
+			corrects++
+		}
+	}
+
+	if poll.Type != entities.PollTypeQuiz {
+		return corrects == 0 && poll.TimeLimit == 0 && len(poll.QuizSet) == 0
+	}
+
+	if corrects == 0 || poll.Anonymous { // the leaderboards need the attributed votes
+		return false
+	}
+
+	if poll.TimeLimit != 0 && (poll.TimeLimit < quizMinTimeLimit || poll.TimeLimit > quizMaxTimeLimit) {
+		return false
+	}
+
+	return len(poll.QuizSet) <= quizSetMaxLength
+}
+
+// quizPoints scores a correct answer by its speed, half of the points are granted just for being
+// correct and the other half fades out linearly from the publishing until the deadline of the quiz
+// (the time limit or the close time, whichever comes first). Without any deadline all the points are granted.
+func quizPoints(poll *entities.Poll, actedAt time.Time, correct bool) int {
+	if !correct {
+		return 0
+	}
+
+	deadline := poll.ClosesAt
+	if poll.TimeLimit > 0 {
+		if limit := poll.PublishedAt.Add(poll.TimeLimit); deadline.IsZero() || limit.Before(deadline) {
+			deadline = limit
+		}
+	}
+
+	if deadline.IsZero() {
+		return quizMaxPoints
+	}
+
+	window, remaining := deadline.Sub(poll.PublishedAt), deadline.Sub(actedAt)
+	if window <= 0 || remaining <= 0 {
+		return quizMaxPoints / 2
+	}
+
+	return quizMaxPoints/2 + int(int64(quizMaxPoints/2)*int64(remaining)/int64(window))
+}
+
+var (
+	ErrInvalidLeaderboardArguments = errors.New("")
+)
+
+// Leaderboard ranks the users over the published public quizzes of the tag and/or the quiz set, at least one
+// is required. The private and unlisted quizzes are left out as the leaderboard is served without a user.
+func (p *pools) Leaderboard(ctx context.Context, tag, quizSet string, page, limit int) (result []entities.LeaderboardEntry, err error) {
+	ctx, span := tracer.Start(ctx, "pools.Leaderboard")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if len(tag) == 0 && len(quizSet) == 0 {
+			return nil, ErrInvalidLeaderboardArguments
+		}
+
+		if limit < 1 {
+			limit = 20
+		} else if limit > 100 {
+			limit = 100
+		}
+
+		if page < 1 {
+			page = 1
+		}
+	}
+
+	result = make([]entities.LeaderboardEntry, 0, limit)
+
+	var tagID int64
+	if len(tag) != 0 {
+		storageTag, err := p.tagsStorage.GetTagByName(ctx, tag)
+		if err != nil {
+			return nil, err
+		} else if storageTag == nil { // nobody has played on an unknown tag
+			return result, nil
+		}
+		tagID = storageTag.ID
+	}
+
+	storageEntries, err := p.votesStorage.GetQuizLeaderboard(ctx, tagID, quizSet, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storageEntry := range storageEntries {
+		result = append(result, entities.LeaderboardEntry{
+			UserID:   entities.UserID(storageEntry.UserID),
+			Points:   storageEntry.Points,
+			Correct:  storageEntry.Correct,
+			Answered: storageEntry.Answered,
+		})
+	}
+
+	return result, nil
+}
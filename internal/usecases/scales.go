@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+)
+
+const (
+	scaleMaxValues      = 101
+	scaleMaxLabelLength = 64
+)
+
+// validScale checks the scale-only settings, the scale polls have no options at all
+func validScale(poll *entities.Poll) bool {
+	if poll.Type != entities.PollTypeScale {
+		return poll.Scale == nil
+	}
+
+	scale := poll.Scale
+	if scale == nil || len(poll.Options) != 0 || poll.Anonymous { // the tallies are per option
+		return false
+	}
+
+	if scale.Step <= 0 || scale.Max <= scale.Min || (scale.Max-scale.Min)%scale.Step != 0 ||
+		(scale.Max-scale.Min)/scale.Step+1 > scaleMaxValues {
+		return false
+	}
+
+	for value, label := range scale.Labels {
+		if !scale.Contains(value) || len(label) == 0 || len(label) > scaleMaxLabelLength {
+			return false
+		}
+	}
+
+	return true
+}
+
+func storageScale(pollID int64, scale *entities.PollScale) (*storage.PollScale, error) {
+	labels := make(map[string]string, len(scale.Labels))
+	for value, label := range scale.Labels {
+		labels[strconv.Itoa(value)] = label
+	}
+
+	encoded, err := json.Marshal(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage.PollScale{PollID: pollID, Min: scale.Min, Max: scale.Max, Step: scale.Step, Labels: encoded}, nil
+}
+
+func scaleEntity(storageScale *storage.PollScale) *entities.PollScale {
+	scale := &entities.PollScale{Min: storageScale.Min, Max: storageScale.Max, Step: storageScale.Step}
+
+	labels := make(map[string]string)
+	if err := json.Unmarshal(storageScale.Labels, &labels); err == nil && len(labels) != 0 {
+		scale.Labels = make(map[int]string, len(labels))
+		for raw, label := range labels {
+			if value, err := strconv.Atoi(raw); err == nil {
+				scale.Labels[value] = label
+			}
+		}
+	}
+
+	return scale
+}
+
+// pollScale retrieves the scale of the poll, nil if it has none
+func (p *pools) pollScale(ctx context.Context, pollID int64) (*entities.PollScale, error) {
+	storageScales, err := p.pollsStorage.GetPollScalesByPollIDs(ctx, []int64{pollID})
+	if err != nil || len(storageScales) == 0 {
+		return nil, err
+	}
+
+	return scaleEntity(&storageScales[0]), nil
+}
+
+// scaleStatistics summarizes the distribution of the values, the median of an even count
+// is the mean of the two middle values
+func scaleStatistics(scale *entities.PollScale, distribution map[int]uint64) *entities.PollScaleStatistics {
+	result := &entities.PollScaleStatistics{}
+
+	var sum float64
+	for value := scale.Min; value <= scale.Max; value += scale.Step {
+		count := distribution[value]
+		result.Distribution = append(result.Distribution, entities.PollScaleBucket{
+			Value: value, Label: scale.Labels[value], Count: count,
+		})
+		result.Count += count
+		sum += float64(value) * float64(count)
+	}
+
+	if result.Count == 0 {
+		return result
+	}
+
+	result.Mean = sum / float64(result.Count)
+
+	var squares float64
+	lower, upper := (result.Count-1)/2, result.Count/2 // the indexes of the middle values
+	lowerValue, upperValue, seen := 0, 0, uint64(0)
+	for _, bucket := range result.Distribution {
+		if bucket.Count == 0 {
+			continue
+		}
+
+		deviation := float64(bucket.Value) - result.Mean
+		squares += deviation * deviation * float64(bucket.Count)
+
+		if seen <= lower && lower < seen+bucket.Count {
+			lowerValue = bucket.Value
+		}
+		if seen <= upper && upper < seen+bucket.Count {
+			upperValue = bucket.Value
+		}
+		seen += bucket.Count
+	}
+
+	result.Median = float64(lowerValue+upperValue) / 2
+	result.StdDev = math.Sqrt(squares / float64(result.Count))
+
+	if scale.IsNPS() {
+		var promoters, detractors uint64
+		for _, bucket := range result.Distribution {
+			if bucket.Value >= 9 {
+				promoters += bucket.Count
+			} else if bucket.Value <= 6 {
+				detractors += bucket.Count
+			}
+		}
+		nps := (float64(promoters) - float64(detractors)) * 100 / float64(result.Count)
+		result.NPS = &nps
+	}
+
+	return result
+}
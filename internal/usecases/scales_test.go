@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+)
+
+func TestScaleStatistics(t *testing.T) {
+	scale := &entities.PollScale{Min: 1, Max: 5, Step: 1, Labels: map[int]string{1: "bad", 5: "good"}}
+
+	tests := []struct {
+		name         string
+		distribution map[int]uint64
+		count        uint64
+		mean         float64
+		median       float64
+		stdDev       float64
+	}{
+		{name: "empty", distribution: map[int]uint64{}},
+		{name: "single", distribution: map[int]uint64{3: 1}, count: 1, mean: 3, median: 3},
+		{name: "odd count", distribution: map[int]uint64{1: 1, 2: 1, 5: 1}, count: 3,
+			mean: 8.0 / 3, median: 2, stdDev: math.Sqrt(26.0 / 9)},
+		{name: "even count", distribution: map[int]uint64{1: 1, 2: 1, 4: 1, 5: 1}, count: 4,
+			mean: 3, median: 3, stdDev: math.Sqrt(2.5)},
+		{name: "even count in one bucket", distribution: map[int]uint64{2: 2, 4: 2}, count: 4,
+			mean: 3, median: 3, stdDev: 1},
+		{name: "same values", distribution: map[int]uint64{4: 5}, count: 5, mean: 4, median: 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := scaleStatistics(scale, test.distribution)
+
+			if len(result.Distribution) != 5 {
+				t.Fatalf("expected every value of the scale, got %d buckets", len(result.Distribution))
+			}
+			if result.Distribution[0].Label != "bad" || result.Distribution[4].Label != "good" {
+				t.Fatalf("the labels should be kept on the buckets, got %+v", result.Distribution)
+			}
+			if result.Count != test.count {
+				t.Fatalf("expected count %d, got %d", test.count, result.Count)
+			}
+			if !almostEqual(result.Mean, test.mean) {
+				t.Fatalf("expected mean %f, got %f", test.mean, result.Mean)
+			}
+			if !almostEqual(result.Median, test.median) {
+				t.Fatalf("expected median %f, got %f", test.median, result.Median)
+			}
+			if !almostEqual(result.StdDev, test.stdDev) {
+				t.Fatalf("expected standard deviation %f, got %f", test.stdDev, result.StdDev)
+			}
+			if result.NPS != nil {
+				t.Fatalf("only the 0 to 10 scales have an NPS")
+			}
+		})
+	}
+}
+
+func TestScaleStatisticsNPS(t *testing.T) {
+	scale := &entities.PollScale{Min: 0, Max: 10, Step: 1}
+
+	tests := []struct {
+		name         string
+		distribution map[int]uint64
+		nps          float64
+	}{
+		{name: "six is a detractor", distribution: map[int]uint64{6: 1}, nps: -100},
+		{name: "seven is passive", distribution: map[int]uint64{7: 1}, nps: 0},
+		{name: "eight is passive", distribution: map[int]uint64{8: 1}, nps: 0},
+		{name: "nine is a promoter", distribution: map[int]uint64{9: 1}, nps: 100},
+		{name: "mixed", distribution: map[int]uint64{0: 1, 6: 1, 7: 2, 9: 3, 10: 3}, nps: 40},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := scaleStatistics(scale, test.distribution)
+			if result.NPS == nil {
+				t.Fatalf("the 0 to 10 scale should have an NPS")
+			}
+			if !almostEqual(*result.NPS, test.nps) {
+				t.Fatalf("expected NPS %f, got %f", test.nps, *result.NPS)
+			}
+		})
+	}
+
+	if result := scaleStatistics(scale, map[int]uint64{}); result.NPS != nil {
+		t.Fatalf("the NPS of no votes should be left out")
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
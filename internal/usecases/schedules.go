@@ -0,0 +1,257 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/icalendar"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+const (
+	scheduleMaxSlots       = 20
+	scheduleMaxSlotLength  = 7 * 24 * time.Hour
+	scheduleMaxContentSize = 128
+)
+
+// scheduleOptions checks the slots of the schedule polls, the other poll types can't have any.
+// The options are returned normalised, without touching the given ones: the missing time zones
+// default to UTC and the missing contents to the label of the slot.
+func scheduleOptions(poll *entities.Poll) ([]entities.PollOption, bool) {
+	if poll.Type != entities.PollTypeSchedule {
+		for _, option := range poll.Options {
+			if option.Slot != nil {
+				return nil, false
+			}
+		}
+		return poll.Options, true
+	}
+
+	if poll.Anonymous { // the availability matrix is per voter
+		return nil, false
+	}
+
+	result := make([]entities.PollOption, 0, len(poll.Options))
+	for _, option := range poll.Options {
+		if option.Slot == nil || !option.Slot.EndsAt.After(option.Slot.StartsAt) ||
+			option.Slot.EndsAt.Sub(option.Slot.StartsAt) > scheduleMaxSlotLength {
+			return nil, false
+		}
+
+		slot := *option.Slot
+		if len(slot.TimeZone) == 0 {
+			slot.TimeZone = time.UTC.String()
+		}
+		location, err := time.LoadLocation(slot.TimeZone)
+		if err != nil {
+			return nil, false
+		}
+
+		if len(option.Content) == 0 {
+			option.Content = slotLabel(&slot, location)
+		} else if len(option.Content) > scheduleMaxContentSize {
+			return nil, false
+		}
+
+		option.Slot = &slot
+		result = append(result, option)
+	}
+
+	return result, true
+}
+
+// slotLabel formats the slot in its own time zone, e.g. "Tue 20 Oct 2026 10:00-11:00 Europe/Berlin"
+func slotLabel(slot *entities.PollSlot, location *time.Location) string {
+	startsAt, endsAt := slot.StartsAt.In(location), slot.EndsAt.In(location)
+	if startsAt.YearDay() == endsAt.YearDay() && startsAt.Year() == endsAt.Year() {
+		return fmt.Sprintf("%s-%s %s", startsAt.Format("Mon 02 Jan 2006 15:04"), endsAt.Format("15:04"), slot.TimeZone)
+	}
+	return fmt.Sprintf("%s - %s %s", startsAt.Format("Mon 02 Jan 2006 15:04"), endsAt.Format("Mon 02 Jan 2006 15:04"), slot.TimeZone)
+}
+
+func storageSlot(option *storage.PollOption, slot *entities.PollSlot) {
+	if slot == nil {
+		return
+	}
+	option.StartsAt = sql.NullTime{Valid: true, Time: slot.StartsAt}
+	option.EndsAt = sql.NullTime{Valid: true, Time: slot.EndsAt}
+	option.TimeZone = slot.TimeZone
+}
+
+// slotEntity is the slot of the option, nil unless it belongs to a schedule poll
+func slotEntity(option *storage.PollOption) *entities.PollSlot {
+	if !option.StartsAt.Valid {
+		return nil
+	}
+	return &entities.PollSlot{StartsAt: option.StartsAt.Time, EndsAt: option.EndsAt.Time, TimeZone: option.TimeZone}
+}
+
+// resolveAvailability checks the availability of the ballot is given for every slot
+func resolveAvailability(storageOptions []storage.PollOption, availability []entities.Availability) ([]storage.ScheduleAvailability, error) {
+	if len(availability) != len(storageOptions) {
+		return nil, errInvalidBallot
+	}
+
+	result := make([]storage.ScheduleAvailability, 0, len(storageOptions))
+	for index, so := range storageOptions {
+		if !availability[index].IsValid() {
+			return nil, errInvalidBallot
+		}
+		result = append(result, storage.ScheduleAvailability{OptionID: so.ID, Availability: string(availability[index])})
+	}
+
+	return result, nil
+}
+
+// scheduleStatistics builds the availability matrix of the voters and ranks the slots by their yes
+// and then if-need-be counts, the earlier slot wins the ties
+func scheduleStatistics(storageOptions []storage.PollOption, availabilities []storage.ScheduleAvailability) *entities.PollScheduleStatistics {
+	result := &entities.PollScheduleStatistics{
+		Slots:  make([]entities.PollScheduleSlot, 0, len(storageOptions)),
+		Best:   make([]int, 0, len(storageOptions)),
+		Matrix: make([]entities.PollScheduleVoter, 0),
+	}
+
+	indexes := make(map[int64]int, len(storageOptions))
+	for index, so := range storageOptions {
+		indexes[so.ID] = index
+		slot := entities.PollSlot{}
+		if s := slotEntity(&so); s != nil {
+			slot = *s
+		}
+		result.Slots = append(result.Slots, entities.PollScheduleSlot{
+			OptionID: entities.PollOptionID(so.ID), Option: so.Content, Slot: slot,
+		})
+	}
+
+	for _, sa := range availabilities { // they are grouped by the voters
+		index, exists := indexes[sa.OptionID]
+		if !exists {
+			continue
+		}
+
+		if last := len(result.Matrix) - 1; last < 0 || result.Matrix[last].UserID != entities.UserID(sa.UserID) {
+			result.Matrix = append(result.Matrix, entities.PollScheduleVoter{
+				UserID:       entities.UserID(sa.UserID),
+				Availability: make([]entities.Availability, len(storageOptions)),
+			})
+		}
+		result.Matrix[len(result.Matrix)-1].Availability[index] = entities.Availability(sa.Availability)
+
+		switch slot := &result.Slots[index]; entities.Availability(sa.Availability) {
+		case entities.AvailabilityYes:
+			slot.Yes++
+		case entities.AvailabilityIfNeedBe:
+			slot.IfNeedBe++
+		case entities.AvailabilityNo:
+			slot.No++
+		}
+	}
+
+	for index, slot := range result.Slots {
+		if slot.Yes+slot.IfNeedBe != 0 {
+			result.Best = append(result.Best, index)
+		}
+	}
+	slices.SortStableFunc(result.Best, func(a, b int) int {
+		if c := cmp.Compare(result.Slots[b].Yes, result.Slots[a].Yes); c != 0 {
+			return c
+		}
+		return cmp.Compare(result.Slots[b].IfNeedBe, result.Slots[a].IfNeedBe)
+	})
+
+	result.BestOptionIDs = make([]entities.PollOptionID, 0, len(result.Best))
+	for _, index := range result.Best {
+		result.BestOptionIDs = append(result.BestOptionIDs, result.Slots[index].OptionID)
+	}
+
+	return result
+}
+
+var (
+	ErrInvalidCalendarArguments = errors.New("")
+	ErrCalendarPollNotExists    = errors.New("")
+	ErrCalendarResultsHidden    = errors.New("")
+	ErrCalendarNoBestSlot       = errors.New("")
+)
+
+// Calendar exports the slot of the schedule poll as an iCalendar file, the chosen one by its option id
+// or the best one by the availabilities of the voters if it's not given
+func (p *pools) Calendar(ctx context.Context, pollID entities.PollID, u entities.UserID, optionID entities.PollOptionID) (result []byte, err error) {
+	ctx, span := tracer.Start(ctx, "pools.Calendar")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if pollID < 0 || optionID < 0 {
+			return nil, ErrInvalidCalendarArguments
+		}
+	}
+
+	storagePoll, err := p.accessiblePoll(ctx, pollID, u)
+	if err != nil {
+		return nil, err
+	} else if storagePoll == nil {
+		return nil, ErrCalendarPollNotExists
+	} else if entities.PollType(storagePoll.Type) != entities.PollTypeSchedule {
+		return nil, ErrInvalidCalendarArguments
+	}
+
+	var so *storage.PollOption
+	if optionID != 0 {
+		so, err = p.pollsStorage.GetPollOption(ctx, storagePoll.ID, int64(optionID))
+		if err != nil {
+			return nil, err
+		} else if so == nil {
+			return nil, ErrInvalidCalendarArguments
+		}
+	} else if so, err = p.bestSlot(ctx, storagePoll, u); err != nil {
+		return nil, err
+	}
+
+	slot := slotEntity(so)
+	if slot == nil {
+		return nil, ErrInvalidCalendarArguments
+	}
+
+	return icalendar.Encode(icalendar.Event{
+		UID:         fmt.Sprintf("%s-%d@porsesh", storagePoll.Slug, so.ID),
+		Summary:     storagePoll.Title,
+		Description: so.Content,
+		StartsAt:    slot.StartsAt,
+		EndsAt:      slot.EndsAt,
+	}), nil
+}
+
+// bestSlot is the top ranked slot of the schedule poll, it tells the results
+// so it's only given to the ones who can see them
+func (p *pools) bestSlot(ctx context.Context, storagePoll *storage.Poll, u entities.UserID) (*storage.PollOption, error) {
+	if visible, err := p.resultsVisible(ctx, storagePoll, u); err != nil {
+		return nil, err
+	} else if !visible {
+		return nil, ErrCalendarResultsHidden
+	}
+
+	storageOptions, err := p.sortedOptions(ctx, entities.PollID(storagePoll.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	availabilities, err := p.votesStorage.GetScheduleAvailabilities(ctx, storagePoll.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics := scheduleStatistics(storageOptions, availabilities)
+	if len(statistics.Best) == 0 {
+		return nil, ErrCalendarNoBestSlot
+	}
+
+	return &storageOptions[statistics.Best[0]], nil
+}
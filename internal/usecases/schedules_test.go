@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+)
+
+func TestScheduleOptions(t *testing.T) {
+	startsAt := time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+	poll := &entities.Poll{
+		Type: entities.PollTypeSchedule,
+		Options: []entities.PollOption{
+			{Slot: &entities.PollSlot{StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour)}},
+			{Content: "afternoon", Slot: &entities.PollSlot{
+				StartsAt: startsAt.Add(6 * time.Hour), EndsAt: startsAt.Add(7 * time.Hour), TimeZone: "Europe/Berlin",
+			}},
+		},
+	}
+
+	options, valid := scheduleOptions(poll)
+	if !valid {
+		t.Fatalf("the schedule should be valid")
+	}
+
+	if options[0].Slot.TimeZone != "UTC" || options[0].Content != "Tue 20 Oct 2026 08:00-09:00 UTC" {
+		t.Fatalf("the first slot should be normalised, got %q in %q", options[0].Content, options[0].Slot.TimeZone)
+	}
+	if options[1].Slot.TimeZone != "Europe/Berlin" || options[1].Content != "afternoon" {
+		t.Fatalf("the second slot should be kept, got %q in %q", options[1].Content, options[1].Slot.TimeZone)
+	}
+
+	// the given poll is left untouched
+	if poll.Options[0].Content != "" || poll.Options[0].Slot.TimeZone != "" {
+		t.Fatalf("the options of the poll shouldn't be changed, got %+v", poll.Options[0])
+	}
+}
+
+func TestScheduleOptionsInvalid(t *testing.T) {
+	startsAt := time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+	slot := &entities.PollSlot{StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour)}
+
+	tests := []struct {
+		name string
+		poll entities.Poll
+	}{
+		{name: "slot on single poll", poll: entities.Poll{
+			Type: entities.PollTypeSingle, Options: []entities.PollOption{{Content: "a", Slot: slot}},
+		}},
+		{name: "anonymous", poll: entities.Poll{
+			Type: entities.PollTypeSchedule, Anonymous: true, Options: []entities.PollOption{{Slot: slot}},
+		}},
+		{name: "missing slot", poll: entities.Poll{
+			Type: entities.PollTypeSchedule, Options: []entities.PollOption{{Content: "a"}},
+		}},
+		{name: "ends before start", poll: entities.Poll{
+			Type: entities.PollTypeSchedule, Options: []entities.PollOption{{Slot: &entities.PollSlot{
+				StartsAt: startsAt, EndsAt: startsAt.Add(-time.Hour),
+			}}},
+		}},
+		{name: "too long", poll: entities.Poll{
+			Type: entities.PollTypeSchedule, Options: []entities.PollOption{{Slot: &entities.PollSlot{
+				StartsAt: startsAt, EndsAt: startsAt.Add(scheduleMaxSlotLength + time.Hour),
+			}}},
+		}},
+		{name: "unknown time zone", poll: entities.Poll{
+			Type: entities.PollTypeSchedule, Options: []entities.PollOption{{Slot: &entities.PollSlot{
+				StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour), TimeZone: "Mars/Olympus",
+			}}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, valid := scheduleOptions(&test.poll); valid {
+				t.Fatalf("the schedule should be rejected")
+			}
+		})
+	}
+}
+
+func TestScheduleStatistics(t *testing.T) {
+	storageOptions := []storage.PollOption{{ID: 11, Sort: 1}, {ID: 12, Sort: 2}, {ID: 13, Sort: 3}}
+	availabilities := []storage.ScheduleAvailability{
+		{UserID: 2, OptionID: 11, Availability: string(entities.AvailabilityIfNeedBe)},
+		{UserID: 2, OptionID: 12, Availability: string(entities.AvailabilityYes)},
+		{UserID: 2, OptionID: 13, Availability: string(entities.AvailabilityNo)},
+		{UserID: 3, OptionID: 11, Availability: string(entities.AvailabilityYes)},
+		{UserID: 3, OptionID: 12, Availability: string(entities.AvailabilityYes)},
+		{UserID: 3, OptionID: 13, Availability: string(entities.AvailabilityNo)},
+	}
+
+	result := scheduleStatistics(storageOptions, availabilities)
+
+	if !slices.Equal(result.Best, []int{1, 0}) {
+		t.Fatalf("expected the best slots [1 0], got %v", result.Best)
+	}
+	if !slices.Equal(result.BestOptionIDs, []entities.PollOptionID{12, 11}) {
+		t.Fatalf("expected the best option ids [12 11], got %v", result.BestOptionIDs)
+	}
+	if len(result.Matrix) != 2 || result.Matrix[0].Availability[0] != entities.AvailabilityIfNeedBe {
+		t.Fatalf("unexpected availability matrix %+v", result.Matrix)
+	}
+	if slot := result.Slots[2]; slot.OptionID != 13 || slot.No != 2 {
+		t.Fatalf("unexpected slot %+v", slot)
+	}
+}
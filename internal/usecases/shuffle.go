@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+)
+
+func validShuffle(poll *entities.Poll) bool {
+	return !poll.ShuffleOptions || poll.Type == entities.PollTypeSingle || poll.Type == entities.PollTypeQuiz
+}
+
+// shuffleOptions reorders the sorted options of the poll in place for the voter, the option indexes
+// of the voter refer to this order. It's derived from the poll, the voter and the option IDs, so it's
+// the same on every request and a newly added option doesn't move the others around. The owner keeps
+// the original order as the owner-only endpoints index into it.
+func shuffleOptions(storagePoll *storage.Poll, storageOptions []storage.PollOption, u entities.UserID) {
+	if !storagePoll.ShuffleOptions || entities.UserID(storagePoll.UserID) == u {
+		return
+	}
+
+	keys := make(map[int64][]byte, len(storageOptions))
+	for _, so := range storageOptions {
+		sum := sha256.Sum256(fmt.Appendf(nil, "%d:%d:%d", storagePoll.ID, u, so.ID))
+		keys[so.ID] = sum[:]
+	}
+
+	slices.SortStableFunc(storageOptions, func(a, b storage.PollOption) int {
+		return bytes.Compare(keys[a.ID], keys[b.ID])
+	})
+}
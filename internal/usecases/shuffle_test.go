@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+)
+
+func shuffleTestOptions() []storage.PollOption {
+	return []storage.PollOption{{ID: 11, Sort: 1}, {ID: 12, Sort: 2}, {ID: 13, Sort: 3}, {ID: 14, Sort: 4}, {ID: 15, Sort: 5}}
+}
+
+func optionIDs(storageOptions []storage.PollOption) []int64 {
+	result := make([]int64, 0, len(storageOptions))
+	for _, so := range storageOptions {
+		result = append(result, so.ID)
+	}
+	return result
+}
+
+func TestShuffleOptionsDeterministic(t *testing.T) {
+	storagePoll := &storage.Poll{ID: 7, UserID: 1, ShuffleOptions: true}
+
+	orders := map[string]bool{}
+	for u := entities.UserID(2); u < 50; u++ {
+		first, second := shuffleTestOptions(), shuffleTestOptions()
+		shuffleOptions(storagePoll, first, u)
+		shuffleOptions(storagePoll, second, u)
+
+		if !slices.Equal(optionIDs(first), optionIDs(second)) {
+			t.Fatalf("the order of the voter %d should be the same on every request", u)
+		}
+
+		ids := optionIDs(first)
+		sorted := slices.Sorted(slices.Values(ids))
+		if !slices.Equal(sorted, optionIDs(shuffleTestOptions())) {
+			t.Fatalf("the options should only be reordered, got %v", ids)
+		}
+		orders[fmt.Sprint(ids)] = true
+	}
+
+	if len(orders) < 2 {
+		t.Fatalf("the voters should be shown different orders")
+	}
+}
+
+func TestShuffleOptionsOwner(t *testing.T) {
+	storagePoll := &storage.Poll{ID: 7, UserID: 1, ShuffleOptions: true}
+
+	storageOptions := shuffleTestOptions()
+	shuffleOptions(storagePoll, storageOptions, 1)
+	if !slices.Equal(optionIDs(storageOptions), optionIDs(shuffleTestOptions())) {
+		t.Fatalf("the owner should see the original order, got %v", optionIDs(storageOptions))
+	}
+}
+
+func TestShuffleOptionsDisabled(t *testing.T) {
+	storagePoll := &storage.Poll{ID: 7, UserID: 1}
+
+	for u := entities.UserID(2); u < 20; u++ {
+		storageOptions := shuffleTestOptions()
+		shuffleOptions(storagePoll, storageOptions, u)
+		if !slices.Equal(optionIDs(storageOptions), optionIDs(shuffleTestOptions())) {
+			t.Fatalf("the options shouldn't be shuffled, got %v", optionIDs(storageOptions))
+		}
+	}
+}
+
+// a newly added option doesn't move the others around
+func TestShuffleOptionsStableOnNewOption(t *testing.T) {
+	storagePoll := &storage.Poll{ID: 7, UserID: 1, ShuffleOptions: true}
+
+	for u := entities.UserID(2); u < 50; u++ {
+		before := shuffleTestOptions()
+		shuffleOptions(storagePoll, before, u)
+
+		after := append(shuffleTestOptions(), storage.PollOption{ID: 16, Sort: 6})
+		shuffleOptions(storagePoll, after, u)
+
+		kept := slices.DeleteFunc(slices.Clone(after), func(so storage.PollOption) bool { return so.ID == 16 })
+		if !slices.Equal(optionIDs(before), optionIDs(kept)) {
+			t.Fatalf("the relative order of the voter %d changed from %v to %v", u, optionIDs(before), optionIDs(after))
+		}
+	}
+}
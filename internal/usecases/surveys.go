@@ -0,0 +1,514 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+	"go.uber.org/zap"
+)
+
+// Surveys groups the polls as the ordered questions of a single form, the answers are
+// submitted all together and cast as the votes (or skips) of the underlying polls
+type Surveys interface {
+	CreateSurvey(context.Context, *entities.Survey) error
+	GetSurvey(ctx context.Context, s entities.SurveyID, u entities.UserID) (*entities.Survey, error)
+	SubmitResponse(ctx context.Context, s entities.SurveyID, u entities.UserID, answers []entities.SurveyAnswer) error
+	Statistics(ctx context.Context, s entities.SurveyID, owner entities.UserID) (*entities.SurveyStatistics, error)
+}
+
+func NewSurveys(cfg *Config, logger *zap.Logger, metrics *Metrics, auditor Auditor, moderator Moderator,
+	ps storage.Polls, ts storage.Tags, vs storage.Votes, gs storage.Groups, ss storage.Surveys) Surveys {
+	return &surveys{
+		pools:          NewPolls(cfg, logger, metrics, auditor, moderator, ps, ts, vs, gs).(*pools),
+		surveysStorage: ss,
+	}
+}
+
+// surveys shares the access rules and the ballots of the polls
+type surveys struct {
+	*pools
+	// storages
+	surveysStorage storage.Surveys
+}
+
+var (
+	ErrInvalidCreateSurveyArguments = errors.New("")
+	ErrCreateSurveyPollNotExists    = errors.New("")
+)
+
+const maxSurveyQuestions = 20
+
+func (s *surveys) CreateSurvey(ctx context.Context, survey *entities.Survey) (err error) {
+	ctx, span := tracer.Start(ctx, "surveys.CreateSurvey")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if len(survey.Title) == 0 || len(survey.Questions) == 0 || len(survey.Questions) > maxSurveyQuestions {
+			return ErrInvalidCreateSurveyArguments
+		}
+	}
+
+	storageQuestions := make([]storage.SurveyQuestion, 0, len(survey.Questions))
+	storageRules := make([]storage.SurveyRule, 0)
+	for index := range survey.Questions {
+		question := &survey.Questions[index]
+		question.Position = index + 1 // the order of the questions is the order they are given
+
+		storagePoll, err := s.ownedPoll(ctx, question.PollID, survey.UserID)
+		if err != nil {
+			return err
+		} else if storagePoll == nil {
+			return ErrCreateSurveyPollNotExists
+		}
+
+		storageOptions, err := s.sortedOptions(ctx, question.PollID)
+		if err != nil {
+			return err
+		}
+
+		rules, err := surveyRules(question, storagePoll, storageOptions, len(survey.Questions))
+		if err != nil {
+			return err
+		}
+		storageRules = append(storageRules, rules...)
+
+		storageQuestions = append(storageQuestions, storage.SurveyQuestion{
+			Position: question.Position,
+			PollID:   int64(question.PollID),
+		})
+	}
+
+	tx, err := s.surveysStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	surveyID, err := s.surveysStorage.CreateSurvey(ctx, tx, &storage.Survey{
+		UserID: int64(survey.UserID),
+		Title:  survey.Title,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.surveysStorage.CreateSurveyQuestions(ctx, tx, surveyID, storageQuestions)
+	if err != nil {
+		if errors.Is(err, storage.ErrCreateSurveyQuestionsDuplicate) {
+			return ErrInvalidCreateSurveyArguments
+		}
+		return err
+	}
+
+	err = s.surveysStorage.CreateSurveyRules(ctx, tx, surveyID, storageRules)
+	if err != nil {
+		return err
+	}
+
+	err = s.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    survey.UserID,
+		Action:     entities.AuditActionCreateSurvey,
+		TargetType: entities.AuditTargetSurvey,
+		TargetID:   surveyID,
+		Metadata:   map[string]any{"title": survey.Title, "questions": len(storageQuestions), "rules": len(storageRules)},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	survey.ID = entities.SurveyID(surveyID)
+	return nil
+}
+
+// surveyRules validates the skip logic of the question, the options of the rules are resolved by their IDs
+// or by their indexes in the original order, never the shuffled one of a voter. The anonymous polls can't have rules, the path
+// of the response is kept per respondent so it would reveal the chosen option.
+func surveyRules(question *entities.SurveyQuestion, storagePoll *storage.Poll,
+	storageOptions []storage.PollOption, questions int) ([]storage.SurveyRule, error) {
+	if storagePoll.Anonymous && len(question.Rules) != 0 {
+		return nil, ErrInvalidCreateSurveyArguments
+	}
+
+	indexes := make(map[entities.PollOptionID]int, len(storageOptions))
+	for index, so := range storageOptions {
+		indexes[entities.PollOptionID(so.ID)] = index
+	}
+
+	result := make([]storage.SurveyRule, 0, len(question.Rules))
+	jumps := make(map[int]bool, len(question.Rules))
+	for _, rule := range question.Rules {
+		option, exists := rule.Option, rule.Option >= 0 && rule.Option < len(storageOptions)
+		if rule.OptionID != 0 {
+			option, exists = indexes[rule.OptionID]
+		}
+
+		// jumping backward could loop forever, so only the later questions are allowed
+		if !exists || jumps[option] ||
+			(rule.JumpTo != 0 && (rule.JumpTo <= question.Position || rule.JumpTo > questions)) {
+			return nil, ErrInvalidCreateSurveyArguments
+		}
+		jumps[option] = true
+
+		result = append(result, storage.SurveyRule{
+			Position:   question.Position,
+			OptionSort: storageOptions[option].Sort,
+			JumpTo:     rule.JumpTo,
+		})
+	}
+
+	return result, nil
+}
+
+var (
+	ErrInvalidGetSurveyArguments = errors.New("")
+	ErrGetSurveySurveyNotExists  = errors.New("")
+)
+
+// GetSurvey retrieves the survey with its polls, the user must have access to every one of them
+func (s *surveys) GetSurvey(ctx context.Context, surveyID entities.SurveyID, u entities.UserID) (result *entities.Survey, err error) {
+	ctx, span := tracer.Start(ctx, "surveys.GetSurvey")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if surveyID < 0 {
+			return nil, ErrInvalidGetSurveyArguments
+		}
+	}
+
+	storageSurvey, storageQuestions, rules, err := s.loadSurvey(ctx, surveyID)
+	if err != nil {
+		return nil, err
+	} else if storageSurvey == nil {
+		return nil, ErrGetSurveySurveyNotExists
+	}
+
+	storagePolls := make([]storage.Poll, 0, len(storageQuestions))
+	for _, storageQuestion := range storageQuestions {
+		storagePoll, err := s.accessiblePoll(ctx, entities.PollID(storageQuestion.PollID), u)
+		if err != nil {
+			return nil, err
+		} else if storagePoll == nil {
+			return nil, ErrGetSurveySurveyNotExists
+		}
+		storagePolls = append(storagePolls, *storagePoll)
+	}
+
+	polls, err := hydratePolls(ctx, s.pollsStorage, storagePolls, u)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &entities.Survey{
+		ID:        surveyID,
+		UserID:    entities.UserID(storageSurvey.UserID),
+		Title:     storageSurvey.Title,
+		Questions: make([]entities.SurveyQuestion, 0, len(storageQuestions)),
+		CreatedAt: storageSurvey.CreatedAt,
+	}
+
+	for index, storageQuestion := range storageQuestions {
+		question := entities.SurveyQuestion{
+			Position: storageQuestion.Position,
+			PollID:   entities.PollID(storageQuestion.PollID),
+			Poll:     &polls[index],
+		}
+		question.Rules = viewerRules(polls[index].Options, rules[storageQuestion.Position])
+		result.Questions = append(result.Questions, question)
+	}
+
+	return result, nil
+}
+
+// viewerRules turns the jumps of the question (by the sorts of the options) into the rules of the viewer,
+// the indexes are in the order the viewer is shown the options which may be shuffled
+func viewerRules(options []entities.PollOption, jumps map[int]int) []entities.SurveyRule {
+	var result []entities.SurveyRule
+	for index, option := range options {
+		if jumpTo, exists := jumps[option.Sort]; exists {
+			result = append(result, entities.SurveyRule{OptionID: option.ID, Option: index, JumpTo: jumpTo})
+		}
+	}
+	return result
+}
+
+var (
+	ErrInvalidSubmitSurveyArguments = errors.New("")
+	ErrSubmitSurveySurveyNotExists  = errors.New("")
+	ErrSubmitSurveyPollClosed       = errors.New("")
+	ErrSubmitSurveyAlreadyResponded = errors.New("")
+)
+
+// surveyStep is a question on the path of the response, a nil choice means skipped. The polls acted on
+// apart from the survey (e.g. through the feed) are kept on the path, but nothing is cast on them again.
+type surveyStep struct {
+	position int
+	poll     *storage.Poll
+	choice   *ballotChoice
+	acted    bool
+}
+
+// SubmitResponse walks the questions following the skip logic and casts all of the answers in one
+// transaction, the questions on the path without an answer are skipped and answering any question
+// off the path is rejected. Only the polls the user hasn't acted on yet are cast, the others keep
+// their earlier votes.
+func (s *surveys) SubmitResponse(ctx context.Context, surveyID entities.SurveyID, u entities.UserID, answers []entities.SurveyAnswer) (err error) {
+	ctx, span := tracer.Start(ctx, "surveys.SubmitResponse")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if surveyID < 0 || len(answers) > maxSurveyQuestions {
+			return ErrInvalidSubmitSurveyArguments
+		}
+	}
+
+	storageSurvey, storageQuestions, rules, err := s.loadSurvey(ctx, surveyID)
+	if err != nil {
+		return err
+	} else if storageSurvey == nil || len(storageQuestions) == 0 {
+		return ErrSubmitSurveySurveyNotExists
+	}
+
+	ballots := make(map[int]*entities.Ballot, len(answers))
+	for _, answer := range answers {
+		if _, duplicated := ballots[answer.Position]; duplicated {
+			return ErrInvalidSubmitSurveyArguments
+		}
+		ballots[answer.Position] = answer.Ballot
+	}
+
+	actedAt := time.Now()
+	steps, votes, casts := make([]surveyStep, 0, len(storageQuestions)), 0, 0
+	for position := 1; position != 0 && position <= len(storageQuestions); {
+		pollID := entities.PollID(storageQuestions[position-1].PollID)
+		storagePoll, err := s.accessiblePoll(ctx, pollID, u)
+		if err != nil {
+			return err
+		} else if storagePoll == nil {
+			return ErrSubmitSurveySurveyNotExists
+		} else if poll := pollEntity(storagePoll); !poll.IsPublished() || poll.IsClosed(actedAt) {
+			return ErrSubmitSurveyPollClosed // the drafts aren't open yet
+		}
+
+		step, next := surveyStep{position: position, poll: storagePoll}, position+1
+		if step.acted, err = s.hasActed(ctx, storagePoll, u); err != nil {
+			return err
+		} else if !step.acted {
+			casts++ // the skips are counted by the daily limit the same as the votes
+		}
+
+		if ballot := ballots[position]; ballot != nil {
+			step.choice, err = s.resolveBallot(ctx, storagePoll, ballot, u)
+			if err != nil {
+				if errors.Is(err, errInvalidBallot) {
+					return ErrInvalidSubmitSurveyArguments
+				}
+				return err
+			}
+
+			votes++
+			if step.choice.option != nil { // the skip logic is only based on the real options
+				if jumpTo, exists := rules[position][step.choice.option.Sort]; exists {
+					next = jumpTo
+				}
+			}
+		}
+		delete(ballots, position)
+
+		steps = append(steps, step)
+		position = next
+	}
+
+	if len(ballots) != 0 { // answers to the questions which have been jumped over
+		return ErrInvalidSubmitSurveyArguments
+	}
+
+	count, err := s.votesStorage.GetCurrentDateUserVoteCount(ctx, int64(u))
+	if err != nil { // log but continue
+		logger.FromContext(ctx, s.logger).Error("error retrieving user daily count", zap.Int64("user_id", int64(u)), zap.Error(err))
+	}
+
+	if count+int64(casts) > dailyUserVoteLimits {
+		s.metrics.LimitRejections.IncrementVector(sourceSurvey)
+		return ErrDailyUserVotesLimit
+	}
+
+	tx, err := s.votesStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	storageResponse := storage.SurveyResponse{SurveyID: int64(surveyID), UserID: int64(u)}
+	for _, step := range steps {
+		storageResponse.Answers = append(storageResponse.Answers, storage.SurveyAnswer{
+			Position: step.position, Answered: step.choice != nil,
+		})
+		if step.acted {
+			continue
+		}
+
+		if step.choice == nil {
+			err = s.skip(ctx, tx, step.poll, u)
+		} else {
+			_, err = s.vote(ctx, tx, step.poll, step.choice, u, actedAt)
+		}
+		if err != nil {
+			if errors.Is(err, storage.ErrCreateVoteAlreadyExists) { // acted on in the meantime
+				return ErrSubmitSurveyAlreadyResponded
+			}
+			return err
+		}
+	}
+
+	err = s.surveysStorage.CreateSurveyResponse(ctx, tx, &storageResponse)
+	if err != nil {
+		if errors.Is(err, storage.ErrCreateSurveyResponseAlreadyExists) {
+			return ErrSubmitSurveyAlreadyResponded
+		}
+		return err
+	}
+
+	err = s.auditor.Record(ctx, tx, &entities.AuditEvent{
+		ActorID:    u,
+		Action:     entities.AuditActionRespondSurvey,
+		TargetType: entities.AuditTargetSurvey,
+		TargetID:   int64(surveyID),
+		Metadata:   map[string]any{"questions": len(steps), "answered": votes},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	var untallied []*storage.Poll
+	var tallyErrs []error
+	for _, step := range steps {
+		if step.acted {
+			continue
+		}
+		if err := s.tallyAnonymous(ctx, step.poll, step.choice); err != nil {
+			untallied, tallyErrs = append(untallied, step.poll), append(tallyErrs, err)
+			continue
+		}
+
+		ballot := ballotVote
+		if step.choice == nil {
+			ballot = ballotSkip
+		}
+		s.metrics.Ballots.IncrementVector(step.poll.Type, ballot, sourceSurvey)
+	}
+
+	if len(untallied) != 0 { // the response is taken back too, so the revoked questions can be answered again
+		return s.revokeAnonymous(ctx, untallied, u, func(tx *sqlx.Tx) error {
+			return s.surveysStorage.DeleteSurveyResponse(ctx, tx, int64(surveyID), int64(u))
+		}, errors.Join(tallyErrs...))
+	}
+	return nil
+}
+
+var (
+	ErrInvalidSurveyStatisticsArguments = errors.New("")
+	ErrSurveyStatisticsSurveyNotExists  = errors.New("")
+	ErrSurveyAccessDenied               = errors.New("")
+)
+
+// Statistics reports the completion rate of every question to the owner of the survey
+func (s *surveys) Statistics(ctx context.Context, surveyID entities.SurveyID, owner entities.UserID) (result *entities.SurveyStatistics, err error) {
+	ctx, span := tracer.Start(ctx, "surveys.Statistics")
+	defer func() { tracing.End(span, err) }()
+
+	{ // validation
+		if surveyID < 0 {
+			return nil, ErrInvalidSurveyStatisticsArguments
+		}
+	}
+
+	storageSurvey, err := s.surveysStorage.GetSurveyByID(ctx, int64(surveyID))
+	if err != nil {
+		return nil, err
+	} else if storageSurvey == nil {
+		return nil, ErrSurveyStatisticsSurveyNotExists
+	} else if storageSurvey.UserID != int64(owner) {
+		return nil, ErrSurveyAccessDenied
+	}
+
+	storageQuestions, err := s.surveysStorage.GetSurveyQuestions(ctx, int64(surveyID))
+	if err != nil {
+		return nil, err
+	}
+
+	respondents, err := s.surveysStorage.GetSurveyRespondentsCount(ctx, int64(surveyID))
+	if err != nil {
+		return nil, err
+	}
+
+	storageStatistics, err := s.surveysStorage.GetSurveyQuestionsStatistics(ctx, int64(surveyID))
+	if err != nil {
+		return nil, err
+	}
+
+	result = &entities.SurveyStatistics{
+		SurveyID:    surveyID,
+		Respondents: respondents,
+		Questions:   make([]entities.SurveyQuestionStatistics, 0, len(storageStatistics)),
+	}
+
+	for _, statistics := range storageStatistics {
+		question := entities.SurveyQuestionStatistics{
+			Position: statistics.Position,
+			PollID:   entities.PollID(storageQuestions[statistics.Position-1].PollID),
+			Reached:  statistics.Reached,
+			Answered: statistics.Answered,
+		}
+		if statistics.Reached != 0 {
+			question.CompletionRate = float64(statistics.Answered) / float64(statistics.Reached)
+		}
+		result.Questions = append(result.Questions, question)
+	}
+
+	return result, nil
+}
+
+// loadSurvey retrieves the survey (nil if it doesn't exist), its questions in order and the
+// skip logic as the jumps of every position keyed by the option sort
+func (s *surveys) loadSurvey(ctx context.Context, surveyID entities.SurveyID) (
+	*storage.Survey, []storage.SurveyQuestion, map[int]map[int]int, error) {
+	storageSurvey, err := s.surveysStorage.GetSurveyByID(ctx, int64(surveyID))
+	if err != nil || storageSurvey == nil {
+		return nil, nil, nil, err
+	}
+
+	storageQuestions, err := s.surveysStorage.GetSurveyQuestions(ctx, int64(surveyID))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	storageRules, err := s.surveysStorage.GetSurveyRules(ctx, int64(surveyID))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rules := make(map[int]map[int]int, len(storageQuestions))
+	for _, rule := range storageRules {
+		if rules[rule.Position] == nil {
+			rules[rule.Position] = make(map[int]int)
+		}
+		rules[rule.Position][rule.OptionSort] = rule.JumpTo
+	}
+
+	return storageSurvey, storageQuestions, rules, nil
+}
@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mohammadne/porsesh/internal/entities"
+	"github.com/mohammadne/porsesh/internal/repository/storage"
+)
+
+func TestSurveyRules(t *testing.T) {
+	storageOptions := []storage.PollOption{{ID: 11, Sort: 1}, {ID: 12, Sort: 2}, {ID: 13, Sort: 3}}
+
+	tests := []struct {
+		name      string
+		anonymous bool
+		rules     []entities.SurveyRule
+		sorts     []int // the sorts of the options the valid rules are resolved to
+		valid     bool
+	}{
+		{name: "no rules", valid: true},
+		{name: "jump forward", rules: []entities.SurveyRule{{Option: 1, JumpTo: 3}}, sorts: []int{2}, valid: true},
+		{name: "end survey", rules: []entities.SurveyRule{{Option: 2, JumpTo: 0}}, sorts: []int{3}, valid: true},
+		{name: "by option id", rules: []entities.SurveyRule{{OptionID: 13, JumpTo: 3}, {OptionID: 11, JumpTo: 0}},
+			sorts: []int{3, 1}, valid: true},
+		{name: "option id over index", rules: []entities.SurveyRule{{OptionID: 12, Option: 0, JumpTo: 3}},
+			sorts: []int{2}, valid: true},
+		{name: "jump backward", rules: []entities.SurveyRule{{Option: 0, JumpTo: 1}}},
+		{name: "jump out of survey", rules: []entities.SurveyRule{{Option: 0, JumpTo: 4}}},
+		{name: "unknown option", rules: []entities.SurveyRule{{Option: 3, JumpTo: 3}}},
+		{name: "negative option", rules: []entities.SurveyRule{{Option: -1, JumpTo: 3}}},
+		{name: "unknown option id", rules: []entities.SurveyRule{{OptionID: 21, JumpTo: 3}}},
+		{name: "duplicated option", rules: []entities.SurveyRule{{Option: 0, JumpTo: 3}, {Option: 0, JumpTo: 0}}},
+		{name: "duplicated by option id", rules: []entities.SurveyRule{{Option: 0, JumpTo: 3}, {OptionID: 11, JumpTo: 0}}},
+		{name: "anonymous without rules", anonymous: true, valid: true},
+		{name: "anonymous with rules", anonymous: true, rules: []entities.SurveyRule{{Option: 1, JumpTo: 3}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			question := &entities.SurveyQuestion{Position: 2, PollID: 1, Rules: test.rules}
+			storagePoll := &storage.Poll{ID: 1, Anonymous: test.anonymous}
+
+			rules, err := surveyRules(question, storagePoll, storageOptions, 3)
+			if !test.valid {
+				if !errors.Is(err, ErrInvalidCreateSurveyArguments) {
+					t.Fatalf("survey rules should be rejected, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("survey rules has error %s", err.Error())
+			}
+			if len(rules) != len(test.rules) {
+				t.Fatalf("expected %d rules, got %d", len(test.rules), len(rules))
+			}
+			for index, rule := range rules {
+				if rule.Position != 2 || rule.OptionSort != test.sorts[index] || rule.JumpTo != test.rules[index].JumpTo {
+					t.Fatalf("unexpected rule %+v", rule)
+				}
+			}
+		})
+	}
+}
+
+// the owner creates the rules by the original order while the voters are shown their own shuffled order,
+// the rules must follow the options rather than their indexes
+func TestSurveyRulesOfShuffledOptions(t *testing.T) {
+	storagePoll := &storage.Poll{ID: 1, UserID: 1, ShuffleOptions: true}
+	storageOptions := []storage.PollOption{{ID: 11, Sort: 1}, {ID: 12, Sort: 2}, {ID: 13, Sort: 3}, {ID: 14, Sort: 4}}
+	question := &entities.SurveyQuestion{Position: 1, PollID: 1, Rules: []entities.SurveyRule{
+		{Option: 0, JumpTo: 3}, {OptionID: 13, JumpTo: 0},
+	}}
+
+	storageRules, err := surveyRules(question, storagePoll, storageOptions, 3)
+	if err != nil {
+		t.Fatalf("survey rules has error %s", err.Error())
+	}
+
+	jumps := make(map[int]int, len(storageRules))
+	for _, rule := range storageRules {
+		jumps[rule.OptionSort] = rule.JumpTo
+	}
+	expected := map[entities.PollOptionID]int{11: 3, 13: 0}
+
+	shuffled := 0
+	for u := entities.UserID(2); u < 50; u++ {
+		voterOptions := slices.Clone(storageOptions)
+		shuffleOptions(storagePoll, voterOptions, u)
+		if voterOptions[0].ID != 11 {
+			shuffled++
+		}
+
+		options := make([]entities.PollOption, 0, len(voterOptions))
+		for _, so := range voterOptions {
+			options = append(options, entities.PollOption{ID: entities.PollOptionID(so.ID), Sort: so.Sort})
+		}
+
+		rules := viewerRules(options, jumps)
+		if len(rules) != len(expected) {
+			t.Fatalf("expected %d rules, got %d", len(expected), len(rules))
+		}
+		for _, rule := range rules {
+			if options[rule.Option].ID != rule.OptionID {
+				t.Fatalf("the index %d of the rule should point to the option %d for the voter %d", rule.Option, rule.OptionID, u)
+			}
+			if jumpTo, exists := expected[rule.OptionID]; !exists || jumpTo != rule.JumpTo {
+				t.Fatalf("unexpected rule %+v for the voter %d", rule, u)
+			}
+		}
+	}
+
+	if shuffled == 0 {
+		t.Fatalf("the options should be shuffled for some of the voters")
+	}
+}
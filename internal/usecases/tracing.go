@@ -0,0 +1,8 @@
+package usecases
+
+import (
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
+)
+
+// tracer starts the spans of the usecase methods, named after their receiver type and the method
+var tracer = tracing.Tracer("github.com/mohammadne/porsesh/internal/usecases")
@@ -0,0 +1,63 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps the blobs by their keys, the keys are slash separated paths
+type Store interface {
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	Get(ctx context.Context, key string) (*Blob, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Blob is the retrieved content, the caller has to close the body
+type Blob struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+func New(cfg *Config) (Store, error) {
+	switch cfg.Backend {
+	case FilesystemBackend:
+		if cfg.Filesystem == nil {
+			return nil, errors.New("filesystem blob store is not configured")
+		}
+		return NewFilesystem(cfg.Filesystem)
+	case S3Backend:
+		if cfg.S3 == nil {
+			return nil, errors.New("s3 blob store is not configured")
+		}
+		return NewS3(cfg.S3)
+	}
+	return nil, fmt.Errorf("unknown blob store backend %q", cfg.Backend)
+}
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+(/[A-Za-z0-9_.-]+)*$`)
+
+// ValidKey tells whether the key is safe for every backend, it can't escape the root
+// of the store by any of its segments
+func ValidKey(key string) bool {
+	if len(key) == 0 || len(key) > 512 || !keyPattern.MatchString(key) {
+		return false
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,56 @@
+package blobs
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Backend string
+
+const (
+	FilesystemBackend Backend = "filesystem"
+	S3Backend         Backend = "s3"
+)
+
+type Config struct {
+	Backend    Backend `required:"true"`
+	Filesystem *FilesystemConfig
+	S3         *S3Config
+}
+
+type FilesystemConfig struct {
+	Directory string `required:"true"`
+}
+
+// S3Config is of any S3-compatible storage, the bucket is addressed in the path style
+type S3Config struct {
+	Endpoint  string // e.g. https://s3.eu-central-1.amazonaws.com or http://127.0.0.1:9000
+	Region    string
+	Bucket    string
+	AccessKey string `split_words:"true"`
+	SecretKey string `split_words:"true" secret:"true"`
+}
+
+func (cfg *Config) Validate() error {
+	switch cfg.Backend {
+	case FilesystemBackend:
+		if cfg.Filesystem == nil || len(cfg.Filesystem.Directory) == 0 {
+			return errors.New("directory of the filesystem backend is empty")
+		}
+	case S3Backend:
+		var errs []error
+		if cfg.S3 == nil || len(cfg.S3.Endpoint) == 0 {
+			errs = append(errs, errors.New("endpoint of the s3 backend is empty"))
+		}
+		if cfg.S3 == nil || len(cfg.S3.Bucket) == 0 {
+			errs = append(errs, errors.New("bucket of the s3 backend is empty"))
+		}
+		if cfg.S3 == nil || len(cfg.S3.AccessKey) == 0 || len(cfg.S3.SecretKey) == 0 {
+			errs = append(errs, errors.New("access key and secret key of the s3 backend should be given"))
+		}
+		return errors.Join(errs...)
+	default:
+		return fmt.Errorf("unknown blob store backend %q", cfg.Backend)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+type filesystem struct {
+	directory string
+}
+
+// NewFilesystem stores the blobs as the files under the directory, the content type
+// is derived from the extension of the key
+func NewFilesystem(cfg *FilesystemConfig) (Store, error) {
+	if len(cfg.Directory) == 0 {
+		return nil, errors.New("directory of the filesystem blob store should be given")
+	}
+
+	if err := os.MkdirAll(cfg.Directory, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating blob store directory: %v", err)
+	}
+
+	return &filesystem{directory: cfg.Directory}, nil
+}
+
+func (f *filesystem) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(f.directory, filepath.FromSlash(key)), nil
+}
+
+func (f *filesystem) Put(ctx context.Context, key, contentType string, body []byte) error {
+	name, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("error creating blob directory: %v", err)
+	}
+
+	// written aside and renamed, so the readers never see a partial blob
+	temporary, err := os.CreateTemp(filepath.Dir(name), ".blob-*")
+	if err != nil {
+		return fmt.Errorf("error creating blob file: %v", err)
+	}
+	defer os.Remove(temporary.Name()) // ignore error, it's gone after the rename
+
+	if _, err := temporary.Write(body); err != nil {
+		temporary.Close()
+		return fmt.Errorf("error writing blob file: %v", err)
+	}
+	if err := temporary.Close(); err != nil {
+		return fmt.Errorf("error closing blob file: %v", err)
+	}
+
+	if err := os.Rename(temporary.Name(), name); err != nil {
+		return fmt.Errorf("error renaming blob file: %v", err)
+	}
+
+	return nil
+}
+
+func (f *filesystem) Get(ctx context.Context, key string) (*Blob, error) {
+	name, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error opening blob file: %v", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error retrieving blob file info: %v", err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if len(contentType) == 0 {
+		contentType = "application/octet-stream"
+	}
+
+	return &Blob{Body: file, ContentType: contentType, Size: info.Size()}, nil
+}
+
+func (f *filesystem) Delete(ctx context.Context, key string) error {
+	name, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing blob file: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package blobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Service        = "s3"
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3TimeLayout     = "20060102T150405Z"
+	s3DateLayout     = "20060102"
+	s3RequestTimeout = 30 * time.Second
+)
+
+type s3 struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// NewS3 stores the blobs in the bucket of an S3-compatible storage (AWS, MinIO, ...),
+// the requests are signed by the signature version 4
+func NewS3(cfg *S3Config) (Store, error) {
+	if len(cfg.Endpoint) == 0 || len(cfg.Bucket) == 0 || len(cfg.AccessKey) == 0 || len(cfg.SecretKey) == 0 {
+		return nil, errors.New("endpoint, bucket and the keys of the s3 blob store should be given")
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || len(endpoint.Host) == 0 {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	region := cfg.Region
+	if len(region) == 0 {
+		region = "us-east-1"
+	}
+
+	return &s3{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		client:    &http.Client{Timeout: s3RequestTimeout},
+	}, nil
+}
+
+func (s *s3) Put(ctx context.Context, key, contentType string, body []byte) error {
+	response, err := s.do(ctx, http.MethodPut, key, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() // ignore error
+
+	if response.StatusCode != http.StatusOK {
+		return s.failure(response)
+	}
+
+	return nil
+}
+
+func (s *s3) Get(ctx context.Context, key string) (*Blob, error) {
+	response, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return &Blob{Body: response.Body, ContentType: response.Header.Get("Content-Type"), Size: response.ContentLength}, nil
+	case http.StatusNotFound:
+		response.Body.Close()
+		return nil, ErrNotFound
+	}
+
+	defer response.Body.Close() // ignore error
+	return nil, s.failure(response)
+}
+
+func (s *s3) Delete(ctx context.Context, key string) error {
+	response, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() // ignore error
+
+	// deleting a missing object isn't an error in S3 either
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		return s.failure(response)
+	}
+
+	return nil
+}
+
+func (s *s3) failure(response *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("unexpected s3 response status %d: %s", response.StatusCode, bytes.TrimSpace(message))
+}
+
+func (s *s3) do(ctx context.Context, method, key, contentType string, body []byte) (*http.Response, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	target := *s.endpoint
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + s.bucket + "/" + key
+
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating s3 request: %v", err)
+	}
+	if len(contentType) != 0 {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(request, body, time.Now().UTC())
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending s3 request: %v", err)
+	}
+
+	return response, nil
+}
+
+// sign adds the authorization of the signature version 4 to the request, the whole payload is signed
+func (s *s3) sign(request *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate, date := now.Format(s3TimeLayout), now.Format(s3DateLayout)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/" + s3Service + "/aws4_request"
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
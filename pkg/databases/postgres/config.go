@@ -1,9 +1,31 @@
 package postgres
 
+import (
+	"errors"
+	"fmt"
+)
+
 type Config struct {
 	Host     string `required:"true"`
 	Port     int    `required:"true"`
 	User     string `required:"true"`
-	Password string `required:"true"`
+	Password string `required:"true" secret:"true"`
 	Database string `required:"true"`
 }
+
+func (cfg *Config) Validate() error {
+	var errs []error
+	if len(cfg.Host) == 0 {
+		errs = append(errs, errors.New("host is empty"))
+	}
+	if cfg.Port < 1 || cfg.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d is out of range", cfg.Port))
+	}
+	if len(cfg.User) == 0 {
+		errs = append(errs, errors.New("user is empty"))
+	}
+	if len(cfg.Database) == 0 {
+		errs = append(errs, errors.New("database is empty"))
+	}
+	return errors.Join(errs...)
+}
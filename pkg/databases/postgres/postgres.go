@@ -2,13 +2,19 @@ package postgres
 
 import (
 	"context"
+	"database/sql/driver"
 	"fmt"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+	"github.com/mohammadne/porsesh/pkg/observability/tracing"
 )
 
 type Postgres struct {
@@ -22,21 +28,27 @@ type Vectors struct {
 }
 
 const (
-	driver           = "postgres"
+	driverName       = "postgres"
 	pingTimeout      = time.Second * 20
 	vectorNamePrefix = "postgres"
 )
 
-func Open(cfg *Config, namespace, subsystem string) (*Postgres, error) {
+func Open(cfg *Config, registry *metrics.Registry, namespace, subsystem string) (*Postgres, error) {
 	connString := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database,
 	)
 
-	database, err := sqlx.Open(driver, connString)
+	// every statement is traced with its text, as a child of the span of the storage method running it
+	db, err := otelsql.Open(driverName, connString,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithAttributesGetter(statementAttributes),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error while opening connection to postgresql: %v", err)
 	}
+	database := sqlx.NewDb(db, driverName)
 
 	database.SetMaxIdleConns(0)
 
@@ -50,14 +62,14 @@ func Open(cfg *Config, namespace, subsystem string) (*Postgres, error) {
 	{
 		counterName := vectorNamePrefix + "_counter"
 		counterLabels := []string{"table", "method", "status"}
-		vectors.Counter, err = metrics.RegisterCounter(counterName, namespace, subsystem, counterLabels)
+		vectors.Counter, err = registry.RegisterCounter(counterName, namespace, subsystem, counterLabels)
 		if err != nil {
 			return nil, fmt.Errorf("error while registering counter vector: %v", err)
 		}
 
 		histogramName := vectorNamePrefix + "_histogram"
 		histogramLabels := []string{"table", "method"}
-		vectors.Histogram, err = metrics.RegisterHistogram(histogramName, namespace, subsystem, histogramLabels)
+		vectors.Histogram, err = registry.RegisterHistogram(histogramName, namespace, subsystem, histogramLabels)
 		if err != nil {
 			return nil, fmt.Errorf("error while registering histogram vector: %v", err)
 		}
@@ -68,6 +80,22 @@ func Open(cfg *Config, namespace, subsystem string) (*Postgres, error) {
 	return r, nil
 }
 
+func statementAttributes(_ context.Context, _ otelsql.Method, query string, _ []driver.NamedValue) []attribute.KeyValue {
+	if len(query) == 0 {
+		return nil
+	}
+	return []attribute.KeyValue{attribute.String("db.statement", query)}
+}
+
+var tracer = tracing.Tracer("github.com/mohammadne/porsesh/pkg/databases/postgres")
+
+// StartSpan starts the span of a storage method on the table, the statements it runs become its children.
+// The span has to be ended by tracing.End along with the outcome of the method.
+func (p *Postgres) StartSpan(ctx context.Context, table, method string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, table+"."+method, trace.WithAttributes(
+		semconv.DBSystemNamePostgreSQL, semconv.DBCollectionName(table), semconv.DBOperationName(method)))
+}
+
 const (
 	ForeignKeyViolatedCode       = "23503"
 	UniqueConstraintViolatedCode = "23505"
@@ -1,14 +1,41 @@
 package redis
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
 	"time"
 )
 
+// Config is of the optional Redis, an empty address leaves it out. The credentials are only
+// given to the servers which require the authentication.
 type Config struct {
-	Address  string        `required:"true"`
-	Username string        `required:"true"`
-	Password string        `required:"true"`
-	DB       int           `required:"true"`
+	Address  string
+	Username string
+	Password string        `secret:"true"`
+	DB       int           `default:"0" required:"false"`
 	Timeout  time.Duration `default:"5s" required:"false"`
 	PoolSize int           `default:"10" required:"false"`
 }
+
+func (cfg *Config) Validate() error {
+	var errs []error
+	if len(cfg.Address) != 0 {
+		if _, port, err := net.SplitHostPort(cfg.Address); err != nil {
+			errs = append(errs, fmt.Errorf("address %q is not host:port", cfg.Address))
+		} else if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+			errs = append(errs, fmt.Errorf("port %q is out of range", port))
+		}
+	}
+	if cfg.DB < 0 {
+		errs = append(errs, fmt.Errorf("db %d is negative", cfg.DB))
+	}
+	if cfg.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout should be positive"))
+	}
+	if cfg.PoolSize <= 0 {
+		errs = append(errs, errors.New("pool size should be positive"))
+	}
+	return errors.Join(errs...)
+}
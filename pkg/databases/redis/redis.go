@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -27,6 +28,11 @@ func Open(config *Config) (*Redis, error) {
 		WriteTimeout: config.Timeout,
 	})
 
+	// the commands are traced by the global tracer provider, as children of the spans of their callers
+	if err := redisotel.InstrumentTracing(r.Client); err != nil {
+		return nil, fmt.Errorf("error instrumenting the redis client: %v", err)
+	}
+
 	pingCtx, cf := context.WithTimeout(context.Background(), config.Timeout)
 	defer cf()
 
@@ -37,3 +43,8 @@ func Open(config *Config) (*Redis, error) {
 
 	return &r, nil
 }
+
+// Check pings the server, it fits as the health checker of the client
+func (r *Redis) Check(ctx context.Context) error {
+	return r.Client.Ping(ctx).Err()
+}
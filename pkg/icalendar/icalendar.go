@@ -0,0 +1,68 @@
+package icalendar
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	productID  = "-//porsesh//schedule polls//EN"
+	timeLayout = "20060102T150405Z"
+	lineLength = 75 // octets, the longer lines are folded
+)
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\r\n", `\n`, "\n", `\n`)
+
+type Event struct {
+	UID         string // globally unique, usually in the form of an email address
+	Summary     string
+	Description string // optional
+	StartsAt    time.Time
+	EndsAt      time.Time
+}
+
+// Encode returns the RFC 5545 calendar of the events, the times are written in UTC
+// so the calendar doesn't need to carry the definitions of the time zones
+func Encode(events ...Event) []byte {
+	var builder strings.Builder
+	stamp := time.Now().UTC().Format(timeLayout)
+
+	writeLine(&builder, "BEGIN:VCALENDAR")
+	writeLine(&builder, "VERSION:2.0")
+	writeLine(&builder, "PRODID:"+productID)
+	writeLine(&builder, "CALSCALE:GREGORIAN")
+	writeLine(&builder, "METHOD:PUBLISH")
+	for _, event := range events {
+		writeLine(&builder, "BEGIN:VEVENT")
+		writeLine(&builder, "UID:"+textEscaper.Replace(event.UID))
+		writeLine(&builder, "DTSTAMP:"+stamp)
+		writeLine(&builder, "DTSTART:"+event.StartsAt.UTC().Format(timeLayout))
+		writeLine(&builder, "DTEND:"+event.EndsAt.UTC().Format(timeLayout))
+		writeLine(&builder, "SUMMARY:"+textEscaper.Replace(event.Summary))
+		if len(event.Description) != 0 {
+			writeLine(&builder, "DESCRIPTION:"+textEscaper.Replace(event.Description))
+		}
+		writeLine(&builder, "END:VEVENT")
+	}
+	writeLine(&builder, "END:VCALENDAR")
+
+	return []byte(builder.String())
+}
+
+// writeLine folds the line into the ones of at most lineLength octets without splitting
+// the multi-byte characters, the continuation lines start with a space
+func writeLine(builder *strings.Builder, line string) {
+	limit := lineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		builder.WriteString(line[:cut])
+		builder.WriteString("\r\n ")
+		line, limit = line[cut:], lineLength-1 // the leading space counts
+	}
+	builder.WriteString(line)
+	builder.WriteString("\r\n")
+}
@@ -0,0 +1,96 @@
+package icalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEncode(t *testing.T) {
+	startsAt := time.Date(2026, 10, 20, 10, 0, 0, 0, time.FixedZone("Europe/Berlin", 2*60*60))
+	calendar := string(Encode(Event{
+		UID:         "abc-1@porsesh",
+		Summary:     "Team sync; room 1, floor 2",
+		Description: `bring \ laptops` + "\nand snacks",
+		StartsAt:    startsAt,
+		EndsAt:      startsAt.Add(time.Hour),
+	}))
+
+	if !strings.HasSuffix(calendar, "\r\n") {
+		t.Fatalf("the lines should end by CRLF")
+	}
+
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VEVENT\r\n",
+		"UID:abc-1@porsesh\r\n",
+		"DTSTART:20261020T080000Z\r\n", // in UTC
+		"DTEND:20261020T090000Z\r\n",
+		`SUMMARY:Team sync\; room 1\, floor 2` + "\r\n",
+		`DESCRIPTION:bring \\ laptops\nand snacks` + "\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(calendar, expected) {
+			t.Fatalf("calendar should contain %q:\n%s", expected, calendar)
+		}
+	}
+}
+
+func TestEncodeWithoutDescription(t *testing.T) {
+	calendar := string(Encode(Event{UID: "abc-1@porsesh", Summary: "sync", StartsAt: time.Now(), EndsAt: time.Now()}))
+	if strings.Contains(calendar, "DESCRIPTION:") {
+		t.Fatalf("the empty description should be left out:\n%s", calendar)
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "short", line: "SUMMARY:sync"},
+		{name: "exact", line: "SUMMARY:" + strings.Repeat("a", lineLength-len("SUMMARY:"))},
+		{name: "long", line: "DESCRIPTION:" + strings.Repeat("a", 200)},
+		{name: "multi-byte", line: "DESCRIPTION:" + strings.Repeat("نظرسنجی ", 30)},
+		{name: "emoji", line: "SUMMARY:" + strings.Repeat("🗳", 40)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var builder strings.Builder
+			writeLine(&builder, test.line)
+			folded := builder.String()
+
+			if !strings.HasSuffix(folded, "\r\n") {
+				t.Fatalf("the line should end by CRLF")
+			}
+
+			lines := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+			if len(test.line) <= lineLength && len(lines) != 1 {
+				t.Fatalf("the line of %d octets shouldn't be folded", len(test.line))
+			}
+
+			var unfolded strings.Builder
+			for index, line := range lines {
+				if len(line) > lineLength {
+					t.Fatalf("line %d is of %d octets, longer than %d", index, len(line), lineLength)
+				}
+				if !utf8.ValidString(line) {
+					t.Fatalf("line %d splits a multi-byte character", index)
+				}
+				if index != 0 {
+					if !strings.HasPrefix(line, " ") {
+						t.Fatalf("continuation line %d should start by a space", index)
+					}
+					line = line[1:]
+				}
+				unfolded.WriteString(line)
+			}
+
+			if unfolded.String() != test.line {
+				t.Fatalf("unfolding should give back the line, got %q", unfolded.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// the raw HTML of the source is escaped by the renderer, the sanitizer is the second line of defense
+var renderer = goldmark.New(
+	goldmark.WithExtensions(
+		extension.Strikethrough,
+		extension.Linkify,
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+	),
+)
+
+var policy = newPolicy()
+
+// newPolicy allows the tags the renderer produces for the text formatting, lists, quotes, code and
+// tables. The links are limited to http(s) and mailto and they get rel="nofollow noopener".
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+
+	p.AllowElements("p", "br", "hr", "strong", "em", "del", "code", "pre", "blockquote",
+		"ul", "ol", "li", "h1", "h2", "h3", "h4", "h5", "h6",
+		"table", "thead", "tbody", "tr", "th", "td")
+	p.AllowAttrs("start").Matching(regexp.MustCompile(`^[0-9]+$`)).OnElements("ol")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|right|center)$`)).OnElements("th", "td")
+
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true) // comes along with noopener
+
+	return p
+}
+
+// Render converts the Markdown into sanitized HTML, there are never any scripts, styles or
+// event handlers in it
+func Render(source string) (string, error) {
+	var buffer bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buffer); err != nil {
+		return "", fmt.Errorf("error rendering markdown: %v", err)
+	}
+
+	return policy.Sanitize(buffer.String()), nil
+}
@@ -0,0 +1,100 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		contains  []string
+		forbidden []string
+	}{
+		{
+			name:     "formatting",
+			source:   "some **bold** and ~~old~~ text",
+			contains: []string{"<p>some <strong>bold</strong> and <del>old</del> text</p>"},
+		},
+		{
+			name:      "script",
+			source:    "before\n\n<script>alert(1)</script>\n\nafter",
+			contains:  []string{"<p>before</p>", "<p>after</p>"},
+			forbidden: []string{"<script", "alert(1)</script>"},
+		},
+		{
+			name:      "inline script",
+			source:    "text <script>alert(1)</script> text",
+			forbidden: []string{"<script"},
+		},
+		{
+			name:      "style",
+			source:    "<style>body { display: none }</style>\n\n<p style=\"color: red\">red</p>",
+			forbidden: []string{"<style", "style="},
+		},
+		{
+			name:      "event handler",
+			source:    `<img src="x.png" onerror="alert(1)"> <a href="https://example.com" onclick="alert(1)">link</a>`,
+			forbidden: []string{"onerror", "onclick", "<img"},
+		},
+		{
+			name:      "javascript link",
+			source:    "[click](javascript:alert(1))",
+			forbidden: []string{"javascript:", "href="},
+		},
+		{
+			name:      "javascript link in html",
+			source:    `<a href="javascript:alert(1)">click</a>`,
+			forbidden: []string{"javascript:"},
+		},
+		{
+			name:   "external link",
+			source: "[proposal](https://example.com/proposal)",
+			contains: []string{
+				`href="https://example.com/proposal"`, `rel="nofollow noopener"`, `target="_blank"`, ">proposal</a>",
+			},
+		},
+		{
+			name:     "linkify",
+			source:   "see https://example.com for more",
+			contains: []string{`href="https://example.com"`, `rel="nofollow noopener"`, `target="_blank"`},
+		},
+		{
+			name:      "relative link",
+			source:    "[results](/polls/1)",
+			contains:  []string{"<p>results</p>"}, // only the http(s) and mailto links are kept
+			forbidden: []string{"href="},
+		},
+		{
+			name:     "mailto link",
+			source:   "[mail](mailto:team@example.com)",
+			contains: []string{`href="mailto:team@example.com"`},
+		},
+		{
+			name:     "table",
+			source:   "| a | b |\n|:--|--:|\n| 1 | 2 |",
+			contains: []string{"<table>", `<th align="left">a</th>`, `<td align="right">2</td>`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			html, err := Render(test.source)
+			if err != nil {
+				t.Fatalf("render has error %s", err.Error())
+			}
+
+			for _, expected := range test.contains {
+				if !strings.Contains(html, expected) {
+					t.Fatalf("html should contain %q:\n%s", expected, html)
+				}
+			}
+			for _, unexpected := range test.forbidden {
+				if strings.Contains(html, unexpected) {
+					t.Fatalf("html shouldn't contain %q:\n%s", unexpected, html)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+)
+
+// Class tells whether the service can serve without the dependency
+type Class string
+
+const (
+	Critical    Class = "critical"     // the service isn't ready without it
+	NonCritical Class = "non_critical" // the service degrades without it, but it's still ready
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDown     Status = "down"
+	StatusDegraded Status = "degraded" // only some non-critical dependencies are down
+)
+
+// Checker reports the dependency as down by returning an error, it has to respect the deadline of the context
+type Checker func(ctx context.Context) error
+
+var ErrTimeout = errors.New("health check timed out")
+
+type dependency struct {
+	name    string
+	class   Class
+	timeout time.Duration
+	checker Checker
+}
+
+// Health checks the registered dependencies, the report is cached for a while
+// so the probes don't hammer the dependencies
+type Health struct {
+	cacheTTL time.Duration
+	gauge    metrics.Gauge
+
+	mutex        sync.Mutex // also serializes the checks, so the concurrent probes share one
+	dependencies []dependency
+	report       *Report
+}
+
+type Report struct {
+	Status       Status
+	Dependencies []DependencyReport // in the order of the registration
+	CheckedAt    time.Time
+}
+
+type DependencyReport struct {
+	Name     string
+	Class    Class
+	Status   Status
+	Duration time.Duration
+	Err      error
+}
+
+// Ready tells whether all the critical dependencies are up
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+const vectorName = "health_status"
+
+func New(registry *metrics.Registry, namespace, subsystem string, cacheTTL time.Duration) (*Health, error) {
+	gauge, err := registry.RegisterGauge(vectorName, namespace, subsystem, []string{"dependency", "class"})
+	if err != nil {
+		return nil, fmt.Errorf("error while registering health gauge: %v", err)
+	}
+
+	return &Health{cacheTTL: cacheTTL, gauge: gauge}, nil
+}
+
+// Register adds the dependency to the next checks, the checker is given the timeout as its deadline
+func (h *Health) Register(name string, class Class, timeout time.Duration, checker Checker) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.dependencies = append(h.dependencies, dependency{name: name, class: class, timeout: timeout, checker: checker})
+	h.report = nil // the cached report misses the new dependency
+}
+
+// Check returns the cached report if it's fresh, otherwise it checks all the dependencies concurrently.
+// The checks are detached from the cancellation of the caller, the report is shared by the other probes
+// so it's only bounded by the timeouts of the dependencies.
+func (h *Health) Check(ctx context.Context) *Report {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.report != nil && time.Since(h.report.CheckedAt) < h.cacheTTL {
+		return h.report
+	}
+
+	ctx = context.WithoutCancel(ctx) // keeps the values, e.g. the span of the probe
+	report := &Report{Status: StatusUp, Dependencies: make([]DependencyReport, len(h.dependencies)), CheckedAt: time.Now()}
+
+	var wg sync.WaitGroup
+	for index, dependency := range h.dependencies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Dependencies[index] = dependency.check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, dependency := range report.Dependencies {
+		if dependency.Status == StatusUp {
+			h.gauge.SetVector(1, dependency.Name, string(dependency.Class))
+			continue
+		}
+
+		h.gauge.SetVector(0, dependency.Name, string(dependency.Class))
+		if dependency.Class == Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	h.report = report
+	return report
+}
+
+func (d dependency) check(ctx context.Context) DependencyReport {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := make(chan error, 1) // buffered, so a checker ignoring the deadline doesn't leak
+	go func() { result <- d.checker(ctx) }()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ErrTimeout
+	}
+
+	report := DependencyReport{Name: d.name, Class: d.class, Status: StatusUp, Duration: time.Since(start)}
+	if err != nil {
+		report.Status, report.Err = StatusDown, err
+	}
+	return report
+}
@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
+)
+
+func newHealth(t *testing.T, cacheTTL time.Duration) *Health {
+	t.Helper()
+
+	health, err := New(metrics.NewRegistry(), "ns", "sub", cacheTTL)
+	if err != nil {
+		t.Fatalf("new health has error %s", err.Error())
+	}
+	return health
+}
+
+func up(context.Context) error { return nil }
+
+func down(context.Context) error { return errors.New("connection refused") }
+
+func TestCheckClasses(t *testing.T) {
+	tests := []struct {
+		name     string
+		critical Checker
+		optional Checker
+		status   Status
+	}{
+		{name: "all_up", critical: up, optional: up, status: StatusUp},
+		{name: "non_critical_down", critical: up, optional: down, status: StatusDegraded},
+		{name: "critical_down", critical: down, optional: up, status: StatusDown},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			health := newHealth(t, 0)
+			health.Register("postgres", Critical, time.Second, test.critical)
+			health.Register("redis", NonCritical, time.Second, test.optional)
+
+			report := health.Check(context.Background())
+			if report.Status != test.status {
+				t.Fatalf("status is %s, expected %s", report.Status, test.status)
+			}
+			if report.Ready() != (test.status != StatusDown) {
+				t.Fatalf("readiness is %v with the status %s", report.Ready(), report.Status)
+			}
+			if len(report.Dependencies) != 2 || report.Dependencies[0].Name != "postgres" {
+				t.Fatalf("dependencies are %v, expected in the order of the registration", report.Dependencies)
+			}
+		})
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	health := newHealth(t, 0)
+	health.Register("broker", Critical, 10*time.Millisecond, func(context.Context) error {
+		time.Sleep(time.Second) // ignores the deadline
+		return nil
+	})
+
+	start := time.Now()
+	report := health.Check(context.Background())
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatal("check waited for the checker beyond its timeout")
+	}
+	if !errors.Is(report.Dependencies[0].Err, ErrTimeout) {
+		t.Fatalf("error is %v, expected the timeout", report.Dependencies[0].Err)
+	}
+}
+
+func TestCheckCache(t *testing.T) {
+	health := newHealth(t, time.Minute)
+
+	calls := 0
+	health.Register("postgres", Critical, time.Second, func(context.Context) error {
+		calls++
+		return nil
+	})
+
+	health.Check(context.Background())
+	health.Check(context.Background())
+	if calls != 1 {
+		t.Fatalf("checker is called %d times, expected the cached report to be reused", calls)
+	}
+}
+
+func TestCheckDetached(t *testing.T) {
+	health := newHealth(t, time.Minute)
+	health.Register("postgres", Critical, time.Second, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // the probe is gone before the check
+
+	report := health.Check(ctx)
+	if report.Status != StatusUp {
+		t.Fatalf("status is %s, the cancellation of the probe shouldn't fail the check: %v",
+			report.Status, report.Dependencies[0].Err)
+	}
+
+	if cached := health.Check(context.Background()); cached != report {
+		t.Fatalf("the report of the canceled probe should be cached for the others")
+	}
+}
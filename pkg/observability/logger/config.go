@@ -1,11 +1,23 @@
 package logger
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
 type LoggerType string
 
 const (
 	StandardLogger LoggerType = "std"
 	GraylogLogger  LoggerType = "graylog"
 	SentryLogger   LoggerType = "sentry"
+	FileLogger     LoggerType = "file"
+	OTLPLogger     LoggerType = "otlp"
 )
 
 type Config struct {
@@ -13,14 +25,119 @@ type Config struct {
 	Loggers     []LoggerType `required:"true"`
 	Graylog     *GraylogConfig
 	Sentry      *SentryConfig
+	File        *FileConfig
+	OTLP        *OTLPConfig
+	Redaction   *RedactionConfig
 }
 
+// The levels of the sinks are optional, the sinks without a level follow the level of the logger
+// which can be changed at runtime
+
 type GraylogConfig struct {
-	URI      string `required:"true"`
+	URI      string `required:"true"` // udp://, tcp:// or http(s):// for GELF over HTTP, e.g. http://graylog:12201/gelf
 	Facility string `required:"true"`
+	Level    string
+	// the records are posted over HTTP in the background, the ones overflowing the queue are dropped.
+	// The zero values fall back to the defaults.
+	QueueSize     int           `split_words:"true" default:"1000"`
+	BatchSize     int           `split_words:"true" default:"1"` // more than one needs the bulk receiving of the input
+	FlushInterval time.Duration `split_words:"true" default:"1s"`
 }
 
 type SentryConfig struct {
-	URI  string `required:"true"`
+	URI  string `required:"true" secret:"true"` // the DSN carries the key of the project
 	Tags map[string]string
 }
+
+// FileConfig is of the rotated file, it's rotated by its size and periodically
+type FileConfig struct {
+	Path        string
+	MaxSize     int           `split_words:"true" default:"100"` // megabytes
+	MaxBackups  int           `split_words:"true" default:"10"`
+	MaxAge      time.Duration `split_words:"true" default:"168h"` // of the backups, rounded down to days
+	RotateEvery time.Duration `split_words:"true" default:"24h"`  // zero only rotates by the size
+	Compress    bool          `default:"true"`                    // the backups by gzip
+	Level       string
+}
+
+type OTLPConfig struct {
+	Endpoint string            // host:port of the collector, over HTTP
+	Insecure bool              `default:"false"`
+	Headers  map[string]string `secret:"true"` // e.g. the authorization of the vendor, as key:value,key:value
+	Level    string
+}
+
+// RedactionConfig extends the default patterns of the policy, they're regular expressions
+// separated by commas, so they can't contain commas themselves
+type RedactionConfig struct {
+	Fields []string // matched against the names of the fields
+	Values []string // matched against the string values and messages, the matches are replaced
+}
+
+// Validate checks the sub-configs of the enabled loggers, the others are left as they are
+func (cfg *Config) Validate() error {
+	var errs []error
+	if len(cfg.Loggers) == 0 {
+		errs = append(errs, errors.New("no logger is enabled"))
+	}
+
+	for _, logger := range cfg.Loggers {
+		switch logger {
+		case StandardLogger:
+		case GraylogLogger:
+			if cfg.Graylog == nil || len(cfg.Graylog.URI) == 0 {
+				errs = append(errs, errors.New("uri of the graylog logger is empty"))
+				break
+			}
+			if uri, err := url.Parse(cfg.Graylog.URI); err != nil {
+				errs = append(errs, fmt.Errorf("invalid graylog uri: %v", err))
+			} else if !slices.Contains([]string{"udp", "tcp", "http", "https"}, uri.Scheme) {
+				errs = append(errs, fmt.Errorf("unknown graylog scheme %q, expected udp, tcp, http or https", uri.Scheme))
+			} else if uri.Scheme == "http" || uri.Scheme == "https" {
+				if cfg.Graylog.QueueSize < 0 || cfg.Graylog.BatchSize < 0 || cfg.Graylog.FlushInterval < 0 {
+					errs = append(errs, errors.New("queue size, batch size and flush interval of the graylog logger can't be negative"))
+				}
+			}
+			errs = append(errs, validLevel("graylog", cfg.Graylog.Level))
+		case SentryLogger:
+			if cfg.Sentry == nil || len(cfg.Sentry.URI) == 0 {
+				errs = append(errs, errors.New("uri of the sentry logger is empty"))
+			}
+		case FileLogger:
+			if cfg.File == nil || len(cfg.File.Path) == 0 {
+				errs = append(errs, errors.New("path of the file logger is empty"))
+				break
+			}
+			if cfg.File.MaxSize <= 0 {
+				errs = append(errs, errors.New("max size of the file logger should be positive"))
+			}
+			if cfg.File.MaxBackups < 0 || cfg.File.MaxAge < 0 || cfg.File.RotateEvery < 0 {
+				errs = append(errs, errors.New("max backups, max age and rotate every of the file logger can't be negative"))
+			}
+			errs = append(errs, validLevel("file", cfg.File.Level))
+		case OTLPLogger:
+			if cfg.OTLP == nil || len(cfg.OTLP.Endpoint) == 0 {
+				errs = append(errs, errors.New("endpoint of the otlp logger is empty"))
+				break
+			}
+			errs = append(errs, validLevel("otlp", cfg.OTLP.Level))
+		default:
+			errs = append(errs, fmt.Errorf("unknown logger type %q", logger))
+		}
+	}
+
+	if cfg.Redaction != nil {
+		if _, err := NewPolicy(cfg.Redaction.Fields, cfg.Redaction.Values); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validLevel(logger, level string) error {
+	if _, err := sinkLevel(level, zap.NewAtomicLevel()); err != nil {
+		return fmt.Errorf("invalid level of the %s logger: %v", logger, err)
+	}
+	return nil
+}
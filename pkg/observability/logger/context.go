@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx which carries the given request-scoped logger
+func NewContext(ctx context.Context, lg *zap.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, lg)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, or the fallback if there is none (e.g. for
+// the background jobs). The returned logger is annotated with the current span, if it's recorded.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	lg, ok := ctx.Value(contextKey{}).(*zap.Logger)
+	if !ok {
+		lg = fallback
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return lg
+	}
+
+	return lg.With(zap.String("trace_id", spanContext.TraceID().String()), zap.String("span_id", spanContext.SpanID().String()))
+}
+
+// With returns a copy of ctx whose request-scoped logger is annotated with the given fields, the fields only
+// known deeper in the request (e.g. once its body is bound) are added by it. ctx is kept if it carries no logger.
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	lg, ok := ctx.Value(contextKey{}).(*zap.Logger)
+	if !ok || len(fields) == 0 {
+		return ctx
+	}
+	return NewContext(ctx, lg.With(fields...))
+}
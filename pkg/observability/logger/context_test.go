@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWith(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := NewContext(context.Background(), zap.New(core).With(zap.String("request_id", "abc")))
+
+	ctx = With(ctx, zap.String("user_id", "7"))
+	FromContext(ctx, zap.NewNop()).Info("vote")
+
+	fields := logs.All()[0].ContextMap()
+	if fields["request_id"] != "abc" || fields["user_id"] != "7" {
+		t.Fatalf("the record should carry the fields of the scoped logger and the added ones, got %v", fields)
+	}
+
+	// there is no scoped logger to annotate outside of the requests
+	background := context.Background()
+	if With(background, zap.String("user_id", "7")) != background {
+		t.Fatalf("the context without a logger should be kept")
+	}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func fileCore(cfg *Config, level zap.AtomicLevel) (zapcore.Core, error) {
+	if cfg.File == nil || len(cfg.File.Path) == 0 {
+		return nil, errors.New("the path of the file logger is not configured")
+	}
+
+	enabler, err := sinkLevel(cfg.File.Level, level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid level of the file logger: %v", err)
+	}
+
+	// the file is opened lazily by the writer, it's checked here to fail on the startup instead
+	if err := os.MkdirAll(filepath.Dir(cfg.File.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating the directory of the log file: %v", err)
+	}
+	file, err := os.OpenFile(cfg.File.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening the log file: %v", err)
+	}
+	file.Close()
+
+	writer := &lumberjack.Logger{
+		Filename:   cfg.File.Path,
+		MaxSize:    cfg.File.MaxSize,
+		MaxBackups: cfg.File.MaxBackups,
+		MaxAge:     int(cfg.File.MaxAge / (24 * time.Hour)),
+		Compress:   cfg.File.Compress,
+	}
+	if cfg.File.RotateEvery > 0 {
+		go rotate(writer, cfg.File.RotateEvery)
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(writer), enabler), nil
+}
+
+// rotate rotates the file periodically for the lifetime of the process, along with the rotations by its size
+func rotate(writer *lumberjack.Logger, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := writer.Rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "error rotating the log file: %v\n", err) // the logger can't log its own failure
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/Graylog2/go-gelf.v2/gelf"
+)
+
+const (
+	gelfHTTPTimeout = 5 * time.Second
+
+	// the defaults of the queue, in place of the zero values of the config
+	gelfQueueSize     = 1000
+	gelfBatchSize     = 1
+	gelfFlushInterval = time.Second
+)
+
+// gelfHTTPWriter posts the records as GELF messages to the HTTP input of Graylog, the same as the UDP and TCP
+// writers of go-gelf the whole record is the short message. The records are queued and posted in the
+// background so the callers never wait for Graylog, once the queue is full the new records are dropped.
+// Up to the batch size of the queued records are posted together, newline-delimited as the bulk receiving
+// of the input expects them.
+type gelfHTTPWriter struct {
+	uri       string
+	hostname  string
+	facility  string
+	client    *http.Client
+	batchSize int
+
+	queue   chan []byte
+	syncs   chan chan error
+	dropped atomic.Uint64
+}
+
+func newGELFHTTPWriter(uri, facility string, queueSize, batchSize int, flushInterval time.Duration) *gelfHTTPWriter {
+	if queueSize <= 0 {
+		queueSize = gelfQueueSize
+	}
+	if batchSize <= 0 {
+		batchSize = gelfBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = gelfFlushInterval
+	}
+
+	hostname, _ := os.Hostname()
+	w := &gelfHTTPWriter{
+		uri:       uri,
+		hostname:  hostname,
+		facility:  facility,
+		client:    &http.Client{Timeout: gelfHTTPTimeout},
+		batchSize: batchSize,
+		queue:     make(chan []byte, queueSize),
+		syncs:     make(chan chan error),
+	}
+	go w.run(flushInterval)
+	return w
+}
+
+func (w *gelfHTTPWriter) Write(p []byte) (int, error) {
+	message := gelf.Message{
+		Version:  "1.1",
+		Host:     w.hostname,
+		Short:    string(bytes.TrimSpace(p)),
+		TimeUnix: float64(time.Now().UnixNano()) / float64(time.Second),
+		Level:    6, // info, the level is a field of the record
+		Facility: w.facility,
+	}
+
+	var body bytes.Buffer
+	if err := message.MarshalJSONBuf(&body); err != nil {
+		return 0, fmt.Errorf("error marshaling the gelf message: %v", err)
+	}
+
+	select {
+	case w.queue <- body.Bytes():
+	default:
+		w.dropped.Add(1) // reported by the next sync, the callers aren't slowed down by the failing graylog
+	}
+	return len(p), nil
+}
+
+// Sync posts the queued records and reports the failures since the previous sync
+func (w *gelfHTTPWriter) Sync() error {
+	done := make(chan error, 1)
+	w.syncs <- done
+	return <-done
+}
+
+// run posts the batches once they're full or at every flush interval, it lives as long as the process
+func (w *gelfHTTPWriter) run(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, w.batchSize)
+	var failures int
+	var lastErr error
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := w.post(batch); err != nil {
+			failures, lastErr = failures+1, err
+		}
+		batch = batch[:0]
+	}
+	add := func(message []byte) {
+		if batch = append(batch, message); len(batch) >= w.batchSize {
+			flush()
+		}
+	}
+
+	for {
+		select {
+		case message := <-w.queue:
+			add(message)
+		case <-ticker.C:
+			flush()
+		case done := <-w.syncs:
+			for queued := len(w.queue); queued > 0; queued-- {
+				add(<-w.queue)
+			}
+			flush()
+
+			var errs []error
+			if failures > 0 {
+				errs = append(errs, fmt.Errorf("%d gelf batches have failed, the last by: %v", failures, lastErr))
+				failures, lastErr = 0, nil
+			}
+			if dropped := w.dropped.Swap(0); dropped > 0 {
+				errs = append(errs, fmt.Errorf("%d gelf messages are dropped as the queue was full", dropped))
+			}
+			done <- errors.Join(errs...)
+		}
+	}
+}
+
+func (w *gelfHTTPWriter) post(batch [][]byte) error {
+	response, err := w.client.Post(w.uri, "application/json", bytes.NewReader(bytes.Join(batch, []byte("\n"))))
+	if err != nil {
+		return fmt.Errorf("error posting the gelf messages: %v", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body) // so the connection is reused
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("graylog has answered the gelf messages by %d", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGELFHTTPWriterBatches(t *testing.T) {
+	var mutex sync.Mutex
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []string
+		for scanner := bufio.NewScanner(r.Body); scanner.Scan(); {
+			batch = append(batch, scanner.Text())
+		}
+		mutex.Lock()
+		batches = append(batches, batch)
+		mutex.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	writer := newGELFHTTPWriter(server.URL, "porsesh", 10, 3, time.Hour)
+	for _, record := range []string{"first", "second", "third", "fourth"} {
+		if _, err := writer.Write([]byte(record + "\n")); err != nil {
+			t.Fatalf("write has error %s", err.Error())
+		}
+	}
+
+	// the full batch is posted right away, the rest waits for the interval or the sync
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("sync has error %s", err.Error())
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 1 {
+		t.Fatalf("expected a batch of 3 and a batch of 1 messages, got %v", batches)
+	}
+	if !strings.Contains(batches[0][0], `"short_message":"first"`) || !strings.Contains(batches[1][0], `"short_message":"fourth"`) {
+		t.Fatalf("the messages should be posted in order, got %v", batches)
+	}
+}
+
+func TestGELFHTTPWriterDrops(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release // graylog falls behind
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	writer := newGELFHTTPWriter(server.URL, "porsesh", 1, 1, time.Hour)
+
+	start := time.Now()
+	for range 5 {
+		if _, err := writer.Write([]byte("vote is submitted")); err != nil {
+			t.Fatalf("write has error %s", err.Error())
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("the writes shouldn't wait for graylog, took %s", elapsed)
+	}
+
+	close(release)
+	err := writer.Sync()
+	if err == nil || !strings.Contains(err.Error(), "dropped") {
+		t.Fatalf("sync should report the dropped messages, got %v", err)
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("the drops should be reported once, got %v", err)
+	}
+}
+
+func TestGELFHTTPWriterFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	writer := newGELFHTTPWriter(server.URL, "porsesh", 10, 1, time.Hour)
+	_, _ = writer.Write([]byte("vote is submitted"))
+
+	if err := writer.Sync(); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("sync should report the failed posts, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sinkLevel is the configured level of the sink, or the level of the logger if the sink has none
+func sinkLevel(configured string, level zap.AtomicLevel) (zapcore.LevelEnabler, error) {
+	if len(configured) == 0 {
+		return level, nil
+	}
+	return zapcore.ParseLevel(configured)
+}
+
+// leveledCore filters the entries of the cores which don't take a level themselves
+type leveledCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *leveledCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level) && c.Core.Enabled(level)
+}
+
+func (c *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *leveledCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -11,38 +12,54 @@ import (
 	"gopkg.in/Graylog2/go-gelf.v2/gelf"
 )
 
-func New(cfg *Config) (*zap.Logger, error) {
+// New returns the logger along with its level, which can be changed at runtime. Sentry only receives the
+// errors regardless of the level. Every sink is wrapped by the redaction policy.
+func New(cfg *Config, service, version string) (*zap.Logger, zap.AtomicLevel, error) {
 	var cores []zapcore.Core
 
-	level := zap.InfoLevel
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
 	if cfg.Development {
-		level = zap.DebugLevel
+		level.SetLevel(zap.DebugLevel)
+	}
+
+	policy := DefaultPolicy()
+	if cfg.Redaction != nil {
+		var err error
+		if policy, err = NewPolicy(cfg.Redaction.Fields, cfg.Redaction.Values); err != nil {
+			return nil, level, fmt.Errorf("error creating the redaction policy: %v", err)
+		}
 	}
 
 	for _, logger := range cfg.Loggers {
+		var core zapcore.Core
+		var err error
+
 		switch logger {
 		case StandardLogger:
-			core := standardCore(cfg, level)
-			cores = append(cores, core)
+			core = standardCore(cfg, level)
 		case GraylogLogger:
-			core, err := graylogCore(cfg, level)
-			if err != nil {
-				return nil, err
-			}
-			cores = append(cores, core)
+			core, err = graylogCore(cfg, level)
 		case SentryLogger:
-			core, err := sentryCore(cfg)
-			if err != nil {
-				return nil, err
-			}
-			cores = append(cores, core)
+			core, err = sentryCore(cfg)
+		case FileLogger:
+			core, err = fileCore(cfg, level)
+		case OTLPLogger:
+			core, err = otlpCore(cfg, level, service, version)
+		default:
+			err = fmt.Errorf("unknown logger type %q", logger)
 		}
+		if err != nil {
+			return nil, level, err
+		}
+
+		// the sinks are wrapped one by one, as the tee writes to all of its cores once any of them is enabled
+		cores = append(cores, NewRedactingCore(core, policy))
 	}
 
-	return zap.New(zapcore.NewTee(cores...)), nil
+	return zap.New(zapcore.NewTee(cores...)), level, nil
 }
 
-func standardCore(cfg *Config, level zapcore.Level) zapcore.Core {
+func standardCore(cfg *Config, level zap.AtomicLevel) zapcore.Core {
 	writerSyncer := zapcore.Lock(os.Stdout)
 
 	var encoderConfig zapcore.EncoderConfig
@@ -62,17 +79,22 @@ func standardCore(cfg *Config, level zapcore.Level) zapcore.Core {
 		encoder = zapcore.NewJSONEncoder(encoderConfig)
 	}
 
-	levelEnablerFunc := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return zapcore.Level(level) <= lvl
-	})
-
-	return zapcore.NewCore(encoder, writerSyncer, levelEnablerFunc)
+	return zapcore.NewCore(encoder, writerSyncer, level)
 }
 
-func graylogCore(cfg *Config, level zapcore.Level) (zapcore.Core, error) {
+func graylogCore(cfg *Config, level zap.AtomicLevel) (zapcore.Core, error) {
+	if cfg.Graylog == nil || len(cfg.Graylog.URI) == 0 {
+		return nil, errors.New("the uri of the graylog logger is not configured")
+	}
+
 	graylogURI, err := url.Parse(cfg.Graylog.URI)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid graylog uri: %v", err)
+	}
+
+	enabler, err := sinkLevel(cfg.Graylog.Level, level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid level of the graylog logger: %v", err)
 	}
 
 	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
@@ -93,13 +115,14 @@ func graylogCore(cfg *Config, level zapcore.Level) (zapcore.Core, error) {
 		}
 		tcpWriter.Facility = cfg.Graylog.Facility
 		writerSyncer = zapcore.AddSync(tcpWriter)
+	case "http", "https":
+		writerSyncer = newGELFHTTPWriter(graylogURI.String(), cfg.Graylog.Facility,
+			cfg.Graylog.QueueSize, cfg.Graylog.BatchSize, cfg.Graylog.FlushInterval)
+	default:
+		return nil, fmt.Errorf("unknown graylog scheme %q, expected udp, tcp, http or https", graylogURI.Scheme)
 	}
 
-	levelEnablerFunc := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return zapcore.Level(level) <= lvl
-	})
-
-	core := zapcore.NewCore(encoder, writerSyncer, levelEnablerFunc)
+	core := zapcore.NewCore(encoder, writerSyncer, enabler)
 	err = core.Sync()
 	if err != nil {
 		return nil, fmt.Errorf("got error (%v) on creating graylog core", err)
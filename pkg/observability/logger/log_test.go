@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{name: "unknown_logger", cfg: &Config{Loggers: []LoggerType{"syslog"}}},
+		{name: "unknown_graylog_scheme", cfg: &Config{Loggers: []LoggerType{GraylogLogger},
+			Graylog: &GraylogConfig{URI: "amqp://graylog:5672"}}},
+		{name: "missing_graylog_uri", cfg: &Config{Loggers: []LoggerType{GraylogLogger}, Graylog: &GraylogConfig{}}},
+		{name: "invalid_level", cfg: &Config{Loggers: []LoggerType{FileLogger},
+			File: &FileConfig{Path: filepath.Join(t.TempDir(), "porsesh.log"), Level: "loud"}}},
+		{name: "missing_otlp_endpoint", cfg: &Config{Loggers: []LoggerType{OTLPLogger}, OTLP: &OTLPConfig{}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := New(test.cfg, "porsesh", "test"); err == nil {
+				t.Fatal("the config should be rejected")
+			}
+		})
+	}
+}
+
+func TestFileLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "porsesh.log")
+	cfg := &Config{Loggers: []LoggerType{FileLogger}, File: &FileConfig{Path: path, MaxSize: 1, Level: "warn"}}
+
+	lg, level, err := New(cfg, "porsesh", "test")
+	if err != nil {
+		t.Fatalf("new logger has error %s", err.Error())
+	}
+	level.SetLevel(zap.DebugLevel) // the level of the sink is kept
+
+	lg.Info("dropped by the level of the sink")
+	lg.Warn("written", zap.String("password", "9xz3jrd8wf"))
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file has error %s", err.Error())
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"msg":"written"`) || strings.Contains(lines[0], "9xz3jrd8wf") {
+		t.Fatalf("log file is %q, expected only the redacted warning", content)
+	}
+}
+
+func TestGraylogHTTPLogger(t *testing.T) {
+	messages := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		message := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		messages <- message
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := &Config{Loggers: []LoggerType{GraylogLogger}, Graylog: &GraylogConfig{URI: server.URL + "/gelf", Facility: "porsesh"}}
+	lg, _, err := New(cfg, "porsesh", "test")
+	if err != nil {
+		t.Fatalf("new logger has error %s", err.Error())
+	}
+
+	lg.Info("vote is submitted")
+
+	message := <-messages
+	if message["facility"] != "porsesh" || !strings.Contains(message["short_message"].(string), "vote is submitted") {
+		t.Fatalf("gelf message is %v, expected the record as the short message", message)
+	}
+}
+
+func TestOTLPLogger(t *testing.T) {
+	var exports atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/logs" {
+			_, _ = io.Copy(io.Discard, r.Body)
+			exports.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &Config{Loggers: []LoggerType{OTLPLogger},
+		OTLP: &OTLPConfig{Endpoint: strings.TrimPrefix(server.URL, "http://"), Insecure: true}}
+	lg, _, err := New(cfg, "porsesh", "test")
+	if err != nil {
+		t.Fatalf("new logger has error %s", err.Error())
+	}
+
+	lg.Info("poll is published")
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("sync has error %s", err.Error())
+	}
+	if exports.Load() == 0 {
+		t.Fatal("the records are not exported on the sync")
+	}
+}
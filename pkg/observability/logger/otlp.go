@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/contrib/bridges/otelzap"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const otlpFlushTimeout = 5 * time.Second
+
+func otlpCore(cfg *Config, level zap.AtomicLevel, service, version string) (zapcore.Core, error) {
+	if cfg.OTLP == nil || len(cfg.OTLP.Endpoint) == 0 {
+		return nil, errors.New("the endpoint of the otlp logger is not configured")
+	}
+
+	enabler, err := sinkLevel(cfg.OTLP.Level, level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid level of the otlp logger: %v", err)
+	}
+
+	options := []otlploghttp.Option{otlploghttp.WithEndpoint(cfg.OTLP.Endpoint)}
+	if cfg.OTLP.Insecure {
+		options = append(options, otlploghttp.WithInsecure())
+	}
+	if len(cfg.OTLP.Headers) != 0 {
+		options = append(options, otlploghttp.WithHeaders(cfg.OTLP.Headers))
+	}
+
+	exporter, err := otlploghttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the otlp log exporter: %v", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(service), semconv.ServiceVersion(version)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating the log resource: %v", err)
+	}
+
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)), sdklog.WithResource(res))
+
+	// the records are correlated with the traces by the contexts given as fields, e.g. zap.Any("context", ctx)
+	core := otelzap.NewCore("github.com/mohammadne/porsesh/pkg/observability/logger", otelzap.WithLoggerProvider(provider))
+	return &flushingCore{Core: &leveledCore{Core: core, level: enabler}, provider: provider}, nil
+}
+
+// flushingCore exports the batched records on the sync of the logger
+type flushingCore struct {
+	zapcore.Core
+	provider *sdklog.LoggerProvider
+}
+
+func (c *flushingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &flushingCore{Core: c.Core.With(fields), provider: c.provider}
+}
+
+func (c *flushingCore) Sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), otlpFlushTimeout)
+	defer cancel()
+	return c.provider.ForceFlush(ctx)
+}
@@ -0,0 +1,307 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted replaces the redacted values, the keys of the fields are kept to tell they were there
+const Redacted = "[REDACTED]"
+
+var (
+	// DefaultFieldPatterns match the names of the fields whose values are never logged
+	DefaultFieldPatterns = []string{
+		`(?i)pass(word|wd)?$`,
+		`(?i)secret`,
+		`(?i)token`,
+		`(?i)authorization`,
+		`(?i)api_?key`,
+		`(?i)credential`,
+		`(?i)(^|_)dsn$`,
+	}
+
+	// DefaultValuePatterns match the sensitive parts of the values, they're replaced wherever they appear.
+	// If a pattern has a group, only the first group is replaced so the surrounding context is kept.
+	DefaultValuePatterns = []string{
+		`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,                  // emails
+		`(?i)bearer\s+([A-Za-z0-9._~+/=-]+)`,                              // authorization headers
+		`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`,               // JWTs
+		`(?i)(?:password|passwd|pwd|secret|token)\s*[=:]\s*([^\s&,;]+)`,   // key=value pairs, e.g. connection strings
+		`[A-Za-z][A-Za-z0-9+.-]*://([^/\s:@]+:[^/\s@]+)@`,                 // credentials of URLs
+		`(?i)(?:sk|pk|rk)_(?:live|test)_[A-Za-z0-9]{8,}|AKIA[A-Z0-9]{16}`, // well-known API keys
+	}
+)
+
+// Policy redacts the fields by their names and the sensitive parts of the values by the patterns
+type Policy struct {
+	fields []*regexp.Regexp
+	values []*regexp.Regexp
+}
+
+// NewPolicy compiles the default patterns along with the given ones
+func NewPolicy(fieldPatterns, valuePatterns []string) (*Policy, error) {
+	policy := &Policy{}
+
+	for _, pattern := range append(append([]string{}, DefaultFieldPatterns...), fieldPatterns...) {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field pattern %q: %v", pattern, err)
+		}
+		policy.fields = append(policy.fields, expression)
+	}
+
+	for _, pattern := range append(append([]string{}, DefaultValuePatterns...), valuePatterns...) {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value pattern %q: %v", pattern, err)
+		}
+		policy.values = append(policy.values, expression)
+	}
+
+	return policy, nil
+}
+
+// DefaultPolicy only consists of the default patterns
+func DefaultPolicy() *Policy {
+	policy, err := NewPolicy(nil, nil)
+	if err != nil {
+		panic(err) // the default patterns are valid
+	}
+	return policy
+}
+
+// Field tells whether the values of the field have to be redacted as a whole
+func (p *Policy) Field(name string) bool {
+	for _, expression := range p.fields {
+		if expression.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value replaces the sensitive parts of the value
+func (p *Policy) Value(value string) string {
+	for _, expression := range p.values {
+		if expression.NumSubexp() == 0 {
+			value = expression.ReplaceAllLiteralString(value, Redacted)
+			continue
+		}
+
+		matches := expression.FindAllStringSubmatchIndex(value, -1)
+		for index := len(matches) - 1; index >= 0; index-- { // backwards, so the earlier offsets stay valid
+			if start, end := matches[index][2], matches[index][3]; start >= 0 {
+				value = value[:start] + Redacted + value[end:]
+			}
+		}
+	}
+	return value
+}
+
+// Fields returns the redacted copies of the fields, the nested objects are redacted as they're encoded
+func (p *Policy) Fields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, 0, len(fields))
+	for _, field := range fields {
+		redacted = append(redacted, p.field(field))
+	}
+	return redacted
+}
+
+func (p *Policy) field(field zapcore.Field) zapcore.Field {
+	if p.Field(field.Key) {
+		switch field.Type {
+		case zapcore.SkipType, zapcore.NamespaceType:
+			return field
+		}
+		return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: Redacted}
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = p.Value(field.String)
+	case zapcore.ByteStringType:
+		field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: p.Value(string(field.Interface.([]byte)))}
+	case zapcore.StringerType:
+		field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: p.Value(stringer(field.Interface))}
+	case zapcore.ErrorType:
+		// the messages of the errors tend to carry the values which have failed, e.g. the connection strings
+		if err, ok := field.Interface.(error); ok && err != nil {
+			if message := err.Error(); p.Value(message) != message {
+				field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: p.Value(message)}
+			}
+		}
+	case zapcore.ObjectMarshalerType:
+		field.Interface = &redactingObject{ObjectMarshaler: field.Interface.(zapcore.ObjectMarshaler), policy: p}
+	case zapcore.ArrayMarshalerType:
+		field.Interface = &redactingArray{ArrayMarshaler: field.Interface.(zapcore.ArrayMarshaler), policy: p}
+	case zapcore.ReflectType:
+		field.Interface = p.reflected(field.Interface)
+	}
+	return field
+}
+
+// reflected returns a redacted copy of the value in its JSON form, as it's encoded by zap. The value is kept
+// if it can't be encoded, zap reports the error of the encoding in its place.
+func (p *Policy) reflected(value any) any {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber() // the large integers are kept as they are
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return value
+	}
+	return p.plain(decoded)
+}
+
+// plain redacts the decoded JSON value, the keys of the objects are checked by the field patterns
+func (p *Policy) plain(value any) any {
+	switch value := value.(type) {
+	case string:
+		return p.Value(value)
+	case []any:
+		for index := range value {
+			value[index] = p.plain(value[index])
+		}
+	case map[string]any:
+		for key, item := range value {
+			if p.Field(key) && item != nil {
+				value[key] = Redacted
+				continue
+			}
+			value[key] = p.plain(item)
+		}
+	}
+	return value
+}
+
+func stringer(value any) (result string) {
+	defer func() { // the same as zap, a nil receiver may panic
+		if recovered := recover(); recovered != nil {
+			result = fmt.Sprintf("PANIC=%v", recovered)
+		}
+	}()
+	return value.(fmt.Stringer).String()
+}
+
+// redactingObject encodes the nested object through the policy
+type redactingObject struct {
+	zapcore.ObjectMarshaler
+	policy *Policy
+}
+
+func (o *redactingObject) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	return o.ObjectMarshaler.MarshalLogObject(&redactingEncoder{ObjectEncoder: encoder, policy: o.policy})
+}
+
+// redactingEncoder redacts the string values and the objects of the nested objects
+type redactingEncoder struct {
+	zapcore.ObjectEncoder
+	policy *Policy
+}
+
+func (e *redactingEncoder) AddString(key, value string) {
+	if e.policy.Field(key) {
+		value = Redacted
+	}
+	e.ObjectEncoder.AddString(key, e.policy.Value(value))
+}
+
+func (e *redactingEncoder) AddByteString(key string, value []byte) {
+	e.AddString(key, string(value))
+}
+
+func (e *redactingEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	if e.policy.Field(key) {
+		e.ObjectEncoder.AddString(key, Redacted)
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, &redactingObject{ObjectMarshaler: marshaler, policy: e.policy})
+}
+
+func (e *redactingEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	if e.policy.Field(key) {
+		e.ObjectEncoder.AddString(key, Redacted)
+		return nil
+	}
+	return e.ObjectEncoder.AddArray(key, &redactingArray{ArrayMarshaler: marshaler, policy: e.policy})
+}
+
+func (e *redactingEncoder) AddReflected(key string, value any) error {
+	if e.policy.Field(key) {
+		e.ObjectEncoder.AddString(key, Redacted)
+		return nil
+	}
+	return e.ObjectEncoder.AddReflected(key, e.policy.reflected(value))
+}
+
+// redactingArray encodes the nested array through the policy
+type redactingArray struct {
+	zapcore.ArrayMarshaler
+	policy *Policy
+}
+
+func (a *redactingArray) MarshalLogArray(encoder zapcore.ArrayEncoder) error {
+	return a.ArrayMarshaler.MarshalLogArray(&redactingArrayEncoder{ArrayEncoder: encoder, policy: a.policy})
+}
+
+// redactingArrayEncoder redacts the string items and the nested objects and arrays of the arrays, the items
+// have no names so only the value patterns apply to them
+type redactingArrayEncoder struct {
+	zapcore.ArrayEncoder
+	policy *Policy
+}
+
+func (e *redactingArrayEncoder) AppendString(value string) {
+	e.ArrayEncoder.AppendString(e.policy.Value(value))
+}
+
+func (e *redactingArrayEncoder) AppendByteString(value []byte) {
+	e.AppendString(string(value))
+}
+
+func (e *redactingArrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(&redactingObject{ObjectMarshaler: marshaler, policy: e.policy})
+}
+
+func (e *redactingArrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(&redactingArray{ArrayMarshaler: marshaler, policy: e.policy})
+}
+
+func (e *redactingArrayEncoder) AppendReflected(value any) error {
+	return e.ArrayEncoder.AppendReflected(e.policy.reflected(value))
+}
+
+// redactingCore applies the policy before the wrapped core, so none of the sinks receives the sensitive values
+type redactingCore struct {
+	zapcore.Core
+	policy *Policy
+}
+
+func NewRedactingCore(core zapcore.Core, policy *Policy) zapcore.Core {
+	return &redactingCore{Core: core, policy: policy}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.policy.Fields(fields)), policy: c.policy}
+}
+
+// Check adds this core instead of the wrapped one, otherwise the entries would be written around the policy
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.policy.Value(entry.Message)
+	return c.Core.Write(entry, c.policy.Fields(fields))
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -8,21 +9,23 @@ import (
 
 type Counter interface {
 	IncrementVector(values ...string)
+	// IncrementVectorContext attaches the trace ID of the context as an exemplar, if it's sampled
+	IncrementVectorContext(ctx context.Context, values ...string)
 }
 
 type counter struct {
 	vector *prometheus.CounterVec
 }
 
-func MustRegisterCounter(name, namespace, subsystem string, labels []string) Counter {
+func (r *Registry) MustRegisterCounter(name, namespace, subsystem string, labels []string) Counter {
 	vector := counterVector(name, namespace, subsystem, labels)
-	prometheus.MustRegister(vector)
+	r.registry.MustRegister(vector)
 	return &counter{vector: vector}
 }
 
-func RegisterCounter(name, namespace, subsystem string, labels []string) (Counter, error) {
+func (r *Registry) RegisterCounter(name, namespace, subsystem string, labels []string) (Counter, error) {
 	vector := counterVector(name, namespace, subsystem, labels)
-	if err := prometheus.Register(vector); err != nil {
+	if err := r.registry.Register(vector); err != nil {
 		return nil, fmt.Errorf("error while registering counter vector: %v", err)
 	}
 	return &counter{vector: vector}, nil
@@ -41,12 +44,22 @@ func (c *counter) IncrementVector(values ...string) {
 	c.vector.WithLabelValues(values...).Inc()
 }
 
+func (c *counter) IncrementVectorContext(ctx context.Context, values ...string) {
+	if labels := exemplar(ctx); labels != nil {
+		c.vector.WithLabelValues(values...).(prometheus.ExemplarAdder).AddWithExemplar(1, labels)
+		return
+	}
+	c.IncrementVector(values...)
+}
+
 // Noop implementation
 
 type counterNoop struct{}
 
 func (c *counterNoop) IncrementVector(values ...string) {}
 
+func (c *counterNoop) IncrementVectorContext(ctx context.Context, values ...string) {}
+
 func RegisterCounterNoop() Counter {
 	return &counterNoop{}
 }
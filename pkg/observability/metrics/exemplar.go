@@ -0,0 +1,17 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exemplar links the observation to the sampled trace of the context, nil is returned otherwise
+func exemplar(ctx context.Context) prometheus.Labels {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsSampled() {
+		return nil
+	}
+	return prometheus.Labels{"trace_id": spanContext.TraceID().String()}
+}
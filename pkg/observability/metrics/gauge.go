@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Gauge interface {
+	SetVector(value float64, values ...string)
+	IncrementVector(values ...string)
+	DecrementVector(values ...string)
+}
+
+type gauge struct {
+	vector *prometheus.GaugeVec
+}
+
+func (r *Registry) MustRegisterGauge(name, namespace, subsystem string, labels []string) Gauge {
+	vector := gaugeVector(name, namespace, subsystem, labels)
+	r.registry.MustRegister(vector)
+	return &gauge{vector: vector}
+}
+
+func (r *Registry) RegisterGauge(name, namespace, subsystem string, labels []string) (Gauge, error) {
+	vector := gaugeVector(name, namespace, subsystem, labels)
+	if err := r.registry.Register(vector); err != nil {
+		return nil, fmt.Errorf("error while registering gauge vector: %v", err)
+	}
+	return &gauge{vector: vector}, nil
+}
+
+func gaugeVector(name, namespace, subsystem string, labels []string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Help:      fmt.Sprintf("gauge vector for %s", name),
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      name,
+	}, labels)
+}
+
+func (g *gauge) SetVector(value float64, values ...string) {
+	g.vector.WithLabelValues(values...).Set(value)
+}
+
+func (g *gauge) IncrementVector(values ...string) {
+	g.vector.WithLabelValues(values...).Inc()
+}
+
+func (g *gauge) DecrementVector(values ...string) {
+	g.vector.WithLabelValues(values...).Dec()
+}
+
+// Noop implementation
+
+type gaugeNoop struct{}
+
+func (g *gaugeNoop) SetVector(value float64, values ...string) {}
+
+func (g *gaugeNoop) IncrementVector(values ...string) {}
+
+func (g *gaugeNoop) DecrementVector(values ...string) {}
+
+func RegisterGaugeNoop() Gauge {
+	return &gaugeNoop{}
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,46 +9,108 @@ import (
 )
 
 type Histogram interface {
-	ObserveResponseTime(duration time.Time, values ...string)
+	ObserveResponseTime(start time.Time, values ...string)
+	ObserveVector(value float64, values ...string)
+	// the context variants attach the trace ID of the context as an exemplar, if it's sampled
+	ObserveResponseTimeContext(ctx context.Context, start time.Time, values ...string)
+	ObserveVectorContext(ctx context.Context, value float64, values ...string)
+}
+
+// ResponseTimeBuckets are the default buckets of the histograms, in seconds
+var ResponseTimeBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+type HistogramOption func(*prometheus.HistogramOpts)
+
+// WithBuckets replaces the default response time buckets, e.g. to observe the sizes
+func WithBuckets(buckets ...float64) HistogramOption {
+	return func(opts *prometheus.HistogramOpts) {
+		opts.Buckets = buckets
+	}
+}
+
+// WithExponentialBuckets replaces the default buckets with count ones, starting from start and
+// each one factor times the previous
+func WithExponentialBuckets(start, factor float64, count int) HistogramOption {
+	return func(opts *prometheus.HistogramOpts) {
+		opts.Buckets = prometheus.ExponentialBuckets(start, factor, count)
+	}
+}
+
+// WithNativeBuckets exposes the histogram as a native one as well, to the scrapers supporting it.
+// The factor is the growth of the sparse buckets (e.g. 1.1) and the resolution is halved whenever
+// there are more than maxBuckets of them.
+func WithNativeBuckets(factor float64, maxBuckets uint32) HistogramOption {
+	return func(opts *prometheus.HistogramOpts) {
+		opts.NativeHistogramBucketFactor = factor
+		opts.NativeHistogramMaxBucketNumber = maxBuckets
+		opts.NativeHistogramMinResetDuration = time.Hour
+	}
 }
 
 type histogram struct {
 	vector *prometheus.HistogramVec
 }
 
-func MustRegisterHistogram(name, namespace, subsystem string, labels []string) Histogram {
-	vector := histogramVector(name, namespace, subsystem, labels)
-	prometheus.MustRegister(vector)
+func (r *Registry) MustRegisterHistogram(name, namespace, subsystem string, labels []string, options ...HistogramOption) Histogram {
+	vector := histogramVector(name, namespace, subsystem, labels, options)
+	r.registry.MustRegister(vector)
 	return &histogram{vector: vector}
 }
 
-func RegisterHistogram(name, namespace, subsystem string, labels []string) (Histogram, error) {
-	vector := histogramVector(name, namespace, subsystem, labels)
-	if err := prometheus.Register(vector); err != nil {
+func (r *Registry) RegisterHistogram(name, namespace, subsystem string, labels []string, options ...HistogramOption) (Histogram, error) {
+	vector := histogramVector(name, namespace, subsystem, labels, options)
+	if err := r.registry.Register(vector); err != nil {
 		return nil, fmt.Errorf("error while registering histogram vector: %v", err)
 	}
 	return &histogram{vector: vector}, nil
 }
 
-func histogramVector(name, namespace, subsystem string, labels []string) *prometheus.HistogramVec {
-	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+func histogramVector(name, namespace, subsystem string, labels []string, options []HistogramOption) *prometheus.HistogramVec {
+	opts := prometheus.HistogramOpts{
 		Help:      fmt.Sprintf("histogram vector for %s", name),
 		Namespace: namespace,
 		Subsystem: subsystem,
 		Name:      name,
-		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
-	}, labels)
+		Buckets:   ResponseTimeBuckets,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+	return prometheus.NewHistogramVec(opts, labels)
 }
 
 func (c *histogram) ObserveResponseTime(start time.Time, values ...string) {
-	c.vector.WithLabelValues(values...).Observe(time.Since(start).Seconds())
+	c.ObserveVector(time.Since(start).Seconds(), values...)
+}
+
+func (c *histogram) ObserveVector(value float64, values ...string) {
+	c.vector.WithLabelValues(values...).Observe(value)
+}
+
+func (c *histogram) ObserveResponseTimeContext(ctx context.Context, start time.Time, values ...string) {
+	c.ObserveVectorContext(ctx, time.Since(start).Seconds(), values...)
+}
+
+func (c *histogram) ObserveVectorContext(ctx context.Context, value float64, values ...string) {
+	if labels := exemplar(ctx); labels != nil {
+		c.vector.WithLabelValues(values...).(prometheus.ExemplarObserver).ObserveWithExemplar(value, labels)
+		return
+	}
+	c.ObserveVector(value, values...)
 }
 
 // Noop implementation
 
 type histogramNoop struct{}
 
-func (h *histogramNoop) ObserveResponseTime(duration time.Time, values ...string) {}
+func (h *histogramNoop) ObserveResponseTime(start time.Time, values ...string) {}
+
+func (h *histogramNoop) ObserveVector(value float64, values ...string) {}
+
+func (h *histogramNoop) ObserveResponseTimeContext(ctx context.Context, start time.Time, values ...string) {
+}
+
+func (h *histogramNoop) ObserveVectorContext(ctx context.Context, value float64, values ...string) {}
 
 func RegisterHistogramNoop() Histogram {
 	return &histogramNoop{}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func gather(t *testing.T, registry *Registry, name string) *dto.MetricFamily {
+	t.Helper()
+
+	families, err := registry.Gatherer().Gather()
+	if err != nil {
+		t.Fatalf("gather has error %s", err.Error())
+	}
+	for _, family := range families {
+		if family.GetName() == name {
+			return family
+		}
+	}
+
+	t.Fatalf("metric %s is not gathered", name)
+	return nil
+}
+
+func TestRegistriesDontClash(t *testing.T) {
+	first, second := NewRegistry(), NewRegistry()
+
+	if _, err := first.RegisterCounter("requests", "ns", "sub", []string{"status"}); err != nil {
+		t.Fatalf("register on the first registry has error %s", err.Error())
+	}
+	if _, err := second.RegisterCounter("requests", "ns", "sub", []string{"status"}); err != nil {
+		t.Fatalf("register on the second registry has error %s", err.Error())
+	}
+	if _, err := first.RegisterCounter("requests", "ns", "sub", []string{"status"}); err == nil {
+		t.Fatal("registering twice on the same registry should fail")
+	}
+}
+
+func TestExemplars(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.MustRegisterCounter("votes", "ns", "sub", []string{"type"})
+	histogram := registry.MustRegisterHistogram("sizes", "ns", "sub", []string{}, WithBuckets(1, 5, 10))
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	counter.IncrementVectorContext(ctx, "single")
+	counter.IncrementVectorContext(context.Background(), "single") // no exemplar without a trace
+	histogram.ObserveVectorContext(ctx, 3)
+
+	metric := gather(t, registry, "ns_sub_votes").GetMetric()[0]
+	if metric.GetCounter().GetValue() != 2 {
+		t.Fatalf("counter value is %v, expected 2", metric.GetCounter().GetValue())
+	}
+	if exemplar := metric.GetCounter().GetExemplar(); exemplar == nil ||
+		exemplar.GetLabel()[0].GetValue() != spanContext.TraceID().String() {
+		t.Fatalf("counter exemplar is %v, expected the trace id", exemplar)
+	}
+
+	buckets := gather(t, registry, "ns_sub_sizes").GetMetric()[0].GetHistogram().GetBucket()
+	if len(buckets) != 3 || buckets[0].GetCumulativeCount() != 0 || buckets[1].GetCumulativeCount() != 1 {
+		t.Fatalf("histogram buckets are %v, expected the value in the bucket of 5", buckets)
+	}
+	if buckets[1].GetExemplar() == nil {
+		t.Fatal("histogram bucket has no exemplar")
+	}
+}
+
+func TestSummaryObjectives(t *testing.T) {
+	registry := NewRegistry()
+	summary := registry.MustRegisterSummary("latency", "ns", "sub", []string{}, map[float64]float64{0.5: 0.05})
+
+	for value := 1; value <= 100; value++ {
+		summary.ObserveVector(float64(value))
+	}
+
+	quantiles := gather(t, registry, "ns_sub_latency").GetMetric()[0].GetSummary().GetQuantile()
+	if len(quantiles) != 1 || quantiles[0].GetValue() < 45 || quantiles[0].GetValue() > 55 {
+		t.Fatalf("summary quantiles are %v, expected the median around 50", quantiles)
+	}
+}
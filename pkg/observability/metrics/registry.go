@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds the registered vectors apart from the global prometheus registry,
+// so several servers (or tests) in one process don't clash over the metric names
+type Registry struct {
+	registry *prometheus.Registry
+}
+
+// NewRegistry returns an empty registry along with the Go runtime and the process collectors
+func NewRegistry() *Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return &Registry{registry: registry}
+}
+
+// Handler serves the registered metrics, in the OpenMetrics format if the scraper accepts it
+// as the exemplars are only exposed by that format
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// Gatherer exposes the registered metrics, e.g. to be checked by the tests
+func (r *Registry) Gatherer() prometheus.Gatherer {
+	return r.registry
+}
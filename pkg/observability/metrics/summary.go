@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type Summary interface {
+	ObserveVector(value float64, values ...string)
+}
+
+// DefaultObjectives are the median, the 90th and the 99th percentiles along with their absolute errors
+var DefaultObjectives = map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+
+type summary struct {
+	vector *prometheus.SummaryVec
+}
+
+// MustRegisterSummary registers a summary of the quantiles over the last ten minutes, the objectives
+// map the quantiles to their absolute errors and DefaultObjectives are used if none is given
+func (r *Registry) MustRegisterSummary(name, namespace, subsystem string, labels []string, objectives map[float64]float64) Summary {
+	vector := summaryVector(name, namespace, subsystem, labels, objectives)
+	r.registry.MustRegister(vector)
+	return &summary{vector: vector}
+}
+
+// RegisterSummary is the same as MustRegisterSummary, only the registration error is returned
+func (r *Registry) RegisterSummary(name, namespace, subsystem string, labels []string, objectives map[float64]float64) (Summary, error) {
+	vector := summaryVector(name, namespace, subsystem, labels, objectives)
+	if err := r.registry.Register(vector); err != nil {
+		return nil, fmt.Errorf("error while registering summary vector: %v", err)
+	}
+	return &summary{vector: vector}, nil
+}
+
+func summaryVector(name, namespace, subsystem string, labels []string, objectives map[float64]float64) *prometheus.SummaryVec {
+	if len(objectives) == 0 {
+		objectives = DefaultObjectives
+	}
+
+	return prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Help:       fmt.Sprintf("summary vector for %s", name),
+		Namespace:  namespace,
+		Subsystem:  subsystem,
+		Name:       name,
+		Objectives: objectives,
+		MaxAge:     10 * time.Minute,
+	}, labels)
+}
+
+func (s *summary) ObserveVector(value float64, values ...string) {
+	s.vector.WithLabelValues(values...).Observe(value)
+}
+
+// Noop implementation
+
+type summaryNoop struct{}
+
+func (s *summaryNoop) ObserveVector(value float64, values ...string) {}
+
+func RegisterSummaryNoop() Summary {
+	return &summaryNoop{}
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+)
+
+type ExporterType string
+
+const (
+	NoneExporter   ExporterType = "none"
+	StdoutExporter ExporterType = "stdout"
+	OTLPExporter   ExporterType = "otlp"
+)
+
+type Config struct {
+	Exporter    ExporterType `required:"true"`
+	SampleRatio float64      `split_words:"true" default:"1"` // of the root spans, the remote parents decide for the rest
+	OTLP        *OTLPConfig
+}
+
+type OTLPConfig struct {
+	Endpoint string            // host:port of the collector, over HTTP
+	Insecure bool              `default:"false"`
+	Headers  map[string]string `secret:"true"` // e.g. the authorization of the vendor, as key:value,key:value
+}
+
+func (cfg *Config) Validate() error {
+	var errs []error
+	switch cfg.Exporter {
+	case NoneExporter, StdoutExporter:
+	case OTLPExporter:
+		if cfg.OTLP == nil || len(cfg.OTLP.Endpoint) == 0 {
+			errs = append(errs, errors.New("endpoint of the otlp exporter is empty"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown trace exporter %q", cfg.Exporter))
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("sample ratio %v is out of [0, 1]", cfg.SampleRatio))
+	}
+	return errors.Join(errs...)
+}
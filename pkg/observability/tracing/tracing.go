@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New installs the global tracer provider along with the W3C trace-context and baggage propagators,
+// the returned function flushes the buffered spans and has to be called on the shutdown
+func New(cfg *Config, service, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case NoneExporter:
+		return func(context.Context) error { return nil }, nil // the global provider is a no-op by default
+	case StdoutExporter:
+		var err error
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout)); err != nil {
+			return nil, fmt.Errorf("error creating the stdout exporter: %v", err)
+		}
+	case OTLPExporter:
+		if cfg.OTLP == nil || len(cfg.OTLP.Endpoint) == 0 {
+			return nil, fmt.Errorf("the endpoint of the otlp exporter is not configured")
+		}
+
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLP.Endpoint)}
+		if cfg.OTLP.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.OTLP.Headers) != 0 {
+			options = append(options, otlptracehttp.WithHeaders(cfg.OTLP.Headers))
+		}
+
+		var err error
+		if exporter, err = otlptracehttp.New(context.Background(), options...); err != nil {
+			return nil, fmt.Errorf("error creating the otlp exporter: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(service), semconv.ServiceVersion(version)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating the trace resource: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the named tracer of the global provider, it's safe to be called before New
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// End records the error (if any) on the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,29 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx which carries the given request id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id stored in ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Generate returns a random 128-bit hex encoded request id
+func Generate() string {
+	bytes := make([]byte, 16)
+	_, _ = rand.Read(bytes) // never returns an error
+	return hex.EncodeToString(bytes)
+}
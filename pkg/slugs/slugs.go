@@ -0,0 +1,46 @@
+package slugs
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Generate returns a cryptographically random base62 string of the given length,
+// it never returns an all-digit slug so it can't be mistaken with a numeric id
+func Generate(length int) (string, error) {
+	if length <= 0 {
+		return "", fmt.Errorf("invalid slug length %d", length)
+	}
+
+	var builder strings.Builder
+	builder.Grow(length)
+
+	buffer := make([]byte, length*2)
+	for {
+		builder.Reset()
+		if _, err := rand.Read(buffer); err != nil {
+			return "", fmt.Errorf("error reading random bytes: %v", err)
+		}
+
+		hasLetter := false
+		for _, b := range buffer {
+			// reject the bytes above the largest multiple of 62 to avoid the modulo bias
+			if b >= 248 {
+				continue
+			}
+			index := b % byte(len(alphabet))
+			hasLetter = hasLetter || index >= 10
+			builder.WriteByte(alphabet[index])
+			if builder.Len() == length {
+				break
+			}
+		}
+
+		if builder.Len() == length && hasLetter {
+			return builder.String(), nil
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package slugs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, length := range []int{1, 2, 10, 32} {
+		for range 1000 {
+			slug, err := Generate(length)
+			if err != nil {
+				t.Fatalf("generate has error %s", err.Error())
+			}
+
+			if len(slug) != length {
+				t.Fatalf("slug %q should be of length %d", slug, length)
+			}
+
+			hasLetter := false
+			for _, r := range slug {
+				if !strings.ContainsRune(alphabet, r) {
+					t.Fatalf("slug %q has %q out of the base62 alphabet", slug, r)
+				}
+				hasLetter = hasLetter || !strings.ContainsRune("0123456789", r)
+			}
+
+			// an all-digit slug would be taken as a numeric id
+			if !hasLetter {
+				t.Fatalf("slug %q should contain a letter", slug)
+			}
+		}
+	}
+}
+
+func TestGenerateInvalidLength(t *testing.T) {
+	for _, length := range []int{0, -1} {
+		if _, err := Generate(length); err == nil {
+			t.Fatalf("generate of length %d should fail", length)
+		}
+	}
+}
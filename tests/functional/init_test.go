@@ -9,6 +9,7 @@ import (
 	"github.com/mohammadne/porsesh/internal/usecases"
 	"github.com/mohammadne/porsesh/pkg/databases/postgres"
 	"github.com/mohammadne/porsesh/pkg/observability/logger"
+	"github.com/mohammadne/porsesh/pkg/observability/metrics"
 	"go.uber.org/zap"
 )
 
@@ -16,28 +17,33 @@ var (
 	err error
 
 	// usecases
+	auditor      usecases.Auditor
 	feedsUsecase usecases.Feeds
 	pollsUsecase usecases.Polls
 
 	// caches
 
 	// storages
-	pollsStorage storage.Polls
-	tagsStorage  storage.Tags
-	votesStorage storage.Votes
+	pollsStorage   storage.Polls
+	tagsStorage    storage.Tags
+	votesStorage   storage.Votes
+	auditStorage   storage.AuditEvents
+	surveysStorage storage.Surveys
+	groupsStorage  storage.Groups
 )
 
 func TestMain(m *testing.M) {
 	cfg := struct {
 		Logger   *logger.Config   `required:"true"`
 		Postgres *postgres.Config `required:"true"`
+		Polls    *usecases.Config `required:"true"`
 	}{}
 
-	if err := config.Load(&cfg, string(config.EnvironmentLocal)); err != nil {
+	if err := config.Load(&cfg, string(config.EnvironmentLocal), ""); err != nil {
 		log.Panicf("failed to load config: \n%v", err)
 	}
 
-	postgres, err := postgres.Open(cfg.Postgres, config.Namespace, config.System)
+	postgres, err := postgres.Open(cfg.Postgres, metrics.NewRegistry(), config.Namespace, config.System)
 	if err != nil {
 		log.Fatalf("failed to initialize Postgres: \n%v", err)
 	}
@@ -46,11 +52,15 @@ func TestMain(m *testing.M) {
 		pollsStorage = storage.NewPools(zap.NewNop(), postgres)
 		tagsStorage = storage.NewTags(zap.NewNop(), postgres)
 		votesStorage = storage.NewVotes(zap.NewNop(), postgres)
+		auditStorage = storage.NewAuditEvents(zap.NewNop(), postgres)
+		surveysStorage = storage.NewSurveys(zap.NewNop(), postgres)
+		groupsStorage = storage.NewGroups(zap.NewNop(), postgres)
 	}
 
 	{ // usecases
-		feedsUsecase = usecases.NewFeeds(zap.NewNop())
-		pollsUsecase = usecases.NewPolls(zap.NewNop(), pollsStorage, tagsStorage, votesStorage)
+		auditor = usecases.NewAuditor(zap.NewNop(), auditStorage)
+		feedsUsecase = usecases.NewFeeds(cfg.Polls, zap.NewNop(), usecases.NoopMetrics(), pollsStorage, tagsStorage, votesStorage)
+		pollsUsecase = usecases.NewPolls(cfg.Polls, zap.NewNop(), usecases.NoopMetrics(), auditor, usecases.Moderators(), pollsStorage, tagsStorage, votesStorage, groupsStorage)
 	}
 
 	m.Run()
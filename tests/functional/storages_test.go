@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/mohammadne/porsesh/internal/repository/storage"
+	"github.com/mohammadne/porsesh/pkg/slugs"
 )
 
 func TestStoragePollOptions(t *testing.T) {
@@ -40,6 +42,32 @@ func TestStoragePollOptions(t *testing.T) {
 		bytes, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(bytes))
 	})
+
+	t.Run("get_poll_option", func(t *testing.T) {
+		result, err := pollsStorage.GetPollOption(context.TODO(), pollID, 1)
+		if err != nil {
+			t.Fatalf("get poll_option has error %s", err.Error())
+		}
+
+		bytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(bytes))
+	})
+
+	t.Run("lock_poll_options", func(t *testing.T) {
+		tx, err := pollsStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+		defer tx.Rollback()
+
+		result, err := pollsStorage.LockPollOptions(context.TODO(), tx, pollID)
+		if err != nil {
+			t.Fatalf("lock poll_options has error %s", err.Error())
+		}
+
+		bytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(bytes))
+	})
 }
 
 func TestStoragePollTags(t *testing.T) {
@@ -61,6 +89,140 @@ func TestStoragePollTags(t *testing.T) {
 	})
 }
 
+func TestStoragePollParticipants(t *testing.T) {
+	var pollID int64 = 3
+	var participantIDs []int64 = []int64{4, 5}
+
+	t.Run("add_poll_participants", func(t *testing.T) {
+		tx, err := pollsStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		err = pollsStorage.AddPollParticipants(context.TODO(), tx, pollID, participantIDs)
+		if err != nil {
+			t.Fatalf("add poll_participants has error %s", err.Error())
+		}
+
+		tx.Commit()
+	})
+
+	t.Run("is_poll_participant", func(t *testing.T) {
+		result, err := pollsStorage.IsPollParticipant(context.TODO(), pollID, participantIDs[0])
+		if err != nil {
+			t.Fatalf("is poll_participant has error %s", err.Error())
+		} else if !result {
+			t.Fatalf("user %d should be a participant", participantIDs[0])
+		}
+	})
+
+	t.Run("remove_poll_participants", func(t *testing.T) {
+		tx, err := pollsStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		err = pollsStorage.RemovePollParticipants(context.TODO(), tx, pollID, participantIDs[:1])
+		if err != nil {
+			t.Fatalf("remove poll_participants has error %s", err.Error())
+		}
+
+		tx.Commit()
+	})
+
+	t.Run("list_poll_participants", func(t *testing.T) {
+		result, err := pollsStorage.ListPollParticipants(context.TODO(), pollID)
+		if err != nil {
+			t.Fatalf("list poll_participants has error %s", err.Error())
+		}
+
+		bytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(bytes))
+	})
+}
+
+func TestStorageGroups(t *testing.T) {
+	var ownerID, pollID int64 = 1, 3
+	var memberIDs []int64 = []int64{6, 7}
+	var groupID int64
+
+	t.Run("create_group", func(t *testing.T) {
+		tx, err := groupsStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		groupID, err = groupsStorage.CreateGroup(context.TODO(), tx, &storage.Group{
+			OwnerID: ownerID,
+			Name:    fmt.Sprintf("team-%d", time.Now().UnixNano()),
+		})
+		if err != nil {
+			t.Fatalf("create group has error %s", err.Error())
+		}
+
+		err = groupsStorage.AddGroupMembers(context.TODO(), tx, groupID, memberIDs)
+		if err != nil {
+			t.Fatalf("add group members has error %s", err.Error())
+		}
+
+		tx.Commit()
+	})
+
+	t.Run("add_poll_participant_groups", func(t *testing.T) {
+		tx, err := pollsStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		err = pollsStorage.AddPollParticipantGroups(context.TODO(), tx, pollID, []int64{groupID})
+		if err != nil {
+			t.Fatalf("add poll_participant_groups has error %s", err.Error())
+		}
+
+		tx.Commit()
+	})
+
+	t.Run("is_poll_participant_by_group", func(t *testing.T) {
+		result, err := pollsStorage.IsPollParticipant(context.TODO(), pollID, memberIDs[0])
+		if err != nil {
+			t.Fatalf("is poll_participant has error %s", err.Error())
+		} else if !result {
+			t.Fatalf("user %d should be a participant by the group", memberIDs[0])
+		}
+	})
+
+	t.Run("remove_group_members", func(t *testing.T) {
+		tx, err := groupsStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		err = groupsStorage.RemoveGroupMembers(context.TODO(), tx, groupID, memberIDs[:1])
+		if err != nil {
+			t.Fatalf("remove group members has error %s", err.Error())
+		}
+
+		tx.Commit()
+
+		result, err := pollsStorage.IsPollParticipant(context.TODO(), pollID, memberIDs[0])
+		if err != nil {
+			t.Fatalf("is poll_participant has error %s", err.Error())
+		} else if result {
+			t.Fatalf("user %d shouldn't be a participant anymore", memberIDs[0])
+		}
+	})
+
+	t.Run("list_group_members", func(t *testing.T) {
+		result, err := groupsStorage.ListGroupMembers(context.TODO(), groupID)
+		if err != nil {
+			t.Fatalf("list group members has error %s", err.Error())
+		}
+
+		bytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(bytes))
+	})
+}
+
 func TestStoragePolls(t *testing.T) {
 	var creatorUserID int64 = 1
 
@@ -70,9 +232,17 @@ func TestStoragePolls(t *testing.T) {
 			t.Fatalf("start transaction has error %s", err.Error())
 		}
 
+		slug, _ := slugs.Generate(10)
 		id, err := pollsStorage.CreatePoll(context.TODO(), tx, &storage.Poll{
-			UserID: creatorUserID,
-			Title:  "some poll title",
+			UserID:            creatorUserID,
+			Slug:              slug,
+			Type:              "single",
+			Title:             "some poll title",
+			Description:       "some **poll** description",
+			DescriptionHTML:   "<p>some <strong>poll</strong> description</p>",
+			Visibility:        "public",
+			ResultsVisibility: "always",
+			PublishedAt:       sql.NullTime{Valid: true, Time: time.Now()},
 		})
 		if err != nil {
 			t.Fatalf("create poll has error %s", err.Error())
@@ -83,7 +253,7 @@ func TestStoragePolls(t *testing.T) {
 	})
 
 	t.Run("list_polls", func(t *testing.T) {
-		polls, err := pollsStorage.ListPollsByTag(context.TODO(), creatorUserID, 0, 0, 0)
+		polls, err := pollsStorage.ListPollsByTag(context.TODO(), creatorUserID, 0, "", "poll", 0, 0)
 		if err != nil {
 			t.Fatalf("list polls has error %s", err.Error())
 		}
@@ -91,6 +261,31 @@ func TestStoragePolls(t *testing.T) {
 		bytes, _ := json.MarshalIndent(polls, "", "  ")
 		fmt.Println(string(bytes))
 	})
+
+	t.Run("get_attachments_by_ids", func(t *testing.T) {
+		attachments, err := pollsStorage.GetAttachmentsByIDs(context.TODO(), []int64{1, 2})
+		if err != nil {
+			t.Fatalf("get attachments has error %s", err.Error())
+		}
+
+		bytes, _ := json.MarshalIndent(attachments, "", "  ")
+		fmt.Println(string(bytes))
+	})
+
+	t.Run("publish_due_polls", func(t *testing.T) {
+		tx, err := pollsStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		polls, err := pollsStorage.PublishDuePolls(context.TODO(), tx, 10)
+		if err != nil {
+			t.Fatalf("publish due polls has error %s", err.Error())
+		}
+
+		tx.Commit()
+		fmt.Println(len(polls))
+	})
 }
 
 func TestStorageTags(t *testing.T) {
@@ -139,7 +334,12 @@ func TestStorageVotes(t *testing.T) {
 
 	t.Run("create_vote", func(t *testing.T) {
 		t.Run("with_option", func(t *testing.T) {
-			err = votesStorage.CreateVote(context.TODO(), &storage.Vote{
+			tx, err := votesStorage.StartTransaction(context.TODO())
+			if err != nil {
+				t.Fatalf("start transaction has error %s", err.Error())
+			}
+
+			err = votesStorage.CreateVote(context.TODO(), tx, &storage.Vote{
 				UserID:   voterUserID,
 				PollID:   pollID,
 				OptionID: sql.NullInt64{Valid: true, Int64: 2},
@@ -147,17 +347,26 @@ func TestStorageVotes(t *testing.T) {
 			if err != nil {
 				t.Fatalf("create vote has error %s", err.Error())
 			}
+
+			tx.Commit()
 		})
 
 		t.Run("no_option", func(t *testing.T) {
-			err = votesStorage.CreateVote(context.TODO(), &storage.Vote{
-				UserID:   voterUserID,
-				PollID:   pollID,
-				OptionID: sql.NullInt64{Valid: false},
+			tx, err := votesStorage.StartTransaction(context.TODO())
+			if err != nil {
+				t.Fatalf("start transaction has error %s", err.Error())
+			}
+
+			err = votesStorage.CreateVote(context.TODO(), tx, &storage.Vote{
+				UserID:  voterUserID,
+				PollID:  pollID,
+				Skipped: true,
 			})
 			if err != nil {
 				t.Fatalf("create vote has error %s", err.Error())
 			}
+
+			tx.Commit()
 		})
 
 	})
@@ -170,6 +379,14 @@ func TestStorageVotes(t *testing.T) {
 		fmt.Println(result)
 	})
 
+	t.Run("has_user_acted", func(t *testing.T) {
+		result, err := votesStorage.HasUserActed(context.TODO(), voterUserID, pollID)
+		if err != nil {
+			t.Fatalf("has user acted has error %s", err.Error())
+		}
+		fmt.Println(result)
+	})
+
 	t.Run("get_current_date_user_vote_count", func(t *testing.T) {
 		result, err := votesStorage.GetCurrentDateUserVoteCount(context.TODO(), voterUserID)
 		if err != nil {
@@ -177,4 +394,246 @@ func TestStorageVotes(t *testing.T) {
 		}
 		fmt.Println(result)
 	})
+
+	t.Run("get_poll_values_distribution", func(t *testing.T) {
+		result, err := votesStorage.GetPollValuesDistribution(context.TODO(), pollID)
+		if err != nil {
+			t.Fatalf("get poll values distribution has error %s", err.Error())
+		}
+		fmt.Println(result)
+	})
+
+	t.Run("get_quiz_leaderboard", func(t *testing.T) {
+		result, err := votesStorage.GetQuizLeaderboard(context.TODO(), 0, "functional-test", 10, 0)
+		if err != nil {
+			t.Fatalf("get quiz leaderboard has error %s", err.Error())
+		}
+		fmt.Println(result)
+	})
+
+	t.Run("list_other_answers", func(t *testing.T) {
+		result, err := votesStorage.ListOtherAnswers(context.TODO(), pollID, "", 10, 0)
+		if err != nil {
+			t.Fatalf("list other answers has error %s", err.Error())
+		}
+		fmt.Println(result)
+	})
+
+	t.Run("get_frequent_other_answers", func(t *testing.T) {
+		result, err := votesStorage.GetFrequentOtherAnswers(context.TODO(), pollID, 10)
+		if err != nil {
+			t.Fatalf("get frequent other answers has error %s", err.Error())
+		}
+		fmt.Println(result)
+	})
+
+	t.Run("get_schedule_availabilities", func(t *testing.T) {
+		result, err := votesStorage.GetScheduleAvailabilities(context.TODO(), pollID)
+		if err != nil {
+			t.Fatalf("get schedule availabilities has error %s", err.Error())
+		}
+		fmt.Println(result)
+	})
+}
+
+func TestStorageAuditEvents(t *testing.T) {
+	var actorUserID int64 = 1
+
+	t.Run("create_audit_event", func(t *testing.T) {
+		err = auditStorage.CreateAuditEvent(context.TODO(), nil, &storage.AuditEvent{
+			ActorID:    actorUserID,
+			Action:     "poll.create",
+			TargetType: "poll",
+			TargetID:   3,
+			RequestID:  "functional-test",
+		})
+		if err != nil {
+			t.Fatalf("create audit event has error %s", err.Error())
+		}
+	})
+
+	t.Run("list_audit_events", func(t *testing.T) {
+		result, err := auditStorage.ListAuditEvents(context.TODO(), &storage.AuditEventFilter{ActorID: actorUserID}, 10, 0)
+		if err != nil {
+			t.Fatalf("list audit events has error %s", err.Error())
+		}
+
+		bytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(bytes))
+	})
+
+	t.Run("list_audit_events_keyset", func(t *testing.T) {
+		first, err := auditStorage.ListAuditEvents(context.TODO(), &storage.AuditEventFilter{ActorID: actorUserID}, 1, 0)
+		if err != nil || len(first) == 0 {
+			t.Fatalf("list audit events has error %v", err)
+		}
+
+		result, err := auditStorage.ListAuditEvents(context.TODO(), &storage.AuditEventFilter{
+			ActorID:         actorUserID,
+			BeforeCreatedAt: sql.NullTime{Time: first[0].CreatedAt, Valid: true},
+			BeforeID:        first[0].ID,
+		}, 10, 0)
+		if err != nil {
+			t.Fatalf("list audit events has error %s", err.Error())
+		}
+
+		for _, event := range result {
+			if event.ID == first[0].ID {
+				t.Fatalf("the keyset listing repeats the event %d", event.ID)
+			}
+		}
+	})
+}
+
+func TestStorageAnonymousVotes(t *testing.T) {
+	var voterUserID int64 = 2
+	var pollID int64 = 3
+	var voterHash string = "functional-test-voter-hash"
+
+	t.Run("create_anonymous_ballot", func(t *testing.T) {
+		tx, err := votesStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		err = votesStorage.CreateAnonymousBallot(context.TODO(), tx, &storage.AnonymousBallot{
+			PollID:    pollID,
+			UserID:    voterUserID,
+			VoterHash: voterHash,
+		})
+		if err != nil {
+			t.Fatalf("create anonymous ballot has error %s", err.Error())
+		}
+
+		tx.Commit()
+	})
+
+	t.Run("increment_anonymous_tally", func(t *testing.T) {
+		err := votesStorage.IncrementAnonymousTally(context.TODO(), pollID, 2)
+		if err != nil {
+			t.Fatalf("increment anonymous tally has error %s", err.Error())
+		}
+	})
+
+	t.Run("has_anonymous_ballot", func(t *testing.T) {
+		result, err := votesStorage.HasAnonymousBallot(context.TODO(), pollID, voterHash)
+		if err != nil {
+			t.Fatalf("has anonymous ballot has error %s", err.Error())
+		}
+		fmt.Println(result)
+	})
+
+	t.Run("get_anonymous_tallies", func(t *testing.T) {
+		result, err := votesStorage.GetAnonymousTallies(context.TODO(), pollID)
+		if err != nil {
+			t.Fatalf("get anonymous tallies has error %s", err.Error())
+		}
+
+		bytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(bytes))
+	})
+
+	t.Run("revoke_anonymous_ballot", func(t *testing.T) {
+		tx, err := votesStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		err = votesStorage.RevokeAnonymousBallot(context.TODO(), tx, &storage.AnonymousBallot{
+			PollID:    pollID,
+			UserID:    voterUserID,
+			VoterHash: voterHash,
+		})
+		if err != nil {
+			t.Fatalf("revoke anonymous ballot has error %s", err.Error())
+		}
+		tx.Commit()
+
+		result, err := votesStorage.HasAnonymousBallot(context.TODO(), pollID, voterHash)
+		if err != nil {
+			t.Fatalf("has anonymous ballot has error %s", err.Error())
+		} else if result {
+			t.Fatalf("the revoked ballot should be gone")
+		}
+	})
+}
+
+func TestStorageSurveys(t *testing.T) {
+	var creatorUserID int64 = 1
+	var respondentUserID int64 = 2
+	var pollID int64 = 3
+	var surveyID int64
+
+	t.Run("create_survey", func(t *testing.T) {
+		tx, err := surveysStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		surveyID, err = surveysStorage.CreateSurvey(context.TODO(), tx, &storage.Survey{
+			UserID: creatorUserID,
+			Title:  "some survey title",
+		})
+		if err != nil {
+			t.Fatalf("create survey has error %s", err.Error())
+		}
+
+		err = surveysStorage.CreateSurveyQuestions(context.TODO(), tx, surveyID, []storage.SurveyQuestion{
+			{Position: 1, PollID: pollID},
+		})
+		if err != nil {
+			t.Fatalf("create survey questions has error %s", err.Error())
+		}
+
+		err = surveysStorage.CreateSurveyRules(context.TODO(), tx, surveyID, []storage.SurveyRule{
+			{Position: 1, OptionSort: 1, JumpTo: 0},
+		})
+		if err != nil {
+			t.Fatalf("create survey rules has error %s", err.Error())
+		}
+
+		tx.Commit()
+	})
+
+	t.Run("create_survey_response", func(t *testing.T) {
+		tx, err := surveysStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		err = surveysStorage.CreateSurveyResponse(context.TODO(), tx, &storage.SurveyResponse{
+			SurveyID: surveyID,
+			UserID:   respondentUserID,
+			Answers:  []storage.SurveyAnswer{{Position: 1, Answered: true}},
+		})
+		if err != nil {
+			t.Fatalf("create survey response has error %s", err.Error())
+		}
+
+		tx.Commit()
+	})
+
+	t.Run("get_survey_questions_statistics", func(t *testing.T) {
+		result, err := surveysStorage.GetSurveyQuestionsStatistics(context.TODO(), surveyID)
+		if err != nil {
+			t.Fatalf("get survey questions statistics has error %s", err.Error())
+		}
+
+		bytes, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(bytes))
+	})
+
+	t.Run("delete_survey_response", func(t *testing.T) {
+		tx, err := surveysStorage.StartTransaction(context.TODO())
+		if err != nil {
+			t.Fatalf("start transaction has error %s", err.Error())
+		}
+
+		err = surveysStorage.DeleteSurveyResponse(context.TODO(), tx, surveyID, respondentUserID)
+		if err != nil {
+			t.Fatalf("delete survey response has error %s", err.Error())
+		}
+
+		tx.Commit()
+	})
 }
@@ -29,5 +29,12 @@ func TestUsecasePolls(t *testing.T) {
 		if err != nil {
 			t.Fatalf("create poll has error %s", err.Error())
 		}
+
+		result, err := pollsUsecase.GetPoll(context.TODO(), poll.Slug, poll.UserID)
+		if err != nil {
+			t.Fatalf("get poll by slug has error %s", err.Error())
+		} else if result.ID != poll.ID {
+			t.Fatalf("poll resolved by slug %s has id %d, expected %d", poll.Slug, result.ID, poll.ID)
+		}
 	})
 }